@@ -0,0 +1,144 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ingestType           string
+	ingestOutput         string
+	ingestDeleteOriginal bool
+)
+
+// ingestCmd represents the ingest command
+var ingestCmd = &cobra.Command{
+	Use:   "ingest --type cucm|cube|expressway|oracle|uccx|webex|<profile> <path...>",
+	Short: "Parses the given files or directories once and exits",
+	Long: `Parses the given files, or every file in the given directories, once and exits.
+Files are moved to the complete or failed directory exactly like the parse command does.
+The exit code is non-zero if any file failed.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := collectIngestFiles(args)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		config.SetDefaults()
+		logger.InitLogger()
+		db := database.InitDB(*config.GetDatabaseFromGlobalConfig())
+		if err := parser.LoadProfiles(db); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		// Like the types of configured directories, see
+		// config.GetDirectoriesFromGlobalConfig
+		ingestType = strings.ToLower(ingestType)
+		if !parser.SupportedType(ingestType) {
+			fmt.Fprintf(os.Stderr, "Unsupported type: %s (expected cucm, cube, expressway, oracle, uccx, webex or a profile name)\n", ingestType)
+			os.Exit(2)
+		}
+
+		var processed, failed, skipped, duplicates, records, rejected int
+		for _, file := range files {
+			output := ingestOutput
+			if output == "" {
+				// Same layout as a configured directory: complete/ and failed/
+				// are created next to the file
+				output = filepath.Join(filepath.Dir(file), "processed")
+			}
+
+			result := parser.ParseFile(file, config.DirectoryConfig{
+				Input:          filepath.Dir(file),
+				Output:         output,
+				Type:           ingestType,
+				DeleteOriginal: ingestDeleteOriginal,
+			}, db)
+
+			records += result.Records
+			rejected += result.Rejected
+			switch {
+			case result.Failed():
+				failed++
+				fmt.Printf("FAILED  %s: %s\n", result.File, result.Err)
+			case result.Duplicate:
+				duplicates++
+				fmt.Printf("DUPLICATE %s: already ingested\n", result.File)
+			case result.Skipped:
+				skipped++
+				fmt.Printf("SKIPPED %s\n", result.File)
+			default:
+				processed++
+				fmt.Printf("OK      %s: %d records, %d rejected\n", result.File, result.Records, result.Rejected)
+			}
+		}
+
+		fmt.Printf("\nFiles: %d (%d complete, %d failed, %d skipped, %d duplicate)\n", len(files), processed, failed, skipped, duplicates)
+		fmt.Printf("Records written: %d\n", records)
+		fmt.Printf("Records rejected: %d\n", rejected)
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// collectIngestFiles expands the command line arguments into a list of files.
+// Directories are not walked recursively, matching the parse command.
+func collectIngestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	rootCmd.AddCommand(ingestCmd)
+
+	ingestCmd.Flags().StringVar(&ingestType, "type", "", "Type of CDR files (cucm|cube|expressway|oracle|uccx|webex or a profile name)")
+	ingestCmd.Flags().StringVar(&ingestOutput, "output", "", "Output path used to place the complete and failed directories (default is next to each file)")
+	ingestCmd.Flags().BoolVar(&ingestDeleteOriginal, "delete-original", false, "Delete original files after parsing instead of moving them")
+	ingestCmd.MarkFlagRequired("type")
+}
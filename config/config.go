@@ -18,6 +18,7 @@ package config
 
 import (
 	"log"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -26,6 +27,9 @@ type GlobalConfig struct {
 	Database *DatabaseConfig
 	Logging  *LoggingConfig
 	Parser   *ParserConfig
+	Receiver *ReceiverConfig
+	Profiles map[string]ProfileConfig
+	Pollers  *PollerConfig
 }
 
 type DatabaseConfig struct {
@@ -53,6 +57,15 @@ type LoggingConfig struct {
 type ParserConfig struct {
 	Directories   []DirectoryConfig `mapstructure:"directories"`
 	ParseInterval int
+	// Workers is the number of directories parsed at the same time.
+	Workers int
+	// Mode is either "interval" (poll every ParseInterval minutes) or
+	// "watch" (parse files as soon as they are written, and still rescan
+	// every ParseInterval minutes).
+	Mode string
+	// WatchDelay is the number of seconds a file has to stay unmodified in
+	// watch mode before it is parsed.
+	WatchDelay int
 }
 
 type DirectoryConfig struct {
@@ -60,6 +73,142 @@ type DirectoryConfig struct {
 	Output         string `mapstructure:"output"`
 	Type           string `mapstructure:"type"`
 	DeleteOriginal bool   `mapstructure:"deleteOriginal"`
+	// Workers is the number of files in this directory parsed at the same time.
+	Workers int `mapstructure:"workers"`
+	// Username and Password are the login of this directory on the embedded
+	// receivers. Uploads with this login are written into Input.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// MinAge is the number of seconds a file must be unmodified before it is
+	// parsed.
+	MinAge int `mapstructure:"minAge"`
+	// StableSize only parses a file once its size and modification time are
+	// unchanged since the previous scan.
+	StableSize bool `mapstructure:"stableSize"`
+	// Ignore are glob patterns of file names that are never parsed. When
+	// empty, hidden files and common temporary upload names are ignored.
+	Ignore []string `mapstructure:"ignore"`
+	// DoneMarker is a suffix such as ".done". When set, a file is only parsed
+	// once a marker file with that suffix exists next to it.
+	DoneMarker string `mapstructure:"doneMarker"`
+}
+
+// ProfileConfig describes the CSV layout of a CDR format without a built-in
+// parser, such as Asterisk Master.csv or a carrier export. A directory whose
+// type is the name of a profile is parsed with it.
+type ProfileConfig struct {
+	// Name is the key of the profile in the profiles section.
+	Name string `mapstructure:"-"`
+	// Delimiter is the field separator, a comma when empty. "tab" selects a
+	// tab.
+	Delimiter string `mapstructure:"delimiter"`
+	// Header is set when the first row holds the column names. Columns can
+	// then be selected by name.
+	Header bool `mapstructure:"header"`
+	// SkipRows is the number of rows skipped before the header or the first
+	// record, such as the title lines of a carrier export.
+	SkipRows int `mapstructure:"skipRows"`
+	// Timezone is the location of time values without a zone, UTC when
+	// empty.
+	Timezone string `mapstructure:"timezone"`
+	// Table is the destination table of the records.
+	Table   string          `mapstructure:"table"`
+	Columns []ProfileColumn `mapstructure:"columns"`
+}
+
+// ProfileColumn maps a CSV column to a column of the destination table.
+type ProfileColumn struct {
+	// Name is the column in the destination table.
+	Name string `mapstructure:"name"`
+	// Source is the name of the CSV column in the header, Index its position
+	// starting at 1. Only one of them is set.
+	Source string `mapstructure:"source"`
+	Index  int    `mapstructure:"index"`
+	// Type is string, int, float, time or ip, string when empty.
+	Type string `mapstructure:"type"`
+	// Layout is the Go layout of a time column, e.g. "2006-01-02 15:04:05".
+	// "unix" and "unixms" read epoch seconds and milliseconds.
+	Layout string `mapstructure:"layout"`
+}
+
+// PollerConfig lists the systems whose call history is pulled from their API.
+type PollerConfig struct {
+	Expressway []ExpresswayPollerConfig
+}
+
+// ExpresswayPollerConfig is an Expressway or VCS whose call history is polled.
+type ExpresswayPollerConfig struct {
+	// Name is stored as the system of the calls read from this Expressway.
+	Name string `mapstructure:"name"`
+	// URL returns the call history as JSON or CSV.
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Interval is the number of seconds between two polls.
+	Interval int `mapstructure:"interval"`
+	// InsecureSkipVerify accepts any certificate, for Expressways that still
+	// use their default self-signed certificate.
+	InsecureSkipVerify bool `mapstructure:"insecureSkipVerify"`
+}
+
+type ReceiverConfig struct {
+	SFTP   *SFTPConfig
+	FTP    *FTPConfig
+	Radius *RadiusConfig
+	Syslog *SyslogConfig
+	CMS    *CMSConfig
+}
+
+type SFTPConfig struct {
+	Enabled bool
+	Listen  string
+	// HostKey is the path of the SSH host key, generated on first start.
+	HostKey string
+}
+
+type FTPConfig struct {
+	Enabled bool
+	Listen  string
+	// PassivePorts is the range of ports used for passive data connections,
+	// e.g. "30000-30009".
+	PassivePorts string
+	// PublicIP is announced in PASV replies when go-cdr runs behind NAT.
+	PublicIP string
+}
+
+type RadiusConfig struct {
+	Enabled bool
+	Listen  string
+	Clients []RadiusClient
+}
+
+// RadiusClient is a gateway allowed to send accounting records.
+type RadiusClient struct {
+	// Address is the IP address or CIDR range the client sends from.
+	Address string
+	Secret  string
+	// Name is stored as the hostname of the CDRs of the client. The
+	// NAS-IP-Address is used when it is empty.
+	Name string
+}
+
+// SyslogConfig is the syslog server CUBE gw-accounting syslog sends call
+// history to. It listens on both UDP and TCP.
+type SyslogConfig struct {
+	Enabled bool
+	Listen  string
+}
+
+// CMSConfig is the CDR receiver Cisco Meeting Server posts its call and call
+// leg records to.
+type CMSConfig struct {
+	Enabled bool
+	Listen  string
+	// Path is the path of the receiver URI configured on CMS.
+	Path string
+	// CertFile and KeyFile enable HTTPS when both are set.
+	CertFile string
+	KeyFile  string
 }
 
 func SetDefaults() {
@@ -77,6 +226,26 @@ func SetDefaults() {
 	viper.SetDefault("database.path", "./go-cdr/db/go-cdr.db")
 	viper.SetDefault("database.limit", 100)
 
+	// Set defaults for the ParserConfig
+	viper.SetDefault("parser.workers", 4)
+	viper.SetDefault("parser.mode", "interval")
+	viper.SetDefault("parser.watchDelay", 5)
+
+	// Set defaults for the ReceiverConfig
+	viper.SetDefault("receiver.sftp.enabled", false)
+	viper.SetDefault("receiver.sftp.listen", ":2022")
+	viper.SetDefault("receiver.sftp.hostKey", "./go-cdr/ssh_host_ed25519_key")
+	viper.SetDefault("receiver.ftp.enabled", false)
+	viper.SetDefault("receiver.ftp.listen", ":2121")
+	viper.SetDefault("receiver.ftp.passivePorts", "30000-30009")
+	viper.SetDefault("receiver.radius.enabled", false)
+	viper.SetDefault("receiver.radius.listen", ":1813")
+	viper.SetDefault("receiver.syslog.enabled", false)
+	viper.SetDefault("receiver.syslog.listen", ":5514")
+	viper.SetDefault("receiver.cms.enabled", false)
+	viper.SetDefault("receiver.cms.listen", ":8089")
+	viper.SetDefault("receiver.cms.path", "/cdr")
+
 }
 
 func GetLoggerFromGlobalConfig() *LoggingConfig {
@@ -103,6 +272,9 @@ func GetParserFromGlobalConfig() *ParserConfig {
 	}
 	return &ParserConfig{
 		ParseInterval: parserConfig.GetInt("parseInterval"),
+		Workers:       parserConfig.GetInt("workers"),
+		Mode:          parserConfig.GetString("mode"),
+		WatchDelay:    parserConfig.GetInt("watchDelay"),
 	}
 }
 
@@ -112,9 +284,77 @@ func GetDirectoriesFromGlobalConfig() []DirectoryConfig {
 
 	viper.UnmarshalKey("parser.directories", &directories)
 
+	// Viper lowercases the names of profiles, so types are compared in lower
+	// case
+	for i := range directories {
+		directories[i].Type = strings.ToLower(directories[i].Type)
+	}
+
 	return directories
 }
 
+// GetProfilesFromGlobalConfig returns the CSV profiles by name.
+func GetProfilesFromGlobalConfig() map[string]ProfileConfig {
+	profiles := map[string]ProfileConfig{}
+
+	viper.UnmarshalKey("profiles", &profiles)
+
+	for name, profile := range profiles {
+		profile.Name = name
+		profiles[name] = profile
+	}
+	return profiles
+}
+
+func GetPollersFromGlobalConfig() *PollerConfig {
+	var expressways []ExpresswayPollerConfig
+	viper.UnmarshalKey("pollers.expressway", &expressways)
+
+	for i := range expressways {
+		if expressways[i].Interval <= 0 {
+			expressways[i].Interval = 300
+		}
+	}
+	return &PollerConfig{
+		Expressway: expressways,
+	}
+}
+
+func GetReceiverFromGlobalConfig() *ReceiverConfig {
+	var radiusClients []RadiusClient
+	viper.UnmarshalKey("receiver.radius.clients", &radiusClients)
+
+	return &ReceiverConfig{
+		SFTP: &SFTPConfig{
+			Enabled: viper.GetBool("receiver.sftp.enabled"),
+			Listen:  viper.GetString("receiver.sftp.listen"),
+			HostKey: viper.GetString("receiver.sftp.hostKey"),
+		},
+		FTP: &FTPConfig{
+			Enabled:      viper.GetBool("receiver.ftp.enabled"),
+			Listen:       viper.GetString("receiver.ftp.listen"),
+			PassivePorts: viper.GetString("receiver.ftp.passivePorts"),
+			PublicIP:     viper.GetString("receiver.ftp.publicIP"),
+		},
+		Radius: &RadiusConfig{
+			Enabled: viper.GetBool("receiver.radius.enabled"),
+			Listen:  viper.GetString("receiver.radius.listen"),
+			Clients: radiusClients,
+		},
+		Syslog: &SyslogConfig{
+			Enabled: viper.GetBool("receiver.syslog.enabled"),
+			Listen:  viper.GetString("receiver.syslog.listen"),
+		},
+		CMS: &CMSConfig{
+			Enabled:  viper.GetBool("receiver.cms.enabled"),
+			Listen:   viper.GetString("receiver.cms.listen"),
+			Path:     viper.GetString("receiver.cms.path"),
+			CertFile: viper.GetString("receiver.cms.certFile"),
+			KeyFile:  viper.GetString("receiver.cms.keyFile"),
+		},
+	}
+}
+
 func GetDatabaseFromGlobalConfig() *DatabaseConfig {
 	databaseConfig := viper.Sub("database")
 	if databaseConfig == nil {
@@ -20,7 +20,10 @@ import (
 
 	"github.com/eds-ch/Go-CDR-V/config"
 	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
 	"github.com/eds-ch/Go-CDR-V/parser"
+	"github.com/eds-ch/Go-CDR-V/poller"
+	"github.com/eds-ch/Go-CDR-V/receiver"
 	"github.com/go-co-op/gocron"
 )
 
@@ -28,16 +31,36 @@ func RunCronJobs() {
 
 	dbConfig := config.GetDatabaseFromGlobalConfig()
 	db := database.InitDB(*dbConfig)
+	if err := parser.LoadProfiles(db); err != nil {
+		logger.Fatal("Error loading profiles: %s", err)
+	}
 	s := gocron.NewScheduler(time.UTC)
 
-	parseInterval := config.GetParserFromGlobalConfig().ParseInterval
+	parserConfig := config.GetParserFromGlobalConfig()
 
 	parseDirectories := config.GetDirectoriesFromGlobalConfig()
+	if err := parser.ValidateDirectories(parseDirectories); err != nil {
+		logger.Fatal("Error in directory configuration: %s", err)
+	}
 
-	s.Every(parseInterval).Minutes().Do(func() {
-		for _, directory := range parseDirectories {
-			parser.ParseFiles(directory.Input, directory.Output, directory.Type, directory.DeleteOriginal, db)
+	receiver.Start(config.GetReceiverFromGlobalConfig(), parseDirectories, db)
+	poller.Start(config.GetPollersFromGlobalConfig(), db)
+
+	// A run that takes longer than the interval must not overlap the next
+	// one, otherwise the same files would be picked up twice.
+	s.SingletonModeAll()
+
+	if parserConfig.Mode == "watch" {
+		delay := time.Duration(parserConfig.WatchDelay) * time.Second
+		if err := parser.WatchDirectories(parseDirectories, delay, db); err != nil {
+			logger.Error("Error starting directory watcher, falling back to interval mode: %s", err)
 		}
+	}
+
+	// In watch mode the interval job is a rescan that catches files missed
+	// while the process was down or that the watcher did not report.
+	s.Every(parserConfig.ParseInterval).Minutes().Do(func() {
+		parser.ParseDirectories(parseDirectories, parserConfig.Workers, db)
 	})
 
 	s.StartBlocking()
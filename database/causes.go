@@ -0,0 +1,76 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"gorm.io/gorm"
+)
+
+// lookupTable is a table of code descriptions that is filled by go-cdr, so
+// reports can join the codes stored in the record tables to their names.
+type lookupTable struct {
+	name string
+	// ddl is the ClickHouse column list
+	ddl  string
+	rows func() interface{}
+}
+
+var lookupTables = []lookupTable{
+	{"q850_causes", "code Int64, hex String, description String, category String", func() interface{} { return models.Q850Causes() }},
+	{"cucm_redirect_reasons", "code Int64, description String", func() interface{} { return models.CucmRedirectReasons }},
+	{"cucm_on_behalf_of_codes", "code Int64, description String", func() interface{} { return models.CucmOnBehalfOfCodes }},
+}
+
+// seedLookupTables replaces the rows of the lookup tables with the codes
+// built into go-cdr. databaseName is only set on ClickHouse, where the tables
+// are created here.
+func seedLookupTables(db *gorm.DB, databaseName string) {
+	for _, table := range lookupTables {
+		logger.Info("Filling table %s...\n", table.name)
+
+		var err error
+		if databaseName != "" {
+			err = seedClickHouseLookupTable(db, databaseName, table)
+		} else {
+			err = db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(fmt.Sprintf("DELETE FROM %s", table.name)).Error; err != nil {
+					return err
+				}
+				return tx.Table(table.name).Create(table.rows()).Error
+			})
+		}
+		if err != nil {
+			logger.Error("Failed to fill table %s: %s\n", table.name, err)
+			return
+		}
+	}
+}
+
+func seedClickHouseLookupTable(db *gorm.DB, databaseName string, table lookupTable) error {
+	tableName := fmt.Sprintf("%s.%s", databaseName, table.name)
+	createQuery := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = MergeTree() ORDER BY (code)", tableName, table.ddl)
+	if err := db.Exec(createQuery).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s", tableName)).Error; err != nil {
+		return err
+	}
+	return db.Table(tableName).Create(table.rows()).Error
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+	"gorm.io/gorm"
+)
+
+// GetCMSCall returns a CMS call, or nil if it is not known.
+func (ds *DataService) GetCMSCall(id string) (*models.CMSCall, error) {
+	var calls []models.CMSCall
+	if err := ds.cmsSession("cms_calls").Where("id = ?", id).Limit(1).Find(&calls).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CMS call: %w", err)
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	return &calls[0], nil
+}
+
+// GetCMSCallLeg returns a CMS call leg, or nil if it is not known.
+func (ds *DataService) GetCMSCallLeg(id string) (*models.CMSCallLeg, error) {
+	var legs []models.CMSCallLeg
+	if err := ds.cmsSession("cms_call_legs").Where("id = ?", id).Limit(1).Find(&legs).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CMS call leg: %w", err)
+	}
+	if len(legs) == 0 {
+		return nil, nil
+	}
+	return &legs[0], nil
+}
+
+// SaveCMSCalls creates or updates CMS calls.
+func (ds *DataService) SaveCMSCalls(calls []*models.CMSCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	updatedAt := time.Now().UnixNano()
+	for _, call := range calls {
+		call.UpdatedAt = updatedAt
+	}
+
+	if err := ds.saveCMS("cms_calls", calls); err != nil {
+		return fmt.Errorf("failed to write CMS calls: %w", err)
+	}
+	return nil
+}
+
+// SaveCMSCallLegs creates or updates CMS call legs.
+func (ds *DataService) SaveCMSCallLegs(legs []*models.CMSCallLeg) error {
+	if len(legs) == 0 {
+		return nil
+	}
+	updatedAt := time.Now().UnixNano()
+	for _, leg := range legs {
+		leg.UpdatedAt = updatedAt
+	}
+
+	if err := ds.saveCMS("cms_call_legs", legs); err != nil {
+		return fmt.Errorf("failed to write CMS call legs: %w", err)
+	}
+	return nil
+}
+
+// cmsSession returns a session reading the newest version of the rows of a
+// CMS table.
+func (ds *DataService) cmsSession(table string) *gorm.DB {
+	if ds.Config.Driver == "clickhouse" {
+		// Every update is a new version of the row, the newest one wins
+		return ds.Session.Table(fmt.Sprintf("%s.%s", ds.Config.Database, table)).Order("updated_at DESC")
+	}
+	return ds.Session.Table(table)
+}
+
+func (ds *DataService) saveCMS(table string, rows interface{}) error {
+	if ds.Config.Driver == "clickhouse" {
+		tableName := fmt.Sprintf("%s.%s", ds.Config.Database, table)
+		return ds.Session.Table(tableName).CreateInBatches(rows, ds.BatchSize()).Error
+	}
+	return ds.Session.Save(rows).Error
+}
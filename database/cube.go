@@ -24,23 +24,19 @@ import (
 )
 
 func (ds DataService) CreateCubeCDRs(cdrs []*models.CubeCDR) error {
-	cdrValues := make([]models.CubeCDR, len(cdrs))
-	for i, cdr := range cdrs {
-		if cdr != nil {
-			cdrValues[i] = *cdr
-		}
-	}
-
-	return ds.WriteCubeCDRs(cdrValues)
+	return ds.WriteCubeCDRs(cdrs)
 }
 
-func (ds *DataService) WriteCubeCDRs(cdrs []models.CubeCDR) error {
+func (ds *DataService) WriteCubeCDRs(cdrs []*models.CubeCDR) error {
 	if len(cdrs) == 0 {
 		return nil
 	}
 
 	if ds.Session.Dialector.Name() == "clickhouse" {
-		return SaveCubeCDRsToClickHouse(cdrs, ds.Session, ds.Config.Database)
+		if err := SaveCubeCDRsToClickHouse(cdrs, ds.Session, ds.Config.Database); err != nil {
+			return err
+		}
+		return ds.writeCubeFeatureEvents(cdrs)
 	}
 
 	limit := int(ds.Config.Limit)
@@ -51,10 +47,39 @@ func (ds *DataService) WriteCubeCDRs(cdrs []models.CubeCDR) error {
 		return fmt.Errorf("failed to write CUBE CDRs: %w", err)
 	}
 
+	return ds.writeCubeFeatureEvents(cdrs)
+}
+
+// writeCubeFeatureEvents writes the feature events of CDRs that were written,
+// tagged with the ingested file of their CDR.
+func (ds *DataService) writeCubeFeatureEvents(cdrs []*models.CubeCDR) error {
+	var events []*models.CubeFeatureEvent
+	for _, cdr := range cdrs {
+		for _, event := range cdr.FeatureEvents {
+			event.IngestedFileId = cdr.IngestedFileId
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	session := ds.Session
+	if ds.Session.Dialector.Name() == "clickhouse" {
+		session = session.Table(fmt.Sprintf("%s.cube_feature_events", ds.Config.Database))
+	}
+	if err := session.CreateInBatches(events, limit).Error; err != nil {
+		return fmt.Errorf("failed to write CUBE feature events: %w", err)
+	}
+
 	return nil
 }
 
-func SaveCubeCDRsToClickHouse(cdrs []models.CubeCDR, db *gorm.DB, databaseName string) error {
+func SaveCubeCDRsToClickHouse(cdrs []*models.CubeCDR, db *gorm.DB, databaseName string) error {
 	if len(cdrs) == 0 {
 		return nil
 	}
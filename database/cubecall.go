@@ -0,0 +1,109 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+	"gorm.io/gorm"
+)
+
+// GetCubeCDRsByConfID returns the CUBE CDRs with one of the given
+// h323_conf_id values. Callers filter them by hostname.
+func (ds *DataService) GetCubeCDRsByConfID(confIDs []string) ([]*models.CubeCDR, error) {
+	var cdrs []*models.CubeCDR
+	for _, chunk := range chunks(confIDs) {
+		var found []*models.CubeCDR
+		if err := ds.Session.Table(ds.tableName("cube_cdrs")).Where("h323_conf_id IN ?", chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUBE CDRs: %w", err)
+		}
+		cdrs = append(cdrs, found...)
+	}
+	return cdrs, nil
+}
+
+// GetCubeCallKeys returns the keys of the calls of the CUBE CDRs recorded in
+// a time range, in Unix seconds. until is exclusive, and 0 means no end.
+func (ds *DataService) GetCubeCallKeys(since int64, until int64) ([]models.CubeCallKey, error) {
+	session := ds.Session.Table(ds.tableName("cube_cdrs")).
+		Select("DISTINCT hostname, h323_conf_id").
+		Where("record_timestamp >= ?", since)
+	if until > 0 {
+		session = session.Where("record_timestamp < ?", until)
+	}
+
+	var cdrs []*models.CubeCDR
+	if err := session.Find(&cdrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CUBE calls: %w", err)
+	}
+
+	var keys []models.CubeCallKey
+	for _, cdr := range cdrs {
+		if key, ok := cdr.CallKey(); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// ReplaceCubeCalls deletes the calls with the given IDs and writes the new
+// calls in their place.
+func (ds *DataService) ReplaceCubeCalls(ids []string, calls []*models.CubeCall) error {
+	if ds.Config.Driver == "clickhouse" {
+		if err := ds.deleteCubeCalls(ds.Session, ids); err != nil {
+			return err
+		}
+		return ds.createCubeCalls(ds.Session, calls)
+	}
+
+	return ds.Session.Transaction(func(tx *gorm.DB) error {
+		if err := ds.deleteCubeCalls(tx, ids); err != nil {
+			return err
+		}
+		return ds.createCubeCalls(tx, calls)
+	})
+}
+
+func (ds *DataService) deleteCubeCalls(db *gorm.DB, ids []string) error {
+	for _, chunk := range chunks(ids) {
+		var err error
+		if ds.Config.Driver == "clickhouse" {
+			query := fmt.Sprintf("ALTER TABLE %s.cube_calls DELETE WHERE id IN ? SETTINGS mutations_sync = 1", ds.Config.Database)
+			err = db.Exec(query, chunk).Error
+		} else {
+			err = db.Exec("DELETE FROM cube_calls WHERE id IN ?", chunk).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete cube_calls: %w", err)
+		}
+	}
+	return nil
+}
+
+func (ds *DataService) createCubeCalls(db *gorm.DB, calls []*models.CubeCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := db.Table(ds.tableName("cube_calls")).CreateInBatches(calls, limit).Error; err != nil {
+		return fmt.Errorf("failed to write CUBE calls: %w", err)
+	}
+	return nil
+}
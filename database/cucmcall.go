@@ -0,0 +1,198 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+	"gorm.io/gorm"
+)
+
+// queryChunkSize is the largest number of values passed to a single IN
+// condition.
+const queryChunkSize = 500
+
+// tableName returns the name of a table for the driver.
+func (ds *DataService) tableName(table string) string {
+	if ds.Config.Driver == "clickhouse" {
+		return fmt.Sprintf("%s.%s", ds.Config.Database, table)
+	}
+	return table
+}
+
+// chunks splits values for IN conditions of at most queryChunkSize values.
+func chunks[T any](values []T) [][]T {
+	var result [][]T
+	for start := 0; start < len(values); start += queryChunkSize {
+		end := start + queryChunkSize
+		if end > len(values) {
+			end = len(values)
+		}
+		result = append(result, values[start:end])
+	}
+	return result
+}
+
+// GetCucmCDRsByCallID returns the CUCM CDRs with one of the given
+// globalcallid_callid values. Callers filter them by cluster and call manager.
+func (ds *DataService) GetCucmCDRsByCallID(callIDs []int64) ([]*models.CucmCdr, error) {
+	var cdrs []*models.CucmCdr
+	for _, chunk := range chunks(callIDs) {
+		var found []*models.CucmCdr
+		if err := ds.Session.Table(ds.tableName("cucm_cdrs")).Where("globalcallid_callid IN ?", chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM CDRs: %w", err)
+		}
+		cdrs = append(cdrs, found...)
+	}
+	return cdrs, nil
+}
+
+// GetCucmCMRsByCallID returns the CUCM CMRs with one of the given
+// globalcallid_callid values. Callers filter them by cluster and call manager.
+func (ds *DataService) GetCucmCMRsByCallID(callIDs []int64) ([]*models.CucmCmr, error) {
+	var cmrs []*models.CucmCmr
+	for _, chunk := range chunks(callIDs) {
+		var found []*models.CucmCmr
+		if err := ds.Session.Table(ds.tableName("cucm_cmrs")).Where("globalcallid_callid IN ?", chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM CMRs: %w", err)
+		}
+		cmrs = append(cmrs, found...)
+	}
+	return cmrs, nil
+}
+
+// GetCucmCDRsByConversation returns the CUCM CDRs whose leg identifiers or
+// conversation IDs are one of the given values.
+func (ds *DataService) GetCucmCDRsByConversation(ids []int64) ([]*models.CucmCdr, error) {
+	var cdrs []*models.CucmCdr
+	for _, chunk := range chunks(ids) {
+		var found []*models.CucmCdr
+		query := "origlegcallidentifier IN ? OR destlegcallidentifier IN ? OR origconversationid IN ? OR destconversationid IN ?"
+		if err := ds.Session.Table(ds.tableName("cucm_cdrs")).Where(query, chunk, chunk, chunk, chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM CDRs: %w", err)
+		}
+		cdrs = append(cdrs, found...)
+	}
+	return cdrs, nil
+}
+
+// GetCucmGlobalCallIDs returns the Global Call IDs of the CUCM CDRs that
+// started in a time range, in Unix seconds. until is exclusive, and 0 means
+// no end.
+func (ds *DataService) GetCucmGlobalCallIDs(since int64, until int64) ([]models.CucmGlobalCallID, error) {
+	session := ds.Session.Table(ds.tableName("cucm_cdrs")).
+		Select("DISTINCT globalcallid_clusterid, file_cluster_id, globalcallid_callmanagerid, globalcallid_callid").
+		Where("datetimeorigination >= ?", since)
+	if until > 0 {
+		session = session.Where("datetimeorigination < ?", until)
+	}
+
+	var cdrs []*models.CucmCdr
+	if err := session.Find(&cdrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CUCM Global Call IDs: %w", err)
+	}
+
+	seen := make(map[string]bool, len(cdrs))
+	var ids []models.CucmGlobalCallID
+	for _, cdr := range cdrs {
+		id := cdr.GlobalCallID()
+		if !seen[id.Key()] {
+			seen[id.Key()] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// GetCucmCallLegs returns the legs of the calls that contain one of the given
+// Global Call ID keys, and every other leg of those calls.
+func (ds *DataService) GetCucmCallLegs(keys []string) ([]models.CucmCallLeg, error) {
+	var callIDs []string
+	for _, chunk := range chunks(keys) {
+		var found []string
+		if err := ds.Session.Table(ds.tableName("cucm_call_legs")).Distinct("call_id").Where("globalcallid IN ?", chunk).Pluck("call_id", &found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM call legs: %w", err)
+		}
+		callIDs = append(callIDs, found...)
+	}
+
+	var legs []models.CucmCallLeg
+	for _, chunk := range chunks(callIDs) {
+		var found []models.CucmCallLeg
+		if err := ds.Session.Table(ds.tableName("cucm_call_legs")).Where("call_id IN ?", chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM call legs: %w", err)
+		}
+		legs = append(legs, found...)
+	}
+	return legs, nil
+}
+
+// ReplaceCucmCalls deletes the calls with the old IDs and their legs, and
+// writes the new calls and legs in their place.
+func (ds *DataService) ReplaceCucmCalls(oldIDs []string, calls []*models.CucmCall, legs []*models.CucmCallLeg) error {
+	if ds.Config.Driver == "clickhouse" {
+		// ClickHouse has no transactions, the deletes are synchronous so the
+		// new rows are never deleted with the old ones
+		if err := ds.deleteCucmCalls(ds.Session, oldIDs); err != nil {
+			return err
+		}
+		return ds.createCucmCalls(ds.Session, calls, legs)
+	}
+
+	return ds.Session.Transaction(func(tx *gorm.DB) error {
+		if err := ds.deleteCucmCalls(tx, oldIDs); err != nil {
+			return err
+		}
+		return ds.createCucmCalls(tx, calls, legs)
+	})
+}
+
+func (ds *DataService) deleteCucmCalls(db *gorm.DB, ids []string) error {
+	for _, chunk := range chunks(ids) {
+		for _, target := range []struct{ table, column string }{{"cucm_call_legs", "call_id"}, {"cucm_calls", "id"}} {
+			var err error
+			if ds.Config.Driver == "clickhouse" {
+				query := fmt.Sprintf("ALTER TABLE %s DELETE WHERE %s IN ? SETTINGS mutations_sync = 1", ds.tableName(target.table), target.column)
+				err = db.Exec(query, chunk).Error
+			} else {
+				err = db.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", target.table, target.column), chunk).Error
+			}
+			if err != nil {
+				return fmt.Errorf("failed to delete %s: %w", target.table, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ds *DataService) createCucmCalls(db *gorm.DB, calls []*models.CucmCall, legs []*models.CucmCallLeg) error {
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if len(calls) > 0 {
+		if err := db.Table(ds.tableName("cucm_calls")).CreateInBatches(calls, limit).Error; err != nil {
+			return fmt.Errorf("failed to write CUCM calls: %w", err)
+		}
+	}
+	if len(legs) > 0 {
+		if err := db.Table(ds.tableName("cucm_call_legs")).CreateInBatches(legs, limit).Error; err != nil {
+			return fmt.Errorf("failed to write CUCM call legs: %w", err)
+		}
+	}
+	return nil
+}
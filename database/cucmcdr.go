@@ -18,12 +18,5 @@ package database
 import "github.com/eds-ch/Go-CDR-V/models"
 
 func (ds DataService) CreateCucmCDRs(cdrs []*models.CucmCdr) error {
-	cdrValues := make([]models.CucmCdr, len(cdrs))
-	for i, cdr := range cdrs {
-		if cdr != nil {
-			cdrValues[i] = *cdr
-		}
-	}
-
-	return ds.WriteCDRs(cdrValues)
+	return ds.WriteCDRs(cdrs)
 }
@@ -23,17 +23,10 @@ import (
 )
 
 func (ds DataService) CreateCucmCMRs(cdrs []*models.CucmCmr) error {
-	cdrValues := make([]models.CucmCmr, len(cdrs))
-	for i, cdr := range cdrs {
-		if cdr != nil {
-			cdrValues[i] = *cdr
-		}
-	}
-
-	return ds.WriteCMRs(cdrValues)
+	return ds.WriteCMRs(cdrs)
 }
 
-func (ds *DataService) WriteCMRs(cdrs []models.CucmCmr) error {
+func (ds *DataService) WriteCMRs(cdrs []*models.CucmCmr) error {
 	if len(cdrs) == 0 {
 		return nil
 	}
@@ -53,7 +46,7 @@ func (ds *DataService) WriteCMRs(cdrs []models.CucmCmr) error {
 	return nil
 }
 
-func (ds *DataService) writeClickHouseCMRs(cdrs []models.CucmCmr) error {
+func (ds *DataService) writeClickHouseCMRs(cdrs []*models.CucmCmr) error {
 	batchSize := int(ds.Config.Limit)
 	if batchSize <= 0 {
 		batchSize = 5000
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+	"gorm.io/gorm"
+)
+
+// GetCubeCallsByConfID returns the CUBE calls with one of the given
+// h323_conf_id values.
+func (ds *DataService) GetCubeCallsByConfID(confIDs []string) ([]*models.CubeCall, error) {
+	var calls []*models.CubeCall
+	for _, chunk := range chunks(confIDs) {
+		var found []*models.CubeCall
+		if err := ds.Session.Table(ds.tableName("cube_calls")).Where("h323_conf_id IN ?", chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUBE calls: %w", err)
+		}
+		calls = append(calls, found...)
+	}
+	return calls, nil
+}
+
+// GetCubeCallsByDisconnectTime returns the CUBE calls that ended in a time
+// range, in Unix seconds, including both ends.
+func (ds *DataService) GetCubeCallsByDisconnectTime(since int64, until int64) ([]*models.CubeCall, error) {
+	var calls []*models.CubeCall
+	if err := ds.Session.Table(ds.tableName("cube_calls")).Where("disconnect_time BETWEEN ? AND ?", since, until).Find(&calls).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CUBE calls: %w", err)
+	}
+	return calls, nil
+}
+
+// GetCucmCDRsByDisconnectTime returns the CUCM CDRs that ended in a time
+// range, in Unix seconds, including both ends.
+func (ds *DataService) GetCucmCDRsByDisconnectTime(since int64, until int64) ([]*models.CucmCdr, error) {
+	var cdrs []*models.CucmCdr
+	if err := ds.Session.Table(ds.tableName("cucm_cdrs")).Where("datetimedisconnect BETWEEN ? AND ?", since, until).Find(&cdrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to read CUCM CDRs: %w", err)
+	}
+	return cdrs, nil
+}
+
+// GetCucmCDRsByProtocolCallRef returns the CUCM CDRs whose incoming or
+// outgoing protocol call reference is one of the given values.
+func (ds *DataService) GetCucmCDRsByProtocolCallRef(refs []string) ([]*models.CucmCdr, error) {
+	var cdrs []*models.CucmCdr
+	for _, chunk := range chunks(refs) {
+		var found []*models.CucmCdr
+		if err := ds.Session.Table(ds.tableName("cucm_cdrs")).Where("incomingprotocolcallref IN ? OR outgoingprotocolcallref IN ?", chunk, chunk).Find(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to read CUCM CDRs: %w", err)
+		}
+		cdrs = append(cdrs, found...)
+	}
+	return cdrs, nil
+}
+
+// ReplaceCucmCubeLinks deletes the links of the CUCM CDRs with the given IDs
+// and writes the new links in their place.
+func (ds *DataService) ReplaceCucmCubeLinks(cdrIDs []string, links []*models.CucmCubeLink) error {
+	if ds.Config.Driver == "clickhouse" {
+		if err := ds.deleteCucmCubeLinks(ds.Session, cdrIDs); err != nil {
+			return err
+		}
+		return ds.createCucmCubeLinks(ds.Session, links)
+	}
+
+	return ds.Session.Transaction(func(tx *gorm.DB) error {
+		if err := ds.deleteCucmCubeLinks(tx, cdrIDs); err != nil {
+			return err
+		}
+		return ds.createCucmCubeLinks(tx, links)
+	})
+}
+
+func (ds *DataService) deleteCucmCubeLinks(db *gorm.DB, cdrIDs []string) error {
+	for _, chunk := range chunks(cdrIDs) {
+		var err error
+		if ds.Config.Driver == "clickhouse" {
+			query := fmt.Sprintf("ALTER TABLE %s.cucm_cube_links DELETE WHERE cucm_cdr_id IN ? SETTINGS mutations_sync = 1", ds.Config.Database)
+			err = db.Exec(query, chunk).Error
+		} else {
+			err = db.Exec("DELETE FROM cucm_cube_links WHERE cucm_cdr_id IN ?", chunk).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete cucm_cube_links: %w", err)
+		}
+	}
+	return nil
+}
+
+func (ds *DataService) createCucmCubeLinks(db *gorm.DB, links []*models.CucmCubeLink) error {
+	if len(links) == 0 {
+		return nil
+	}
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := db.Table(ds.tableName("cucm_cube_links")).CreateInBatches(links, limit).Error; err != nil {
+		return fmt.Errorf("failed to write CUCM CUBE links: %w", err)
+	}
+	return nil
+}
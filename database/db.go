@@ -51,7 +51,7 @@ func InitDB(dbConfig config.DatabaseConfig) *DataService {
 		}
 		logger.Info("Connected to MySQL database.\n")
 		if dbConfig.AutoMigrate {
-			db.AutoMigrate(&models.CucmCdr{}, &models.CubeCDR{}, &models.CucmCmr{})
+			autoMigrate(db)
 		}
 		return &DataService{Session: db, Config: dbConfig}
 
@@ -66,7 +66,7 @@ func InitDB(dbConfig config.DatabaseConfig) *DataService {
 		}
 		logger.Info("Connected to PostgreSQL database.\n")
 		if dbConfig.AutoMigrate {
-			db.AutoMigrate(&models.CucmCdr{}, &models.CubeCDR{}, &models.CucmCmr{})
+			autoMigrate(db)
 		}
 		return &DataService{Session: db, Config: dbConfig}
 
@@ -81,7 +81,7 @@ func InitDB(dbConfig config.DatabaseConfig) *DataService {
 		}
 		logger.Info("Connected to SQL Server database.\n")
 		if dbConfig.AutoMigrate {
-			db.AutoMigrate(&models.CucmCdr{}, &models.CubeCDR{}, &models.CucmCmr{})
+			autoMigrate(db)
 		}
 		return &DataService{Session: db, Config: dbConfig}
 
@@ -135,9 +135,17 @@ func InitDB(dbConfig config.DatabaseConfig) *DataService {
 		if err != nil {
 			logger.Fatal("Database Connection Error: %s\n", err)
 		}
+		// SQLite allows a single writer, so concurrent parser workers share
+		// one connection instead of failing with "database is locked".
+		sqlDB, err := db.DB()
+		if err != nil {
+			logger.Fatal("Failed to configure connection pool: %s\n", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+
 		logger.Info("Connected to SQLite database.\n")
 		if dbConfig.AutoMigrate {
-			db.AutoMigrate(&models.CucmCdr{}, &models.CubeCDR{}, &models.CucmCmr{})
+			autoMigrate(db)
 		}
 		return &DataService{Session: db, Config: dbConfig}
 
@@ -147,6 +155,40 @@ func InitDB(dbConfig config.DatabaseConfig) *DataService {
 	}
 }
 
+// migratedModels are the tables created by AutoMigrate on every driver but
+// ClickHouse, which is migrated with explicit DDL in migrateClickHouse.
+var migratedModels = []interface{}{
+	&models.CucmCdr{},
+	&models.CubeCDR{},
+	&models.CubeFeatureEvent{},
+	&models.CucmCmr{},
+	&models.OracleCDR{},
+	&models.WebexCDR{},
+	&models.CMSCall{},
+	&models.CMSCallLeg{},
+	&models.ExpresswayCall{},
+	&models.UccxContactCallDetail{},
+	&models.UccxAgentConnectionDetail{},
+	&models.CucmCall{},
+	&models.CucmCallLeg{},
+	&models.CubeCall{},
+	&models.CucmCubeLink{},
+	&models.Q850Cause{},
+	&models.CucmRedirectReason{},
+	&models.CucmOnBehalfOfCode{},
+	&models.IngestedFile{},
+	&models.RejectedRecord{},
+}
+
+func autoMigrate(db *gorm.DB) {
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		logger.Error("Failed to migrate database: %s\n", err)
+		return
+	}
+	seedLookupTables(db, "")
+	createViews(db, "")
+}
+
 // migrateClickHouse handles ClickHouse-specific migration with error recovery
 func migrateClickHouse(db *gorm.DB, databaseName string) {
 	logger.Info("Starting ClickHouse migration...\n")
@@ -163,6 +205,7 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 	createTableQuery := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.cucm_cdrs (
 			id String,
+			ingested_file_id Nullable(String),
 			origin_pkid Nullable(String),
 			file_cluster_id Nullable(String),
 			file_node_id Nullable(String),
@@ -316,6 +359,7 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 	createCubeTableQuery := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.cube_cdrs (
 			id String,
+			ingested_file_id Nullable(String),
 			invalid_ntp_reference Bool,
 			hostname Nullable(String),
 			filename Nullable(String),
@@ -495,6 +539,7 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 	createCMRTableQuery := fmt.Sprintf(`
 		CREATE TABLE IF NOT EXISTS %s.cucm_cmrs (
 			id String,
+			ingested_file_id Nullable(String),
 			originpkid Nullable(String),
 			file_cluster_id Nullable(String),
 			file_node_id Nullable(String),
@@ -546,6 +591,11 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 			remotesessionid Nullable(String),
 			headsetsn Nullable(String),
 			headsetmetrics Nullable(String),
+			headsetmodel Nullable(String),
+			headsetfirmware Nullable(String),
+			headsetconnection Nullable(String),
+			headsetbattery Nullable(Int64),
+			headsetsignal Nullable(Int64),
 			vqccr Nullable(Float64),
 			vqicr Nullable(Float64),
 			vqicrmx Nullable(Float64),
@@ -563,7 +613,8 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 			vqmlqkav Nullable(Float64),
 			vqmlqkmn Nullable(Float64),
 			vqmlqkmx Nullable(Float64),
-			vqmlqkvr Nullable(Float64)
+			vqmlqkvr Nullable(Float64),
+			varvqmetrics Nullable(String)
 		) ENGINE = MergeTree()
 		ORDER BY (id)
 		PARTITION BY tuple()
@@ -576,10 +627,739 @@ func migrateClickHouse(db *gorm.DB, databaseName string) {
 	}
 	logger.Info("Table cucm_cmrs created successfully\n")
 
+	logger.Info("Creating table oracle_cdrs...\n")
+	createOracleTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.oracle_cdrs (
+			id String,
+			ingested_file_id Nullable(String),
+			filename Nullable(String),
+			accountingstatus Nullable(String),
+			nasipaddress Nullable(String),
+			nasport Nullable(Int64),
+			accountingsessionid Nullable(String),
+			ingresssessionid Nullable(String),
+			egresssessionid Nullable(String),
+			sessionprotocoltype Nullable(String),
+			callingstationid Nullable(String),
+			calledstationid Nullable(String),
+			accountingterminationcause Nullable(String),
+			accountingsessiontime Nullable(Int64),
+			ciscosetuptime Nullable(Int64),
+			ciscoconnecttime Nullable(Int64),
+			ciscodisconnecttime Nullable(Int64),
+			ciscodisconnectcause Nullable(Int64),
+			egressnetworkinterfaceid Nullable(String),
+			egressvlantagvalue Nullable(Int64),
+			ingressnetworkinterfaceid Nullable(String),
+			ingressvlantagvalue Nullable(Int64),
+			egressrealm Nullable(String),
+			ingressrealm Nullable(String),
+			flowidentifier Nullable(String),
+			flowtype Nullable(String),
+			flowinputrealm Nullable(String),
+			flowinputsrcaddr Nullable(String),
+			flowinputsrcport Nullable(Int64),
+			flowinputdestaddress Nullable(String),
+			flowinputdestport Nullable(Int64),
+			flowoutputrealm Nullable(String),
+			flowoutputsrcaddress Nullable(String),
+			flowoutputsrcport Nullable(Int64),
+			flowoutputdestaddr Nullable(String),
+			flowoutputdestport Nullable(Int64),
+			rtcpcallingpacketslost Nullable(Int64),
+			rtcpcallingavgjitter Nullable(Int64),
+			rtcpcallingavglatency Nullable(Int64),
+			rtcpcallingmaxjitter Nullable(Int64),
+			rtcpcallingmaxlatency Nullable(Int64),
+			rtpcallingpacketslost Nullable(Int64),
+			rtpcallingavgjitter Nullable(Int64),
+			rtpcallingmaxjitter Nullable(Int64),
+			rtpcallingoctets Nullable(Int64),
+			rtpcallingpackets Nullable(Int64),
+			callingrfactor Nullable(Float64),
+			callingmos Nullable(Float64),
+			flowidentifier2 Nullable(String),
+			flowtype2 Nullable(String),
+			flowinputrealm2 Nullable(String),
+			flowinputsrcaddr2 Nullable(String),
+			flowinputsrcport2 Nullable(Int64),
+			flowinputdestaddress2 Nullable(String),
+			flowinputdestport2 Nullable(Int64),
+			flowoutputrealm2 Nullable(String),
+			flowoutputsrcaddress2 Nullable(String),
+			flowoutputsrcport2 Nullable(Int64),
+			flowoutputdestaddr2 Nullable(String),
+			flowoutputdestport2 Nullable(Int64),
+			rtcpcalledpacketslost Nullable(Int64),
+			rtcpcalledavgjitter Nullable(Int64),
+			rtcpcalledavglatency Nullable(Int64),
+			rtcpcalledmaxjitter Nullable(Int64),
+			rtcpcalledmaxlatency Nullable(Int64),
+			rtpcalledpacketslost Nullable(Int64),
+			rtpcalledavgjitter Nullable(Int64),
+			rtpcalledmaxjitter Nullable(Int64),
+			rtpcalledoctets Nullable(Int64),
+			rtpcalledpackets Nullable(Int64),
+			calledrfactor Nullable(Float64),
+			calledmos Nullable(Float64),
+			firmwareversion Nullable(String),
+			localtimezone Nullable(String),
+			postdialdelay Nullable(Int64),
+			primaryroutingnumber Nullable(String),
+			ingresslocaladdress Nullable(String),
+			ingressremoteaddress Nullable(String),
+			egresslocaladdress Nullable(String),
+			egressremoteaddress Nullable(String),
+			sessiondisposition Nullable(Int64),
+			disconnectinitiator Nullable(Int64),
+			disconnectcause Nullable(Int64),
+			sipstatuscode Nullable(Int64),
+			egressroutingnumber Nullable(String),
+			callingmediastoptime Nullable(Int64),
+			calledmediastoptime Nullable(Int64),
+			flowmediatype Nullable(String),
+			flowmediatype2 Nullable(String),
+			rtpcallingoctetstransmitted Nullable(Int64),
+			rtpcallingpacketstransmitted Nullable(Int64),
+			rtpcalledoctetstransmitted Nullable(Int64),
+			rtpcalledpacketstransmitted Nullable(Int64),
+			msrpcalledoctets Nullable(Int64),
+			msrpcalledpackets Nullable(Int64),
+			msrpcalledoctetstransmitted Nullable(Int64),
+			msrpcalledpacketstransmitted Nullable(Int64),
+			msrpcallingoctets Nullable(Int64),
+			msrpcallingpackets Nullable(Int64),
+			msrpcallingoctetstransmitted Nullable(Int64),
+			msrpcallingpacketstransmitted Nullable(Int64),
+			nodefunctionality Nullable(String),
+			cdrsequencenumber Nullable(Int64)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createOracleTableQuery).Error; err != nil {
+		logger.Error("Failed to create oracle_cdrs table: %s\n", err)
+		return
+	}
+	logger.Info("Table oracle_cdrs created successfully\n")
+
+	logger.Info("Creating table ingested_files...\n")
+	createLedgerTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.ingested_files (
+			id String,
+			size Int64,
+			path String,
+			type String,
+			records Int64,
+			rejected Int64,
+			status String,
+			error Nullable(String),
+			started_at Int64,
+			finished_at Nullable(Int64),
+			updated_at Int64
+		) ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY (id)
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createLedgerTableQuery).Error; err != nil {
+		logger.Error("Failed to create ingested_files table: %s\n", err)
+		return
+	}
+	logger.Info("Table ingested_files created successfully\n")
+
+	logger.Info("Creating table cube_feature_events...\n")
+	createCubeFeatureTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cube_feature_events (
+			id String,
+			ingested_file_id Nullable(String),
+			cube_cdr_id String,
+			filename Nullable(String),
+			call_id Nullable(Int64),
+			feature Nullable(String),
+			status Nullable(String),
+			time Nullable(Int64),
+			feature_id Nullable(String),
+			correlation_id Nullable(String),
+			leg_id Nullable(String),
+			calling_number Nullable(String),
+			called_number Nullable(String),
+			fields Nullable(String)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCubeFeatureTableQuery).Error; err != nil {
+		logger.Error("Failed to create cube_feature_events table: %s\n", err)
+		return
+	}
+	logger.Info("Table cube_feature_events created successfully\n")
+
+	logger.Info("Creating table webex_cdrs...\n")
+	createWebexTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.webex_cdrs (
+			id String,
+			ingested_file_id Nullable(String),
+			filename Nullable(String),
+			starttime Nullable(Int64),
+			answertime Nullable(Int64),
+			duration Nullable(Int64),
+			reportid Nullable(String),
+			reporttime Nullable(Int64),
+			callid Nullable(String),
+			correlationid Nullable(String),
+			localcallid Nullable(String),
+			remotecallid Nullable(String),
+			networkcallid Nullable(String),
+			relatedcallid Nullable(String),
+			transferrelatedcallid Nullable(String),
+			user Nullable(String),
+			usertype Nullable(String),
+			useruuid Nullable(String),
+			callinglineid Nullable(String),
+			calledlineid Nullable(String),
+			callingnumber Nullable(String),
+			callednumber Nullable(String),
+			dialeddigits Nullable(String),
+			calleridnumber Nullable(String),
+			redirectingnumber Nullable(String),
+			direction Nullable(String),
+			calltype Nullable(String),
+			clienttype Nullable(String),
+			clientversion Nullable(String),
+			subclienttype Nullable(String),
+			devicemac Nullable(String),
+			model Nullable(String),
+			ostype Nullable(String),
+			location Nullable(String),
+			locationuuid Nullable(String),
+			siteuuid Nullable(String),
+			orguuid Nullable(String),
+			departmentid Nullable(String),
+			answered Nullable(Bool),
+			answerindicator Nullable(String),
+			originalreason Nullable(String),
+			redirectreason Nullable(String),
+			relatedreason Nullable(String),
+			inboundtrunk Nullable(String),
+			outboundtrunk Nullable(String),
+			routegroup Nullable(String),
+			internationalcountry Nullable(String),
+			authorizationcode Nullable(String),
+			calltransfertime Nullable(Int64),
+			ringduration Nullable(Int64),
+			holdduration Nullable(Int64),
+			waittime Nullable(Int64),
+			queuetype Nullable(String),
+			releasetime Nullable(Int64),
+			releasingparty Nullable(String),
+			releasecause Nullable(String),
+			calloutcome Nullable(String),
+			calloutcomereason Nullable(String),
+			localsessionid Nullable(String),
+			remotesessionid Nullable(String),
+			finallocalsessionid Nullable(String),
+			finalremotesessionid Nullable(String),
+			pstnvendorname Nullable(String),
+			pstnlegalentity Nullable(String),
+			pstnvendororgid Nullable(String),
+			pstnproviderid Nullable(String),
+			publiccallingipaddress Nullable(String),
+			publiccalledipaddress Nullable(String)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createWebexTableQuery).Error; err != nil {
+		logger.Error("Failed to create webex_cdrs table: %s\n", err)
+		return
+	}
+	logger.Info("Table webex_cdrs created successfully\n")
+
+	logger.Info("Creating table expressway_calls...\n")
+	createExpresswayTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.expressway_calls (
+			id String,
+			ingested_file_id Nullable(String),
+			filename Nullable(String),
+			system Nullable(String),
+			serial_number Nullable(String),
+			tag Nullable(String),
+			state Nullable(String),
+			start_time Nullable(Int64),
+			end_time Nullable(Int64),
+			duration Nullable(Int64),
+			source_alias Nullable(String),
+			destination_alias Nullable(String),
+			source_address Nullable(String),
+			destination_address Nullable(String),
+			protocol Nullable(String),
+			call_type Nullable(String),
+			source_zone Nullable(String),
+			destination_zone Nullable(String),
+			requested_bandwidth Nullable(Int64),
+			allocated_bandwidth Nullable(Int64),
+			media_routed Nullable(String),
+			encryption Nullable(String),
+			disconnect_reason Nullable(String)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createExpresswayTableQuery).Error; err != nil {
+		logger.Error("Failed to create expressway_calls table: %s\n", err)
+		return
+	}
+	logger.Info("Table expressway_calls created successfully\n")
+
+	logger.Info("Creating table uccx_contact_call_details...\n")
+	createUccxContactTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.uccx_contact_call_details (
+			id String,
+			ingested_file_id Nullable(String),
+			filename Nullable(String),
+			sessionid Nullable(Int64),
+			sessionseqnum Nullable(Int64),
+			nodeid Nullable(Int64),
+			profileid Nullable(Int64),
+			contacttype Nullable(Int64),
+			contactdisposition Nullable(Int64),
+			dispositionreason Nullable(String),
+			originatortype Nullable(Int64),
+			originatorid Nullable(Int64),
+			originatordn Nullable(String),
+			destinationtype Nullable(Int64),
+			destinationid Nullable(Int64),
+			destinationdn Nullable(String),
+			startdatetime Nullable(Int64),
+			enddatetime Nullable(Int64),
+			gmtoffset Nullable(Int64),
+			callednumber Nullable(String),
+			origcallednumber Nullable(String),
+			applicationtaskid Nullable(Int64),
+			applicationid Nullable(Int64),
+			applicationname Nullable(String),
+			connecttime Nullable(Int64),
+			customvariable1 Nullable(String),
+			customvariable2 Nullable(String),
+			customvariable3 Nullable(String),
+			customvariable4 Nullable(String),
+			customvariable5 Nullable(String),
+			customvariable6 Nullable(String),
+			customvariable7 Nullable(String),
+			customvariable8 Nullable(String),
+			customvariable9 Nullable(String),
+			customvariable10 Nullable(String),
+			accountnumber Nullable(String),
+			callerentereddigits Nullable(String),
+			badcalltag Nullable(String),
+			transfer Nullable(Bool),
+			redirect Nullable(Bool),
+			conference Nullable(Bool),
+			flowout Nullable(Bool),
+			metservicelevel Nullable(Bool),
+			campaignid Nullable(Int64),
+			origprotocolcallref Nullable(String),
+			destprotocolcallref Nullable(String),
+			globalcallid_callid Nullable(Int64),
+			callresult Nullable(Int64),
+			dialinglistid Nullable(Int64)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createUccxContactTableQuery).Error; err != nil {
+		logger.Error("Failed to create uccx_contact_call_details table: %s\n", err)
+		return
+	}
+	logger.Info("Table uccx_contact_call_details created successfully\n")
+
+	logger.Info("Creating table uccx_agent_connection_details...\n")
+	createUccxAgentTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.uccx_agent_connection_details (
+			id String,
+			ingested_file_id Nullable(String),
+			filename Nullable(String),
+			sessionid Nullable(Int64),
+			sessionseqnum Nullable(Int64),
+			nodeid Nullable(Int64),
+			profileid Nullable(Int64),
+			resourceid Nullable(Int64),
+			startdatetime Nullable(Int64),
+			enddatetime Nullable(Int64),
+			qindex Nullable(Int64),
+			gmtoffset Nullable(Int64),
+			ringtime Nullable(Int64),
+			talktime Nullable(Int64),
+			holdtime Nullable(Int64),
+			worktime Nullable(Int64),
+			callwrapupdata Nullable(String),
+			callresult Nullable(Int64),
+			dialinglistid Nullable(Int64)
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createUccxAgentTableQuery).Error; err != nil {
+		logger.Error("Failed to create uccx_agent_connection_details table: %s\n", err)
+		return
+	}
+	logger.Info("Table uccx_agent_connection_details created successfully\n")
+
+	logger.Info("Creating table cucm_calls...\n")
+	createCucmCallTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cucm_calls (
+			id String,
+			globalcallid_clusterid Nullable(String),
+			globalcallid_callmanagerid Nullable(Int64),
+			globalcallid_callid Nullable(Int64),
+			legs Int64,
+			start_time Nullable(Int64),
+			answer_time Nullable(Int64),
+			end_time Nullable(Int64),
+			calling_number Nullable(String),
+			original_called_number Nullable(String),
+			answering_number Nullable(String),
+			answering_device Nullable(String),
+			hunt_pilot Nullable(String),
+			ring_time Nullable(Int64),
+			talk_time Int64,
+			transferred Bool,
+			forwarded Bool,
+			conferenced Bool,
+			chain Nullable(String),
+			disposition String,
+			disposition_cause Nullable(Int64),
+			cmrs Int64,
+			min_mlqk Nullable(Float64),
+			max_jitter Nullable(Int64),
+			max_latency Nullable(Int64),
+			packets_lost Nullable(Int64),
+			max_ccr Nullable(Float64),
+			updated_at Int64
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCucmCallTableQuery).Error; err != nil {
+		logger.Error("Failed to create cucm_calls table: %s\n", err)
+		return
+	}
+	logger.Info("Table cucm_calls created successfully\n")
+
+	logger.Info("Creating table cucm_call_legs...\n")
+	createCucmCallLegTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cucm_call_legs (
+			id String,
+			call_id String,
+			cucm_cdr_id String,
+			globalcallid String,
+			sequence Int64,
+			orig_cmr_id Nullable(String),
+			orig_mlqk Nullable(Float64),
+			orig_mlqk_min Nullable(Float64),
+			orig_jitter Nullable(Int64),
+			orig_latency Nullable(Int64),
+			orig_packets_lost Nullable(Int64),
+			orig_ccr Nullable(Float64),
+			dest_cmr_id Nullable(String),
+			dest_mlqk Nullable(Float64),
+			dest_mlqk_min Nullable(Float64),
+			dest_jitter Nullable(Int64),
+			dest_latency Nullable(Int64),
+			dest_packets_lost Nullable(Int64),
+			dest_ccr Nullable(Float64)
+		) ENGINE = MergeTree()
+		ORDER BY (call_id, sequence)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCucmCallLegTableQuery).Error; err != nil {
+		logger.Error("Failed to create cucm_call_legs table: %s\n", err)
+		return
+	}
+	logger.Info("Table cucm_call_legs created successfully\n")
+
+	logger.Info("Creating table cube_calls...\n")
+	createCubeCallTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cube_calls (
+			id String,
+			hostname Nullable(String),
+			h323_conf_id Nullable(String),
+			legs Int64,
+			ingress_cdr_id Nullable(String),
+			ingress_call_id Nullable(Int64),
+			ingress_leg_type Nullable(Int64),
+			ingress_peer_id Nullable(Int64),
+			ingress_trunkgroup Nullable(String),
+			ingress_peer_address Nullable(String),
+			egress_cdr_id Nullable(String),
+			egress_call_id Nullable(Int64),
+			egress_leg_type Nullable(Int64),
+			egress_peer_id Nullable(Int64),
+			egress_trunkgroup Nullable(String),
+			egress_peer_address Nullable(String),
+			gw_rxd_cgn Nullable(String),
+			gw_rxd_cdn Nullable(String),
+			gw_rxd_rdn Nullable(String),
+			gw_collected_cdn Nullable(String),
+			gk_xlated_cgn Nullable(String),
+			gk_xlated_cdn Nullable(String),
+			gw_final_xlated_cgn Nullable(String),
+			gw_final_xlated_cdn Nullable(String),
+			gw_final_xlated_rdn Nullable(String),
+			setup_time Nullable(Int64),
+			alert_time Nullable(Int64),
+			connect_time Nullable(Int64),
+			disconnect_time Nullable(Int64),
+			duration Nullable(Int64),
+			disconnect_cause Nullable(String),
+			disconnect_text Nullable(String),
+			ingress_codec Nullable(String),
+			ingress_paks_in Nullable(Int64),
+			ingress_paks_out Nullable(Int64),
+			ingress_lost_packets Nullable(Int64),
+			ingress_late_packets Nullable(Int64),
+			ingress_early_packets Nullable(Int64),
+			ingress_round_trip_delay Nullable(Int64),
+			ingress_remote_media_address Nullable(String),
+			egress_codec Nullable(String),
+			egress_paks_in Nullable(Int64),
+			egress_paks_out Nullable(Int64),
+			egress_lost_packets Nullable(Int64),
+			egress_late_packets Nullable(Int64),
+			egress_early_packets Nullable(Int64),
+			egress_round_trip_delay Nullable(Int64),
+			egress_remote_media_address Nullable(String),
+			updated_at Int64
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCubeCallTableQuery).Error; err != nil {
+		logger.Error("Failed to create cube_calls table: %s\n", err)
+		return
+	}
+	logger.Info("Table cube_calls created successfully\n")
+
+	logger.Info("Creating table cucm_cube_links...\n")
+	createCucmCubeLinkTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cucm_cube_links (
+			id String,
+			cucm_cdr_id String,
+			cube_call_id String,
+			direction String,
+			method String,
+			offset_seconds Int64,
+			updated_at Int64
+		) ENGINE = MergeTree()
+		ORDER BY (cucm_cdr_id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCucmCubeLinkTableQuery).Error; err != nil {
+		logger.Error("Failed to create cucm_cube_links table: %s\n", err)
+		return
+	}
+	logger.Info("Table cucm_cube_links created successfully\n")
+
+	logger.Info("Creating table cms_calls...\n")
+	createCMSCallTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cms_calls (
+			id String,
+			cdr_session Nullable(String),
+			name Nullable(String),
+			owner_name Nullable(String),
+			co_space Nullable(String),
+			call_correlator Nullable(String),
+			call_type Nullable(String),
+			cdr_tag Nullable(String),
+			start_time Nullable(Int64),
+			end_time Nullable(Int64),
+			duration_seconds Nullable(Int64),
+			call_legs_completed Nullable(Int64),
+			call_legs_max_active Nullable(Int64),
+			updated_at Int64
+		) ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY (id)
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCMSCallTableQuery).Error; err != nil {
+		logger.Error("Failed to create cms_calls table: %s\n", err)
+		return
+	}
+	logger.Info("Table cms_calls created successfully\n")
+
+	logger.Info("Creating table cms_call_legs...\n")
+	createCMSCallLegTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.cms_call_legs (
+			id String,
+			call_id Nullable(String),
+			cdr_session Nullable(String),
+			display_name Nullable(String),
+			local_address Nullable(String),
+			remote_address Nullable(String),
+			remote_party Nullable(String),
+			type Nullable(String),
+			sub_type Nullable(String),
+			direction Nullable(String),
+			sip_call_id Nullable(String),
+			group_id Nullable(String),
+			owner_id Nullable(String),
+			cdr_tag Nullable(String),
+			guest_connection Nullable(Bool),
+			recording Nullable(Bool),
+			streaming Nullable(Bool),
+			state Nullable(String),
+			start_time Nullable(Int64),
+			connect_time Nullable(Int64),
+			end_time Nullable(Int64),
+			reason Nullable(String),
+			remote_teardown Nullable(Bool),
+			duration_seconds Nullable(Int64),
+			activated_duration Nullable(Int64),
+			encrypted_media Nullable(Bool),
+			unencrypted_media Nullable(Bool),
+			rx_audio_codec Nullable(String),
+			tx_audio_codec Nullable(String),
+			rx_video_codec Nullable(String),
+			rx_video_max_width Nullable(Int64),
+			rx_video_max_height Nullable(Int64),
+			tx_video_codec Nullable(String),
+			tx_video_max_width Nullable(Int64),
+			tx_video_max_height Nullable(Int64),
+			alarms Nullable(String),
+			updated_at Int64
+		) ENGINE = ReplacingMergeTree(updated_at)
+		ORDER BY (id)
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createCMSCallLegTableQuery).Error; err != nil {
+		logger.Error("Failed to create cms_call_legs table: %s\n", err)
+		return
+	}
+	logger.Info("Table cms_call_legs created successfully\n")
+
+	logger.Info("Creating table rejected_records...\n")
+	createRejectedTableQuery := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.rejected_records (
+			id String,
+			ingested_file_id Nullable(String),
+			file String,
+			line Int64,
+			type String,
+			reason String,
+			error Nullable(String),
+			raw String,
+			created_at Int64
+		) ENGINE = MergeTree()
+		ORDER BY (created_at, file, line)
+		SETTINGS index_granularity = 8192
+	`, databaseName)
+
+	if err := db.Exec(createRejectedTableQuery).Error; err != nil {
+		logger.Error("Failed to create rejected_records table: %s\n", err)
+		return
+	}
+	logger.Info("Table rejected_records created successfully\n")
+
+	// Columns added after the first release, for tables created before them
+	addedColumns := []struct {
+		table      string
+		definition string
+	}{
+		{"cucm_cdrs", "ingested_file_id Nullable(String)"},
+		{"cube_cdrs", "ingested_file_id Nullable(String)"},
+		{"cucm_cmrs", "ingested_file_id Nullable(String)"},
+		{"cucm_cmrs", "headsetmodel Nullable(String)"},
+		{"cucm_cmrs", "headsetfirmware Nullable(String)"},
+		{"cucm_cmrs", "headsetconnection Nullable(String)"},
+		{"cucm_cmrs", "headsetbattery Nullable(Int64)"},
+		{"cucm_cmrs", "headsetsignal Nullable(Int64)"},
+		{"cucm_cmrs", "varvqmetrics Nullable(String)"},
+		{"cucm_calls", "cmrs Int64"},
+		{"cucm_calls", "min_mlqk Nullable(Float64)"},
+		{"cucm_calls", "max_jitter Nullable(Int64)"},
+		{"cucm_calls", "max_latency Nullable(Int64)"},
+		{"cucm_calls", "packets_lost Nullable(Int64)"},
+		{"cucm_calls", "max_ccr Nullable(Float64)"},
+		{"cucm_call_legs", "orig_cmr_id Nullable(String)"},
+		{"cucm_call_legs", "orig_mlqk Nullable(Float64)"},
+		{"cucm_call_legs", "orig_mlqk_min Nullable(Float64)"},
+		{"cucm_call_legs", "orig_jitter Nullable(Int64)"},
+		{"cucm_call_legs", "orig_latency Nullable(Int64)"},
+		{"cucm_call_legs", "orig_packets_lost Nullable(Int64)"},
+		{"cucm_call_legs", "orig_ccr Nullable(Float64)"},
+		{"cucm_call_legs", "dest_cmr_id Nullable(String)"},
+		{"cucm_call_legs", "dest_mlqk Nullable(Float64)"},
+		{"cucm_call_legs", "dest_mlqk_min Nullable(Float64)"},
+		{"cucm_call_legs", "dest_jitter Nullable(Int64)"},
+		{"cucm_call_legs", "dest_latency Nullable(Int64)"},
+		{"cucm_call_legs", "dest_packets_lost Nullable(Int64)"},
+		{"cucm_call_legs", "dest_ccr Nullable(Float64)"},
+	}
+	for _, column := range addedColumns {
+		alterQuery := fmt.Sprintf("ALTER TABLE %s.%s ADD COLUMN IF NOT EXISTS %s", databaseName, column.table, column.definition)
+		if err := db.Exec(alterQuery).Error; err != nil {
+			logger.Error("Failed to add column to %s: %s\n", column.table, err)
+			return
+		}
+	}
+
+	// Skip indexes for the lookups of the call rebuilds. They only cover
+	// parts written after they were added, ALTER TABLE ... MATERIALIZE INDEX
+	// indexes the existing rows.
+	addedIndexes := []struct {
+		table      string
+		definition string
+	}{
+		{"cucm_cdrs", "idx_globalcallid_callid globalcallid_callid TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_origlegcallidentifier origlegcallidentifier TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_destlegcallidentifier destlegcallidentifier TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_origconversationid origconversationid TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_destconversationid destconversationid TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cmrs", "idx_globalcallid_callid globalcallid_callid TYPE bloom_filter GRANULARITY 4"},
+		{"cube_cdrs", "idx_h323_conf_id h323_conf_id TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_incomingprotocolcallref incomingprotocolcallref TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_outgoingprotocolcallref outgoingprotocolcallref TYPE bloom_filter GRANULARITY 4"},
+		{"cucm_cdrs", "idx_datetimedisconnect datetimedisconnect TYPE minmax GRANULARITY 4"},
+		{"cube_calls", "idx_disconnect_time disconnect_time TYPE minmax GRANULARITY 4"},
+	}
+	for _, index := range addedIndexes {
+		alterQuery := fmt.Sprintf("ALTER TABLE %s.%s ADD INDEX IF NOT EXISTS %s", databaseName, index.table, index.definition)
+		if err := db.Exec(alterQuery).Error; err != nil {
+			logger.Error("Failed to add index to %s: %s\n", index.table, err)
+			return
+		}
+	}
+
+	seedLookupTables(db, databaseName)
+	createViews(db, databaseName)
+
 	logger.Info("ClickHouse migration completed successfully.\n")
 }
 
-func (ds *DataService) WriteCDRs(cdrs []models.CucmCdr) error {
+func (ds *DataService) WriteCDRs(cdrs []*models.CucmCdr) error {
 	if len(cdrs) == 0 {
 		return nil
 	}
@@ -599,7 +1379,7 @@ func (ds *DataService) WriteCDRs(cdrs []models.CucmCdr) error {
 	return nil
 }
 
-func (ds *DataService) writeClickHouseCDRs(cdrs []models.CucmCdr) error {
+func (ds *DataService) writeClickHouseCDRs(cdrs []*models.CucmCdr) error {
 	batchSize := int(ds.Config.Limit)
 	if batchSize <= 0 {
 		batchSize = 5000
@@ -623,3 +1403,11 @@ func (ds *DataService) writeClickHouseCDRs(cdrs []models.CucmCdr) error {
 
 	return nil
 }
+
+// BatchSize is the number of records written to the database at once.
+func (ds *DataService) BatchSize() int {
+	if ds.Config.Limit <= 0 {
+		return 100
+	}
+	return int(ds.Config.Limit)
+}
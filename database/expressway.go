@@ -0,0 +1,87 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func (ds DataService) CreateExpresswayCalls(calls []*models.ExpresswayCall) error {
+	return ds.WriteExpresswayCalls(calls)
+}
+
+func (ds *DataService) WriteExpresswayCalls(calls []*models.ExpresswayCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	if ds.Session.Dialector.Name() == "clickhouse" {
+		return ds.writeClickHouseExpresswayCalls(calls)
+	}
+
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := ds.Session.CreateInBatches(calls, limit).Error; err != nil {
+		return fmt.Errorf("failed to write Expressway calls: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DataService) writeClickHouseExpresswayCalls(calls []*models.ExpresswayCall) error {
+	batchSize := int(ds.Config.Limit)
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	db := ds.Session
+	tableName := fmt.Sprintf("%s.expressway_calls", ds.Config.Database)
+
+	for i := 0; i < len(calls); i += batchSize {
+		end := i + batchSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+
+		batch := calls[i:end]
+
+		if err := db.Table(tableName).CreateInBatches(batch, len(batch)).Error; err != nil {
+			return fmt.Errorf("failed to write ClickHouse Expressway call batch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetLatestExpresswayCalls returns the calls of a poller that ended last, so
+// a restarted poller knows which calls it already wrote.
+func (ds *DataService) GetLatestExpresswayCalls(system string) ([]models.ExpresswayCall, error) {
+	tableName := "expressway_calls"
+	if ds.Config.Driver == "clickhouse" {
+		tableName = fmt.Sprintf("%s.expressway_calls", ds.Config.Database)
+	}
+
+	var calls []models.ExpresswayCall
+	query := fmt.Sprintf("system = ? AND end_time = (SELECT MAX(end_time) FROM %s WHERE system = ?)", tableName)
+	if err := ds.Session.Table(tableName).Where(query, system, system).Find(&calls).Error; err != nil {
+		return nil, fmt.Errorf("failed to read Expressway calls: %w", err)
+	}
+	return calls, nil
+}
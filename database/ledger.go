@@ -0,0 +1,117 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// ledgerRecordTables are the built-in tables whose rows carry the ingested_file_id of
+// the file they were read from.
+var ledgerRecordTables = []string{"cucm_cdrs", "cucm_cmrs", "cube_cdrs", "oracle_cdrs", "webex_cdrs", "expressway_calls",
+	"uccx_contact_call_details", "uccx_agent_connection_details"}
+
+// ledgerDerivedTables hold rows derived from the records of a file, such as the
+// feature events of CUBE CDRs. They are rolled back with the file but are not
+// records of their own, so they are not counted when a file is resumed.
+var ledgerDerivedTables = []string{"cube_feature_events"}
+
+// GetIngestedFile returns the ledger entry of a file, or nil if the file has
+// never been ingested.
+func (ds *DataService) GetIngestedFile(id string) (*models.IngestedFile, error) {
+	var entries []models.IngestedFile
+
+	session := ds.Session
+	if ds.Config.Driver == "clickhouse" {
+		// Every update is a new version of the row, the newest one wins
+		session = session.Table(fmt.Sprintf("%s.ingested_files", ds.Config.Database)).Order("updated_at DESC")
+	}
+	if err := session.Where("id = ?", id).Limit(1).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to read ingested file: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// SaveIngestedFile creates or updates a ledger entry.
+func (ds *DataService) SaveIngestedFile(entry *models.IngestedFile) error {
+	entry.UpdatedAt = time.Now().UnixNano()
+
+	if ds.Config.Driver == "clickhouse" {
+		tableName := fmt.Sprintf("%s.ingested_files", ds.Config.Database)
+		if err := ds.Session.Table(tableName).Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to write ingested file: %w", err)
+		}
+		return nil
+	}
+
+	if err := ds.Session.Save(entry).Error; err != nil {
+		return fmt.Errorf("failed to write ingested file: %w", err)
+	}
+	return nil
+}
+
+// DeleteIngestedFile removes a ledger entry.
+func (ds *DataService) DeleteIngestedFile(id string) error {
+	if ds.Config.Driver == "clickhouse" {
+		query := fmt.Sprintf("ALTER TABLE %s.ingested_files DELETE WHERE id = ? SETTINGS mutations_sync = 1", ds.Config.Database)
+		return ds.Session.Exec(query, id).Error
+	}
+	return ds.Session.Where("id = ?", id).Delete(&models.IngestedFile{}).Error
+}
+
+// CountIngestedRecords returns the number of records written from a file.
+func (ds *DataService) CountIngestedRecords(id string) (int64, error) {
+	var total int64
+	for _, table := range recordTables() {
+		if ds.Config.Driver == "clickhouse" {
+			table = fmt.Sprintf("%s.%s", ds.Config.Database, table)
+		}
+		var count int64
+		if err := ds.Session.Table(table).Where("ingested_file_id = ?", id).Count(&count).Error; err != nil {
+			return 0, fmt.Errorf("failed to count records of ingested file: %w", err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// RollbackIngestedFile deletes every record written from a file, and the
+// rows that were rejected.
+func (ds *DataService) RollbackIngestedFile(id string) error {
+	if err := ds.DeleteRejectedRecords(id); err != nil {
+		return fmt.Errorf("failed to roll back rejected_records: %w", err)
+	}
+	for _, table := range append(recordTables(), ledgerDerivedTables...) {
+		var err error
+		if ds.Config.Driver == "clickhouse" {
+			query := fmt.Sprintf("ALTER TABLE %s DELETE WHERE ingested_file_id = ? SETTINGS mutations_sync = 1", ds.quoteName(ds.Config.Database+"."+table))
+			err = ds.Session.Exec(query, id).Error
+		} else {
+			err = ds.Session.Exec(fmt.Sprintf("DELETE FROM %s WHERE ingested_file_id = ?", ds.quoteName(table)), id).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to roll back %s: %w", table, err)
+		}
+	}
+	return nil
+}
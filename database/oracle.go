@@ -0,0 +1,71 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func (ds DataService) CreateOracleCDRs(cdrs []*models.OracleCDR) error {
+	return ds.WriteOracleCDRs(cdrs)
+}
+
+func (ds *DataService) WriteOracleCDRs(cdrs []*models.OracleCDR) error {
+	if len(cdrs) == 0 {
+		return nil
+	}
+
+	if ds.Session.Dialector.Name() == "clickhouse" {
+		return ds.writeClickHouseOracleCDRs(cdrs)
+	}
+
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := ds.Session.CreateInBatches(cdrs, limit).Error; err != nil {
+		return fmt.Errorf("failed to write Oracle CDRs: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DataService) writeClickHouseOracleCDRs(cdrs []*models.OracleCDR) error {
+	batchSize := int(ds.Config.Limit)
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	db := ds.Session
+	tableName := fmt.Sprintf("%s.oracle_cdrs", ds.Config.Database)
+
+	for i := 0; i < len(cdrs); i += batchSize {
+		end := i + batchSize
+		if end > len(cdrs) {
+			end = len(cdrs)
+		}
+
+		batch := cdrs[i:end]
+
+		if err := db.Table(tableName).CreateInBatches(batch, len(batch)).Error; err != nil {
+			return fmt.Errorf("failed to write ClickHouse Oracle CDR batch: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+var (
+	profileTablesMutex sync.Mutex
+	// profileTables are the destination tables of the CSV profiles. Like the
+	// built-in tables, their rows carry the ingested_file_id of their file.
+	profileTables []string
+)
+
+// recordTables returns every table written from ingested files.
+func recordTables() []string {
+	profileTablesMutex.Lock()
+	defer profileTablesMutex.Unlock()
+	return append(append([]string(nil), ledgerRecordTables...), profileTables...)
+}
+
+// SetupProfileTable registers the destination table of a profile with the
+// ledger. With autoMigrate the table is created, and columns added to the
+// profile since are added to it.
+func (ds *DataService) SetupProfileTable(profile config.ProfileConfig) error {
+	for _, table := range ledgerRecordTables {
+		if strings.EqualFold(table, profile.Table) {
+			return fmt.Errorf("profile %s: table %s is a built-in table", profile.Name, profile.Table)
+		}
+	}
+
+	if ds.Config.AutoMigrate {
+		var err error
+		if ds.Config.Driver == "clickhouse" {
+			err = ds.migrateClickHouseProfileTable(profile)
+		} else {
+			err = ds.migrateProfileTable(profile)
+		}
+		if err != nil {
+			return fmt.Errorf("profile %s: failed to migrate table %s: %w", profile.Name, profile.Table, err)
+		}
+	}
+
+	profileTablesMutex.Lock()
+	defer profileTablesMutex.Unlock()
+	for _, table := range profileTables {
+		if table == profile.Table {
+			return nil
+		}
+	}
+	profileTables = append(profileTables, profile.Table)
+	return nil
+}
+
+func (ds *DataService) migrateProfileTable(profile config.ProfileConfig) error {
+	migrator := ds.Session.Migrator()
+
+	table := ds.quoteName(profile.Table)
+	if !migrator.HasTable(profile.Table) {
+		logger.Info("Creating table %s...\n", profile.Table)
+		definitions := []string{
+			"id " + ds.profileKeyType() + " NOT NULL PRIMARY KEY",
+			"ingested_file_id " + ds.profileKeyType(),
+			"filename " + ds.profileColumnType("string"),
+		}
+		for _, column := range profile.Columns {
+			definitions = append(definitions, ds.quoteName(column.Name)+" "+ds.profileColumnType(column.Type))
+		}
+		query := fmt.Sprintf("CREATE TABLE %s (%s)", table, strings.Join(definitions, ", "))
+		if err := ds.Session.Exec(query).Error; err != nil {
+			return err
+		}
+		index := ds.quoteName("idx_" + profile.Table + "_ingested_file_id")
+		query = fmt.Sprintf("CREATE INDEX %s ON %s (ingested_file_id)", index, table)
+		return ds.Session.Exec(query).Error
+	}
+
+	add := "ADD COLUMN"
+	if ds.Config.Driver == "sqlserver" {
+		add = "ADD"
+	}
+	for _, column := range profile.Columns {
+		if migrator.HasColumn(profile.Table, column.Name) {
+			continue
+		}
+		logger.Info("Adding column %s to table %s...\n", column.Name, profile.Table)
+		query := fmt.Sprintf("ALTER TABLE %s %s %s %s", table, add, ds.quoteName(column.Name), ds.profileColumnType(column.Type))
+		if err := ds.Session.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds *DataService) migrateClickHouseProfileTable(profile config.ProfileConfig) error {
+	logger.Info("Creating table %s...\n", profile.Table)
+	table := ds.quoteName(ds.Config.Database + "." + profile.Table)
+	definitions := []string{
+		"id String",
+		"ingested_file_id Nullable(String)",
+		"filename Nullable(String)",
+	}
+	for _, column := range profile.Columns {
+		definitions = append(definitions, ds.quoteName(column.Name)+" "+ds.profileColumnType(column.Type))
+	}
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			%s
+		) ENGINE = MergeTree()
+		ORDER BY (id)
+		PARTITION BY tuple()
+		SETTINGS index_granularity = 8192
+	`, table, strings.Join(definitions, ",\n\t\t\t"))
+	if err := ds.Session.Exec(query).Error; err != nil {
+		return err
+	}
+
+	// Columns added to the profile after the table was created
+	for _, column := range profile.Columns {
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, ds.quoteName(column.Name), ds.profileColumnType(column.Type))
+		if err := ds.Session.Exec(query).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteName quotes a table or column name for the driver, so profiles can use
+// names that are reserved words such as order or user.
+func (ds *DataService) quoteName(name string) string {
+	return ds.Session.Statement.Quote(name)
+}
+
+// profileKeyType is the type of the id and ingested_file_id columns, which
+// must be indexable on every driver.
+func (ds *DataService) profileKeyType() string {
+	switch ds.Config.Driver {
+	case "mysql", "sqlserver":
+		return "varchar(191)"
+	default:
+		return "text"
+	}
+}
+
+// profileColumnType returns the SQL type of a profile column type. Times are
+// stored as Unix seconds, like the timestamps of the built-in tables.
+func (ds *DataService) profileColumnType(columnType string) string {
+	switch ds.Config.Driver {
+	case "clickhouse":
+		switch columnType {
+		case "int", "time":
+			return "Nullable(Int64)"
+		case "float":
+			return "Nullable(Float64)"
+		default:
+			return "Nullable(String)"
+		}
+	case "sqlite":
+		switch columnType {
+		case "int", "time":
+			return "integer"
+		case "float":
+			return "real"
+		default:
+			return "text"
+		}
+	case "mysql":
+		switch columnType {
+		case "int", "time":
+			return "bigint"
+		case "float":
+			return "double"
+		default:
+			return "longtext"
+		}
+	case "sqlserver":
+		switch columnType {
+		case "int", "time":
+			return "bigint"
+		case "float":
+			return "float"
+		default:
+			return "nvarchar(max)"
+		}
+	default:
+		switch columnType {
+		case "int", "time":
+			return "bigint"
+		case "float":
+			return "double precision"
+		default:
+			return "text"
+		}
+	}
+}
+
+// CreateProfileRecords writes records parsed with a profile. The keys of a
+// record are the column names of the destination table. Gorm quotes the
+// table and column names.
+func (ds *DataService) CreateProfileRecords(table string, records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if ds.Config.Driver == "clickhouse" {
+		table = fmt.Sprintf("%s.%s", ds.Config.Database, table)
+	}
+	if err := ds.Session.Table(table).CreateInBatches(records, ds.BatchSize()).Error; err != nil {
+		return fmt.Errorf("failed to write %s: %w", table, err)
+	}
+	return nil
+}
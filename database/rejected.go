@@ -0,0 +1,47 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// CreateRejectedRecords writes rows that were rejected while parsing.
+func (ds *DataService) CreateRejectedRecords(records []*models.RejectedRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	session := ds.Session
+	if ds.Config.Driver == "clickhouse" {
+		session = session.Table(fmt.Sprintf("%s.rejected_records", ds.Config.Database))
+	}
+	if err := session.CreateInBatches(records, ds.BatchSize()).Error; err != nil {
+		return fmt.Errorf("failed to write rejected records: %w", err)
+	}
+	return nil
+}
+
+// DeleteRejectedRecords removes the rejected rows of an ingested file.
+func (ds *DataService) DeleteRejectedRecords(id string) error {
+	if ds.Config.Driver == "clickhouse" {
+		query := fmt.Sprintf("ALTER TABLE %s.rejected_records DELETE WHERE ingested_file_id = ? SETTINGS mutations_sync = 1", ds.Config.Database)
+		return ds.Session.Exec(query, id).Error
+	}
+	return ds.Session.Where("ingested_file_id = ?", id).Delete(&models.RejectedRecord{}).Error
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func (ds DataService) CreateUccxContactCallDetails(details []*models.UccxContactCallDetail) error {
+	return ds.WriteUccxContactCallDetails(details)
+}
+
+func (ds *DataService) WriteUccxContactCallDetails(details []*models.UccxContactCallDetail) error {
+	if len(details) == 0 {
+		return nil
+	}
+
+	if ds.Session.Dialector.Name() == "clickhouse" {
+		return ds.writeClickHouseUccxContactCallDetails(details)
+	}
+
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := ds.Session.CreateInBatches(details, limit).Error; err != nil {
+		return fmt.Errorf("failed to write UCCX contact call details: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DataService) writeClickHouseUccxContactCallDetails(details []*models.UccxContactCallDetail) error {
+	batchSize := int(ds.Config.Limit)
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	db := ds.Session
+	tableName := fmt.Sprintf("%s.uccx_contact_call_details", ds.Config.Database)
+
+	for i := 0; i < len(details); i += batchSize {
+		end := i + batchSize
+		if end > len(details) {
+			end = len(details)
+		}
+
+		batch := details[i:end]
+
+		if err := db.Table(tableName).CreateInBatches(batch, len(batch)).Error; err != nil {
+			return fmt.Errorf("failed to write ClickHouse UCCX contact call detail batch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (ds DataService) CreateUccxAgentConnectionDetails(details []*models.UccxAgentConnectionDetail) error {
+	return ds.WriteUccxAgentConnectionDetails(details)
+}
+
+func (ds *DataService) WriteUccxAgentConnectionDetails(details []*models.UccxAgentConnectionDetail) error {
+	if len(details) == 0 {
+		return nil
+	}
+
+	if ds.Session.Dialector.Name() == "clickhouse" {
+		return ds.writeClickHouseUccxAgentConnectionDetails(details)
+	}
+
+	limit := int(ds.Config.Limit)
+	if limit <= 0 {
+		limit = 100
+	}
+	if err := ds.Session.CreateInBatches(details, limit).Error; err != nil {
+		return fmt.Errorf("failed to write UCCX agent connection details: %w", err)
+	}
+
+	return nil
+}
+
+func (ds *DataService) writeClickHouseUccxAgentConnectionDetails(details []*models.UccxAgentConnectionDetail) error {
+	batchSize := int(ds.Config.Limit)
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	db := ds.Session
+	tableName := fmt.Sprintf("%s.uccx_agent_connection_details", ds.Config.Database)
+
+	for i := 0; i < len(details); i += batchSize {
+		end := i + batchSize
+		if end > len(details) {
+			end = len(details)
+		}
+
+		batch := details[i:end]
+
+		if err := db.Table(tableName).CreateInBatches(batch, len(batch)).Error; err != nil {
+			return fmt.Errorf("failed to write ClickHouse UCCX agent connection detail batch: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"gorm.io/gorm"
+)
+
+// view is a view created on top of the record tables. The query is a format
+// string given the table prefix, which is the database name and a dot on
+// ClickHouse.
+type view struct {
+	name  string
+	query func(text func(expression string) string) string
+}
+
+// views are created after the tables, in order.
+var views = []view{
+	{"normalized_calls", normalizedCallsQuery},
+	{"uccx_agent_calls", uccxAgentCallsQuery},
+	{"cucm_cdr_descriptions", cucmCdrDescriptionsQuery},
+	{"cube_cdr_descriptions", cubeCdrDescriptionsQuery},
+	{"end_to_end_calls", endToEndCallsQuery},
+}
+
+// normalizedCallsQuery is one row per call leg of every call control, so
+// CUCM and Webex Calling can be reported on together. Times are Unix seconds.
+func normalizedCallsQuery(text func(expression string) string) string {
+	return `
+		SELECT 'cucm' AS source,
+			id,
+			ingested_file_id,
+			` + text("globalcallid_callid") + ` AS call_id,
+			` + text("origlegcallidentifier") + ` AS leg_id,
+			datetimeorigination AS start_time,
+			CASE WHEN datetimeconnect = 0 THEN NULL ELSE datetimeconnect END AS answer_time,
+			datetimedisconnect AS end_time,
+			duration,
+			callingpartynumber AS calling_number,
+			finalcalledpartynumber AS called_number,
+			` + text("CASE WHEN destcause_value > 0 THEN destcause_value ELSE origcause_value END") + ` AS release_cause
+		FROM %[1]scucm_cdrs
+		UNION ALL
+		SELECT 'webex' AS source,
+			id,
+			ingested_file_id,
+			correlationid AS call_id,
+			localcallid AS leg_id,
+			starttime AS start_time,
+			answertime AS answer_time,
+			COALESCE(releasetime, starttime + duration) AS end_time,
+			duration,
+			callingnumber AS calling_number,
+			callednumber AS called_number,
+			releasecause AS release_cause
+		FROM %[1]swebex_cdrs`
+}
+
+// uccxAgentCallsQuery is one row per agent a UCCX contact was presented to,
+// with the CUCM Global Call ID of the contact and the handle time of the
+// agent in seconds.
+func uccxAgentCallsQuery(text func(expression string) string) string {
+	return `
+		SELECT a.id,
+			a.ingested_file_id,
+			a.sessionid,
+			a.sessionseqnum,
+			a.nodeid,
+			a.profileid,
+			c.globalcallid_callid,
+			c.applicationname,
+			c.callednumber,
+			c.originatordn,
+			a.resourceid,
+			a.qindex,
+			a.startdatetime,
+			a.enddatetime,
+			a.ringtime,
+			a.talktime,
+			a.holdtime,
+			a.worktime,
+			COALESCE(a.talktime, 0) + COALESCE(a.holdtime, 0) + COALESCE(a.worktime, 0) AS handletime
+		FROM %[1]succx_agent_connection_details a
+		LEFT JOIN %[1]succx_contact_call_details c
+			ON c.sessionid = a.sessionid
+			AND c.sessionseqnum = a.sessionseqnum
+			AND c.nodeid = a.nodeid
+			AND c.profileid = a.profileid`
+}
+
+// cucmCdrDescriptionsQuery is one row per CUCM CDR with the descriptions of
+// its cause, redirect reason and on behalf of codes.
+func cucmCdrDescriptionsQuery(text func(expression string) string) string {
+	return `
+		SELECT c.id,
+			c.globalcallid_callid,
+			c.origcause_value,
+			oc.description AS origcause_description,
+			oc.category AS origcause_category,
+			c.destcause_value,
+			dc.description AS destcause_description,
+			dc.category AS destcause_category,
+			c.origcalledpartyredirectreason,
+			orr.description AS origcalledpartyredirectreason_description,
+			c.lastredirectredirectreason,
+			lrr.description AS lastredirectredirectreason_description,
+			c.origcallterminationonbehalfof,
+			oob.description AS origcallterminationonbehalfof_description,
+			c.destcallterminationonbehalfof,
+			dob.description AS destcallterminationonbehalfof_description,
+			c.origcalledpartyredirectonbehalfof,
+			orob.description AS origcalledpartyredirectonbehalfof_description,
+			c.lastredirectredirectonbehalfof,
+			lrob.description AS lastredirectredirectonbehalfof_description,
+			c.joinonbehalfof,
+			job.description AS joinonbehalfof_description
+		FROM %[1]scucm_cdrs c
+		LEFT JOIN %[1]sq850_causes oc ON oc.code = c.origcause_value
+		LEFT JOIN %[1]sq850_causes dc ON dc.code = c.destcause_value
+		LEFT JOIN %[1]scucm_redirect_reasons orr ON orr.code = c.origcalledpartyredirectreason
+		LEFT JOIN %[1]scucm_redirect_reasons lrr ON lrr.code = c.lastredirectredirectreason
+		LEFT JOIN %[1]scucm_on_behalf_of_codes oob ON oob.code = c.origcallterminationonbehalfof
+		LEFT JOIN %[1]scucm_on_behalf_of_codes dob ON dob.code = c.destcallterminationonbehalfof
+		LEFT JOIN %[1]scucm_on_behalf_of_codes orob ON orob.code = c.origcalledpartyredirectonbehalfof
+		LEFT JOIN %[1]scucm_on_behalf_of_codes lrob ON lrob.code = c.lastredirectredirectonbehalfof
+		LEFT JOIN %[1]scucm_on_behalf_of_codes job ON job.code = c.joinonbehalfof`
+}
+
+// cubeCdrDescriptionsQuery is one row per CUBE CDR with the description of
+// its disconnect cause, which CUBE writes in hexadecimal.
+func cubeCdrDescriptionsQuery(text func(expression string) string) string {
+	return `
+		SELECT c.id,
+			c.call_id,
+			c.h323_disconnect_cause,
+			q.code AS disconnect_cause_value,
+			q.description AS disconnect_cause_description,
+			q.category AS disconnect_cause_category
+		FROM %[1]scube_cdrs c
+		LEFT JOIN %[1]sq850_causes q ON q.hex = c.h323_disconnect_cause`
+}
+
+// endToEndCallsQuery is one row per CUCM CDR linked to a CUBE call, from the
+// phone on CUCM to the carrier behind the CUBE. The phone and trunk device
+// and the carrier side of the CUBE call depend on the direction of the link.
+func endToEndCallsQuery(text func(expression string) string) string {
+	return `
+		SELECT l.cucm_cdr_id,
+			g.call_id AS cucm_call_id,
+			l.cube_call_id,
+			l.direction,
+			l.method,
+			c.globalcallid_callid,
+			c.datetimeorigination AS start_time,
+			CASE WHEN c.datetimeconnect = 0 THEN NULL ELSE c.datetimeconnect END AS answer_time,
+			c.datetimedisconnect AS end_time,
+			c.callingpartynumber AS calling_number,
+			c.finalcalledpartynumber AS called_number,
+			CASE WHEN l.direction = 'outbound' THEN c.origdevicename ELSE c.destdevicename END AS phone_device,
+			CASE WHEN l.direction = 'outbound' THEN c.destdevicename ELSE c.origdevicename END AS trunk_device,
+			k.disposition AS cucm_disposition,
+			k.min_mlqk AS cucm_min_mlqk,
+			b.hostname AS cube_hostname,
+			b.h323_conf_id,
+			b.ingress_peer_id,
+			b.ingress_trunkgroup,
+			b.egress_peer_id,
+			b.egress_trunkgroup,
+			CASE WHEN l.direction = 'outbound' THEN b.egress_peer_address ELSE b.ingress_peer_address END AS carrier_address,
+			b.gw_rxd_cgn,
+			b.gw_rxd_cdn,
+			b.gw_final_xlated_cgn,
+			b.gw_final_xlated_cdn,
+			b.setup_time AS cube_setup_time,
+			b.connect_time AS cube_connect_time,
+			b.disconnect_time AS cube_disconnect_time,
+			b.disconnect_cause AS cube_disconnect_cause,
+			CASE WHEN l.direction = 'outbound' THEN b.egress_lost_packets ELSE b.ingress_lost_packets END AS carrier_lost_packets
+		FROM %[1]scucm_cube_links l
+		JOIN %[1]scucm_cdrs c ON c.id = l.cucm_cdr_id
+		JOIN %[1]scube_calls b ON b.id = l.cube_call_id
+		LEFT JOIN %[1]scucm_call_legs g ON g.cucm_cdr_id = l.cucm_cdr_id
+		LEFT JOIN %[1]scucm_calls k ON k.id = g.call_id`
+}
+
+// createViews creates or replaces the views. databaseName is only set on
+// ClickHouse, where the tables live in that database.
+func createViews(db *gorm.DB, databaseName string) {
+	driver := db.Dialector.Name()
+
+	prefix := ""
+	if databaseName != "" {
+		prefix = databaseName + "."
+	}
+
+	for _, v := range views {
+		logger.Info("Creating view %s...\n", v.name)
+		query := fmt.Sprintf(v.query(textExpression(driver)), prefix)
+
+		var err error
+		if driver == "clickhouse" {
+			err = db.Exec(fmt.Sprintf("CREATE OR REPLACE VIEW %s%s AS %s", prefix, v.name, query)).Error
+		} else {
+			// Views are dropped first, CREATE OR REPLACE cannot change the
+			// columns of a view on every driver
+			err = db.Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", v.name)).Error
+			if err == nil {
+				err = db.Exec(fmt.Sprintf("CREATE VIEW %s AS %s", v.name, query)).Error
+			}
+		}
+		if err != nil {
+			logger.Error("Failed to create view %s: %s\n", v.name, err)
+			return
+		}
+	}
+}
+
+// textExpression returns a function that converts an SQL expression to text
+// on the given driver, keeping NULL values.
+func textExpression(driver string) func(expression string) string {
+	return func(expression string) string {
+		switch driver {
+		case "clickhouse":
+			return fmt.Sprintf("toString(%s)", expression)
+		case "mysql":
+			return fmt.Sprintf("CAST(%s AS CHAR)", expression)
+		case "sqlserver":
+			return fmt.Sprintf("CAST(%s AS NVARCHAR(64))", expression)
+		default:
+			return fmt.Sprintf("CAST(%s AS TEXT)", expression)
+		}
+	}
+}
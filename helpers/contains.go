@@ -17,6 +17,9 @@
 package helpers
 
 func ContainsString(slice *[]string, s *string) bool {
+	if s == nil {
+		return false
+	}
 	for _, v := range *slice {
 		if v == *s {
 			return true
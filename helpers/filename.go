@@ -54,7 +54,7 @@ func ChangeFileNameToCompleteAndMoveOrDelete(input string, output string, delete
 		return err
 	}
 	if _, err := os.Stat(completedFilePath); errors.Is(err, os.ErrNotExist) {
-		err := os.Mkdir(completedFilePath, os.ModePerm)
+		err := os.MkdirAll(completedFilePath, os.ModePerm)
 		if err != nil {
 			logger.Error(err.Error())
 		}
@@ -69,7 +69,7 @@ func ChangeFileNameToFailedAndMove(input, output string) error {
 	baseFileName := filepath.Base(input)
 	failedFilePath := filepath.Join(OutputPath, "failed")
 	if _, err := os.Stat(failedFilePath); errors.Is(err, os.ErrNotExist) {
-		err := os.Mkdir(failedFilePath, os.ModePerm)
+		err := os.MkdirAll(failedFilePath, os.ModePerm)
 		if err != nil {
 			logger.Error(err.Error())
 		}
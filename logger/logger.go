@@ -63,6 +63,10 @@ func Info(format string, a ...any) {
 	Logger.Info(fmt.Sprintf(format, a...))
 }
 
+func Warn(format string, a ...any) {
+	Logger.Warn(fmt.Sprintf(format, a...))
+}
+
 func Error(format string, a ...any) {
 	Logger.Error(fmt.Sprintf(format, a...))
 }
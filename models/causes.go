@@ -0,0 +1,254 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import "fmt"
+
+// Q850Cause is a release cause of ITU-T Q.850, or one of the Cisco specific
+// causes CUCM reports in the same columns. Hex is the code as CUBE writes it
+// in h323-disconnect-cause.
+type Q850Cause struct {
+	Code        int64 `gorm:"primaryKey;autoIncrement:false"`
+	Hex         string
+	Description string
+	Category    string
+}
+
+// CucmRedirectReason is a CUCM redirect reason code, as in
+// origcalledpartyredirectreason and lastredirectredirectreason.
+type CucmRedirectReason struct {
+	Code        int64 `gorm:"primaryKey;autoIncrement:false"`
+	Description string
+}
+
+// CucmOnBehalfOfCode is a CUCM on behalf of code, as in
+// origcallterminationonbehalfof, destcallterminationonbehalfof,
+// joinonbehalfof and the redirect on behalf of columns.
+type CucmOnBehalfOfCode struct {
+	Code        int64 `gorm:"primaryKey;autoIncrement:false"`
+	Description string
+}
+
+// Categories of the release causes, after the cause classes of Q.850.
+const (
+	CauseCategoryNormal         = "Normal event"
+	CauseCategoryResource       = "Resource unavailable"
+	CauseCategoryNotAvailable   = "Service or option not available"
+	CauseCategoryNotImplemented = "Service or option not implemented"
+	CauseCategoryInvalidMessage = "Invalid message"
+	CauseCategoryProtocolError  = "Protocol error"
+	CauseCategoryInterworking   = "Interworking"
+	CauseCategoryCisco          = "Cisco specific"
+)
+
+// q850Descriptions are the release causes by code.
+var q850Descriptions = map[int64]string{
+	0:      "No error",
+	1:      "Unallocated (unassigned) number",
+	2:      "No route to specified transit network",
+	3:      "No route to destination",
+	4:      "Send special information tone",
+	5:      "Misdialed trunk prefix",
+	6:      "Channel unacceptable",
+	7:      "Call awarded and being delivered in an established channel",
+	8:      "Preemption",
+	9:      "Preemption, circuit reserved for reuse",
+	14:     "QoR: ported number",
+	16:     "Normal call clearing",
+	17:     "User busy",
+	18:     "No user responding",
+	19:     "No answer from user (user alerted)",
+	20:     "Subscriber absent",
+	21:     "Call rejected",
+	22:     "Number changed",
+	23:     "Redirection to new destination",
+	25:     "Exchange routing error",
+	26:     "Non-selected user clearing",
+	27:     "Destination out of order",
+	28:     "Invalid number format (address incomplete)",
+	29:     "Facility rejected",
+	30:     "Response to STATUS ENQUIRY",
+	31:     "Normal, unspecified",
+	34:     "No circuit/channel available",
+	38:     "Network out of order",
+	39:     "Permanent frame mode connection out of service",
+	40:     "Permanent frame mode connection operational",
+	41:     "Temporary failure",
+	42:     "Switching equipment congestion",
+	43:     "Access information discarded",
+	44:     "Requested circuit/channel not available",
+	46:     "Precedence call blocked",
+	47:     "Resource unavailable, unspecified",
+	49:     "Quality of service not available",
+	50:     "Requested facility not subscribed",
+	53:     "Outgoing calls barred within CUG",
+	55:     "Incoming calls barred within CUG",
+	57:     "Bearer capability not authorized",
+	58:     "Bearer capability not presently available",
+	62:     "Inconsistency in designated outgoing access information and subscriber class",
+	63:     "Service or option not available, unspecified",
+	65:     "Bearer capability not implemented",
+	66:     "Channel type not implemented",
+	69:     "Requested facility not implemented",
+	70:     "Only restricted digital information bearer capability is available",
+	79:     "Service or option not implemented, unspecified",
+	81:     "Invalid call reference value",
+	82:     "Identified channel does not exist",
+	83:     "A suspended call exists, but this call identity does not",
+	84:     "Call identity in use",
+	85:     "No call suspended",
+	86:     "Call having the requested call identity has been cleared",
+	87:     "User not member of CUG",
+	88:     "Incompatible destination",
+	90:     "Non-existent CUG",
+	91:     "Invalid transit network selection",
+	95:     "Invalid message, unspecified",
+	96:     "Mandatory information element is missing",
+	97:     "Message type non-existent or not implemented",
+	98:     "Message not compatible with call state or message type non-existent or not implemented",
+	99:     "Information element/parameter non-existent or not implemented",
+	100:    "Invalid information element contents",
+	101:    "Message not compatible with call state",
+	102:    "Recovery on timer expiry",
+	103:    "Parameter non-existent or not implemented, passed on",
+	110:    "Message with unrecognized parameter, discarded",
+	111:    "Protocol error, unspecified",
+	127:    "Interworking, unspecified",
+	262144: "Conference full",
+	393216: "Call split",
+	458752: "Conference drop any party/Conference drop last party",
+}
+
+// CucmRedirectReasons are the CUCM redirect reason codes.
+var CucmRedirectReasons = []CucmRedirectReason{
+	{0, "Unknown"},
+	{1, "Call Forward Busy"},
+	{2, "Call Forward No Answer"},
+	{4, "Call Transfer"},
+	{5, "Call Pickup"},
+	{7, "Call Park"},
+	{8, "Call Park Pickup"},
+	{9, "CPE Out of Order"},
+	{10, "Call Forward"},
+	{11, "Call Park Reversion"},
+	{15, "Call Forward All"},
+	{18, "Call Deflection"},
+	{34, "Blind Transfer"},
+	{50, "Call Immediate Divert"},
+	{66, "Call Forward Alternate Party"},
+	{82, "Call Forward On Failure"},
+	{98, "Conference"},
+	{114, "Barge"},
+	{129, "Aar"},
+	{130, "Refer"},
+	{146, "Callback"},
+	{162, "Mobility"},
+	{178, "Mobility HandIn"},
+	{194, "Mobility HandOut"},
+	{210, "Mobility Follow Me"},
+	{226, "Mobility Redial"},
+	{242, "Recording"},
+	{258, "Monitoring"},
+	{274, "Mobility IVR"},
+	{290, "Mobility DVOR"},
+	{306, "Mobility EFA"},
+	{322, "Mobility Session Handoff"},
+	{338, "Mobility Cell Pickup"},
+	{354, "Click to Conference"},
+	{370, "Forward No Retrieve"},
+	{386, "Forward No Retrieve Send Back to Parker"},
+	{402, "Call Control Discovery"},
+	{418, "Intercompany Media Engine"},
+}
+
+// CucmOnBehalfOfCodes are the CUCM on behalf of codes.
+var CucmOnBehalfOfCodes = []CucmOnBehalfOfCode{
+	{0, "Unknown"},
+	{1, "CTI Line"},
+	{2, "Unicast Shared Resource Provider"},
+	{3, "Call Park"},
+	{4, "Conference"},
+	{5, "Call Forward"},
+	{6, "Meet-Me Conference Intercepts"},
+	{7, "Message Waiting Indication"},
+	{8, "Multicast Shared Resource Provider"},
+	{10, "Transfer"},
+	{11, "SSAPI Manager"},
+	{12, "Device"},
+	{13, "Call Control"},
+	{14, "Immediate Divert"},
+	{15, "Barge"},
+	{16, "Pickup"},
+	{17, "Refer"},
+	{18, "Replaces"},
+	{19, "Redirection"},
+	{20, "Callback"},
+	{21, "Path Replacement"},
+	{22, "FAC/CMC Manager"},
+	{23, "Malicious Call"},
+	{24, "Mobility"},
+	{25, "Aar"},
+	{26, "Directed Call Park"},
+	{27, "Recording"},
+	{28, "Monitoring"},
+	{29, "Call Control Discovery"},
+	{30, "Intercompany Media Engine"},
+}
+
+// Q850Causes returns every release cause, in code order.
+func Q850Causes() []Q850Cause {
+	causes := make([]Q850Cause, 0, len(q850Descriptions))
+	for code := int64(0); code <= 127; code++ {
+		if description, ok := q850Descriptions[code]; ok {
+			causes = append(causes, newQ850Cause(code, description))
+		}
+	}
+	for _, code := range []int64{262144, 393216, 458752} {
+		causes = append(causes, newQ850Cause(code, q850Descriptions[code]))
+	}
+	return causes
+}
+
+func newQ850Cause(code int64, description string) Q850Cause {
+	return Q850Cause{
+		Code:        code,
+		Hex:         fmt.Sprintf("%X", code),
+		Description: description,
+		Category:    q850Category(code),
+	}
+}
+
+// q850Category returns the cause class of a code.
+func q850Category(code int64) string {
+	switch {
+	case code > 127:
+		return CauseCategoryCisco
+	case code < 32:
+		return CauseCategoryNormal
+	case code < 48:
+		return CauseCategoryResource
+	case code < 64:
+		return CauseCategoryNotAvailable
+	case code < 80:
+		return CauseCategoryNotImplemented
+	case code < 96:
+		return CauseCategoryInvalidMessage
+	case code < 112:
+		return CauseCategoryProtocolError
+	default:
+		return CauseCategoryInterworking
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// headsetMetrics are the metrics of a Cisco headset reported in the
+// headsetMetrics field of a CMR.
+type headsetMetrics struct {
+	Model      *string
+	Firmware   *string
+	Connection *string
+	Battery    *int64
+	Signal     *int64
+}
+
+// headsetKeys maps the keys of the headset metrics, compared without case
+// and punctuation, to the metric they hold. Phone firmware releases name
+// them differently.
+var headsetKeys = map[string]string{
+	"model":           "model",
+	"headsetmodel":    "model",
+	"hsmodel":         "model",
+	"firmware":        "firmware",
+	"firmwareversion": "firmware",
+	"fw":              "firmware",
+	"fwversion":       "firmware",
+	"swversion":       "firmware",
+	"connection":      "connection",
+	"connectiontype":  "connection",
+	"conntype":        "connection",
+	"interface":       "connection",
+	"battery":         "battery",
+	"batterylevel":    "battery",
+	"batt":            "battery",
+	"rssi":            "signal",
+	"signal":          "signal",
+	"signalstrength":  "signal",
+	"wirelesssignal":  "signal",
+}
+
+// parseHeadsetMetrics reads the key/value pairs of the headset metrics,
+// separated by semicolons or commas. Keys that are not known are skipped,
+// they stay in the Headsetmetrics column. A value that cannot be converted is
+// left nil and returned as the error once every pair has been read.
+func parseHeadsetMetrics(s *string) (headsetMetrics, error) {
+	var metrics headsetMetrics
+	if s == nil {
+		return metrics, nil
+	}
+
+	var err, firstErr error
+	pairs := strings.FieldsFunc(*s, func(r rune) bool { return r == ';' || r == ',' })
+	for _, pair := range pairs {
+		separator := strings.IndexAny(pair, "=:")
+		if separator < 0 {
+			continue
+		}
+		key := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, pair[:separator])
+		raw := pair[separator+1:]
+		value := trimmedString(&raw)
+		if value == nil {
+			continue
+		}
+
+		switch headsetKeys[key] {
+		case "model":
+			metrics.Model = value
+		case "firmware":
+			metrics.Firmware = value
+		case "connection":
+			metrics.Connection = value
+		case "battery":
+			metrics.Battery, err = metricInt(*value, "%")
+		case "signal":
+			metrics.Signal, err = metricInt(*value, "dBm")
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", key, err)
+		}
+		err = nil
+	}
+	return metrics, firstErr
+}
+
+// metricInt converts a whole number that may carry a unit, such as 80% or
+// -62dBm.
+func metricInt(s string, unit string) (*int64, error) {
+	trimmed := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), unit))
+	value, err := strconv.ParseInt(trimmed, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// varVQJSON returns every key of the VarVQ metrics as a JSON object. Numbers
+// are kept as JSON numbers and everything else, such as the codec, as
+// strings.
+func varVQJSON(metrics map[string]string) (*string, error) {
+	if len(metrics) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]interface{}, len(metrics))
+	for key, value := range metrics {
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil && json.Valid([]byte(value)) {
+			values[key] = json.Number(value)
+		} else {
+			values[key] = value
+		}
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	result := string(encoded)
+	return &result, nil
+}
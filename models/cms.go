@@ -0,0 +1,93 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// CMS record types.
+const (
+	CMSCallStart     = "callStart"
+	CMSCallEnd       = "callEnd"
+	CMSCallLegStart  = "callLegStart"
+	CMSCallLegUpdate = "callLegUpdate"
+	CMSCallLegEnd    = "callLegEnd"
+)
+
+// CMSCall is a call on Cisco Meeting Server, such as a space being in use,
+// built from its callStart and callEnd records. Times are Unix seconds.
+type CMSCall struct {
+	ID string
+	// CdrSession is the CDR session of the CMS node that sent the records.
+	CdrSession        *string
+	Name              *string
+	OwnerName         *string
+	CoSpace           *string
+	CallCorrelator    *string `gorm:"index"`
+	CallType          *string
+	CdrTag            *string
+	StartTime         *int64
+	EndTime           *int64
+	DurationSeconds   *int64
+	CallLegsCompleted *int64
+	CallLegsMaxActive *int64
+	// UpdatedAt is the version of the call in nanoseconds. ClickHouse keeps
+	// the newest version of every call.
+	UpdatedAt int64 `gorm:"autoUpdateTime:nano"`
+}
+
+// CMSCallLeg is a participant connection of a CMS call, built from its
+// callLegStart, callLegUpdate and callLegEnd records. CallId is the CMS call
+// the leg is currently in. SipCallId is the Call-ID of SIP legs, which CUCM
+// records as the protocol call reference of its trunk leg.
+type CMSCallLeg struct {
+	ID                string
+	CallId            *string `gorm:"index"`
+	CdrSession        *string
+	DisplayName       *string
+	LocalAddress      *string
+	RemoteAddress     *string
+	RemoteParty       *string
+	Type              *string
+	SubType           *string
+	Direction         *string
+	SipCallId         *string `gorm:"index"`
+	GroupId           *string
+	OwnerId           *string
+	CdrTag            *string
+	GuestConnection   *bool
+	Recording         *bool
+	Streaming         *bool
+	State             *string
+	StartTime         *int64
+	ConnectTime       *int64
+	EndTime           *int64
+	Reason            *string
+	RemoteTeardown    *bool
+	DurationSeconds   *int64
+	ActivatedDuration *int64
+	EncryptedMedia    *bool
+	UnencryptedMedia  *bool
+	RxAudioCodec      *string
+	TxAudioCodec      *string
+	RxVideoCodec      *string
+	RxVideoMaxWidth   *int64
+	RxVideoMaxHeight  *int64
+	TxVideoCodec      *string
+	TxVideoMaxWidth   *int64
+	TxVideoMaxHeight  *int64
+	// Alarms are the media alarms of the leg as type:percentage pairs, e.g.
+	// "packetLoss:12.5,excessiveJitter:3.1".
+	Alarms    *string
+	UpdatedAt int64 `gorm:"autoUpdateTime:nano"`
+}
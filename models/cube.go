@@ -17,6 +17,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -150,6 +151,7 @@ type CubeCDRs []*CubeCDR
 
 type CubeCDR struct {
 	ID                  string
+	IngestedFileId      *string `gorm:"index"`
 	InvalidNTPReference bool
 	Hostname            *string // `gorm:"uniqueIndex:cube_cdr_index"`
 	Filename            *string
@@ -228,7 +230,7 @@ type CubeCDR struct {
 	GwRxdCgn                        *string
 	GwRxdRdn                        *string
 	H323CallOrigin                  *string
-	H323ConfId                      *string // `gorm:"uniqueIndex:cube_cdr_index"`
+	H323ConfId                      *string `gorm:"index"`
 	H323ConnectTime                 *int64
 	H323DisconnectCause             *string
 	H323DisconnectTime              *int64
@@ -314,6 +316,9 @@ type CubeCDR struct {
 	VadEnable                       *bool
 	VoiceFeature                    *string
 	VoiceTxDuration                 *int64
+	// FeatureEvents is the feature invocation of the record, written to
+	// cube_feature_events with the record
+	FeatureEvents []*CubeFeatureEvent `gorm:"-"`
 }
 
 func (raw *RawCubeCDR) Parse(filename string) (*CubeCDR, error) {
@@ -582,9 +587,12 @@ func (raw *RawCubeCDR) Parse(filename string) (*CubeCDR, error) {
 		logger.Error("Error parsing BytesOut: %s in %s", err, filename)
 	}
 
+	// The call id links the legs of a call, so unlike the other fields it
+	// rejects the record when it cannot be converted
 	ParsedCallId, err = helpers.ConvertStringToInt64(raw.CallId)
 	if err != nil {
 		logger.Error("Error parsing CallId: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing CallId: %s", err)
 	}
 
 	ParsedCdrType, err = helpers.ConvertStringToInt64(raw.CdrType)
@@ -825,6 +833,8 @@ func (raw *RawCubeCDR) Parse(filename string) (*CubeCDR, error) {
 		if !(helpers.ContainsString(&H323CauseCodes, TrimmedH323DisconnectCause)) {
 			ParsedH323DisconnectCause = nil
 			logger.Error("Error parsing H323DisconnectCause: %s in %s", *TrimmedH323DisconnectCause, filename)
+		} else {
+			ParsedH323DisconnectCause = TrimmedH323DisconnectCause
 		}
 	}
 
@@ -959,7 +969,7 @@ func (raw *RawCubeCDR) Parse(filename string) (*CubeCDR, error) {
 		ParsedHoldPhoneTag = HoldPhoneTag
 	}
 
-	return &CubeCDR{
+	cdr := &CubeCDR{
 		ID:                              uuid.New().String(),
 		FileTimestamp:                   ParsedFiletimestamp,
 		RecordTimestamp:                 ParsedRecordTimestamp,
@@ -1116,7 +1126,11 @@ func (raw *RawCubeCDR) Parse(filename string) (*CubeCDR, error) {
 		Username:                        ParsedUsername,
 		VadEnable:                       ParsedVadEnable,
 		VoiceFeature:                    ParsedVoiceFeature,
-	}, nil
+	}
+	if event := raw.featureEvent(cdr, ParsedFeatureIdField2); event != nil {
+		cdr.FeatureEvents = []*CubeFeatureEvent{event}
+	}
+	return cdr, nil
 }
 
 type RawCubeCDR struct {
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// CubeCall is a call through a CUBE, paired from the CDRs of its incoming
+// and outgoing legs. The legs of a call share the H323ConfId on the gateway,
+// and the ID is the hostname and H323ConfId, see CubeCallKey. Times are Unix
+// seconds.
+//
+// The received and translated numbers are the stages of digit manipulation
+// on the gateway, from the numbers received on the ingress leg to the final
+// translated numbers sent on the egress leg. The media columns of each side
+// are the RTP statistics of that leg.
+type CubeCall struct {
+	ID                        string
+	Hostname                  *string
+	H323ConfId                *string `gorm:"index"`
+	Legs                      int64
+	IngressCdrId              *string `gorm:"index"`
+	IngressCallId             *int64
+	IngressLegType            *int64
+	IngressPeerId             *int64
+	IngressTrunkgroup         *string
+	IngressPeerAddress        *string
+	EgressCdrId               *string `gorm:"index"`
+	EgressCallId              *int64
+	EgressLegType             *int64
+	EgressPeerId              *int64
+	EgressTrunkgroup          *string
+	EgressPeerAddress         *string
+	GwRxdCgn                  *string
+	GwRxdCdn                  *string
+	GwRxdRdn                  *string
+	GwCollectedCdn            *string
+	GkXlatedCgn               *string
+	GkXlatedCdn               *string
+	GwFinalXlatedCgn          *string
+	GwFinalXlatedCdn          *string
+	GwFinalXlatedRdn          *string
+	SetupTime                 *int64 `gorm:"index"`
+	AlertTime                 *int64
+	ConnectTime               *int64
+	DisconnectTime            *int64 `gorm:"index"`
+	Duration                  *int64
+	DisconnectCause           *string
+	DisconnectText            *string
+	IngressCodec              *string
+	IngressPaksIn             *int64
+	IngressPaksOut            *int64
+	IngressLostPackets        *int64
+	IngressLatePackets        *int64
+	IngressEarlyPackets       *int64
+	IngressRoundTripDelay     *int64
+	IngressRemoteMediaAddress *string
+	EgressCodec               *string
+	EgressPaksIn              *int64
+	EgressPaksOut             *int64
+	EgressLostPackets         *int64
+	EgressLatePackets         *int64
+	EgressEarlyPackets        *int64
+	EgressRoundTripDelay      *int64
+	EgressRemoteMediaAddress  *string
+	UpdatedAt                 int64
+}
+
+// CubeCallKey identifies the CDRs of a call on a gateway.
+type CubeCallKey struct {
+	Hostname   string
+	H323ConfId string
+}
+
+// ID returns the key as a single string, the ID of the CubeCall.
+func (key CubeCallKey) ID() string {
+	return key.Hostname + "/" + key.H323ConfId
+}
+
+// CallKey returns the CubeCallKey of a CDR, and false if it has no
+// H323ConfId.
+func (cdr *CubeCDR) CallKey() (CubeCallKey, bool) {
+	var key CubeCallKey
+	if cdr.H323ConfId == nil || *cdr.H323ConfId == "" {
+		return key, false
+	}
+	key.H323ConfId = *cdr.H323ConfId
+	if cdr.Hostname != nil {
+		key.Hostname = *cdr.Hostname
+	}
+	return key, true
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CubeFeatureEvent is a supplementary service invoked on a CUBE call leg,
+// such as a transfer, forward or hold, read from the feature fields of a CDR.
+// CubeCdrId is the ID of that CDR. Time is Unix seconds.
+//
+// CallingNumber and CalledNumber are the parties of the feature: the calling
+// and called number of a TWC, the forwarding and forwarded-to number of a
+// forward, the transferring and transferred-to party of a transfer, and the
+// holding and held number of a hold or resume.
+type CubeFeatureEvent struct {
+	ID             string
+	IngestedFileId *string `gorm:"index"`
+	CubeCdrId      string  `gorm:"index"`
+	Filename       *string
+	CallId         *int64
+	Feature        *string `gorm:"index"`
+	Status         *string
+	Time           *int64
+	FeatureId      *string
+	CorrelationId  *string `gorm:"index"`
+	LegId          *string
+	CallingNumber  *string
+	CalledNumber   *string
+	// Fields holds the non-empty feature fields as a JSON object, from
+	// field1 to field12
+	Fields *string
+}
+
+// cubeFeatureLayout is the position of the common values among the feature
+// fields of a feature code, starting at 1. Zero means the feature has no such
+// field.
+type cubeFeatureLayout struct {
+	status, featureID, correlationID, legID, calling, called int
+}
+
+// cubeFeatureLayouts are the feature fields of the known feature codes.
+// Codes that are not listed use cubeDefaultFeatureLayout.
+var cubeFeatureLayouts = map[string]cubeFeatureLayout{
+	TWCCode:    {status: 5, featureID: 7, correlationID: 6, legID: 8, calling: 3, called: 4},
+	CFACode:    {status: 3, featureID: 4, correlationID: 5, legID: 6, calling: 9, called: 11},
+	CFNACode:   {status: 3, featureID: 4, correlationID: 5, legID: 6, calling: 9, called: 11},
+	CFBYCode:   {status: 3, featureID: 4, correlationID: 5, legID: 6, calling: 9, called: 11},
+	BXFERCode:  {status: 3, featureID: 4, correlationID: 5, legID: 7, calling: 10, called: 12},
+	CXFERCode:  {status: 3, featureID: 4, correlationID: 5, legID: 7, calling: 10, called: 12},
+	HOLDCode:   {status: 3, featureID: 4, correlationID: 5, legID: 6, calling: 8, called: 9},
+	RESUMECode: {status: 3, featureID: 4, correlationID: 5, legID: 6, calling: 8, called: 9},
+}
+
+// cubeDefaultFeatureLayout is the order most feature VSAs start with after
+// the name and time: fn, ft, frs, fid, fcid and legID.
+var cubeDefaultFeatureLayout = cubeFeatureLayout{status: 3, featureID: 4, correlationID: 5, legID: 6}
+
+// featureEvent returns the feature invocation of the record, or nil if it has
+// none. featureTime is the parsed time of the second field.
+func (raw *RawCubeCDR) featureEvent(cdr *CubeCDR, featureTime *int64) *CubeFeatureEvent {
+	fields := []*string{
+		raw.FeatureIdField1, raw.FeatureIdField2, raw.FeatureIdField3, raw.FeatureIdField4,
+		raw.FeatureIdField5, raw.FeatureIdField6, raw.FeatureIdField7, raw.FeatureIdField8,
+		raw.FeatureIdField9, raw.FeatureIdField10, raw.FeatureIdField11, raw.FeatureIdField12,
+	}
+
+	feature := trimmedString(fields[0])
+	if feature == nil {
+		return nil
+	}
+	code := strings.ToUpper(*feature)
+
+	layout, ok := cubeFeatureLayouts[code]
+	if !ok {
+		layout = cubeDefaultFeatureLayout
+	}
+	field := func(position int) *string {
+		if position == 0 {
+			return nil
+		}
+		return trimmedString(fields[position-1])
+	}
+
+	values := make(map[string]string, len(fields))
+	for i, value := range fields {
+		if trimmed := trimmedString(value); trimmed != nil {
+			values[fmt.Sprintf("field%d", i+1)] = *trimmed
+		}
+	}
+	var encoded *string
+	if data, err := json.Marshal(values); err == nil {
+		text := string(data)
+		encoded = &text
+	}
+
+	return &CubeFeatureEvent{
+		ID:            uuid.New().String(),
+		CubeCdrId:     cdr.ID,
+		Filename:      cdr.Filename,
+		CallId:        cdr.CallId,
+		Feature:       &code,
+		Status:        field(layout.status),
+		Time:          featureTime,
+		FeatureId:     field(layout.featureID),
+		CorrelationId: field(layout.correlationID),
+		LegId:         field(layout.legID),
+		CallingNumber: field(layout.calling),
+		CalledNumber:  field(layout.called),
+		Fields:        encoded,
+	}
+}
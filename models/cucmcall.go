@@ -0,0 +1,167 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CucmCall is a call as the caller saw it, built from every CUCM CDR of the
+// call. Legs of transfers, forwards, conferences and hunt groups are grouped
+// by their Global Call ID, and by their conversation IDs across Global Call
+// IDs. The ID is the Global Call ID of the first leg. Times are Unix seconds.
+type CucmCall struct {
+	ID                         string
+	Globalcallid_Clusterid     *string
+	Globalcallid_Callmanagerid *int64
+	Globalcallid_Callid        *int64 `gorm:"index"`
+	Legs                       int64
+	StartTime                  *int64 `gorm:"index"`
+	AnswerTime                 *int64
+	EndTime                    *int64
+	CallingNumber              *string
+	OriginalCalledNumber       *string
+	// AnsweringNumber and AnsweringDevice are the last party that answered
+	AnsweringNumber *string
+	AnsweringDevice *string
+	HuntPilot       *string
+	// RingTime is the time until the call was first answered, or until it
+	// ended if it was never answered, and TalkTime the sum of the connected
+	// time of every leg
+	RingTime    *int64
+	TalkTime    int64
+	Transferred bool
+	Forwarded   bool
+	Conferenced bool
+	// Chain is a JSON array of the numbers the call was offered to, in
+	// order, with the reason it reached each of them
+	Chain            *string
+	Disposition      string
+	DispositionCause *int64
+	// Cmrs is the number of CMRs matched to the legs of the call. MinMlqk is
+	// the lowest MOS of any side of any leg, MaxJitter and MaxLatency the
+	// highest jitter and latency in milliseconds, and PacketsLost the sum of
+	// the lost packets of every side
+	Cmrs        int64
+	MinMlqk     *float64
+	MaxJitter   *int64
+	MaxLatency  *int64
+	PacketsLost *int64
+	MaxCcr      *float64
+	UpdatedAt   int64
+}
+
+// CucmCallLeg links a CUCM CDR to the call it belongs to. Globalcallid is the
+// key of the Global Call ID of the CDR, see CucmGlobalCallID.Key.
+type CucmCallLeg struct {
+	ID           string
+	CallId       string `gorm:"index"`
+	CucmCdrId    string `gorm:"index"`
+	Globalcallid string `gorm:"index"`
+	Sequence     int64
+	// The voice quality of the originating and destination side of the leg,
+	// from the CMR of each side's phone or trunk. Mlqk is the average MOS of
+	// the call and MlqkMin the lowest MOS of any interval
+	OrigCmrId       *string
+	OrigMlqk        *float64
+	OrigMlqkMin     *float64
+	OrigJitter      *int64
+	OrigLatency     *int64
+	OrigPacketsLost *int64
+	OrigCcr         *float64
+	DestCmrId       *string
+	DestMlqk        *float64
+	DestMlqkMin     *float64
+	DestJitter      *int64
+	DestLatency     *int64
+	DestPacketsLost *int64
+	DestCcr         *float64
+}
+
+// Dispositions of a CucmCall.
+const (
+	CucmCallAnswered  = "answered"
+	CucmCallBusy      = "busy"
+	CucmCallNoAnswer  = "no_answer"
+	CucmCallAbandoned = "abandoned"
+	CucmCallFailed    = "failed"
+)
+
+// CucmGlobalCallID identifies the CDRs of a call within a cluster.
+type CucmGlobalCallID struct {
+	ClusterID     string
+	CallManagerID int64
+	CallID        int64
+}
+
+// Key returns the Global Call ID as a single string, e.g.
+// StandAloneCluster/1/101135.
+func (id CucmGlobalCallID) Key() string {
+	return fmt.Sprintf("%s/%d/%d", id.ClusterID, id.CallManagerID, id.CallID)
+}
+
+// GlobalCallID returns the Global Call ID of a CDR. The cluster ID of the
+// file name is used if the record has none.
+func (cdr *CucmCdr) GlobalCallID() CucmGlobalCallID {
+	return cucmGlobalCallID(cdr.Globalcallid_Clusterid, cdr.FileClusterId, cdr.Globalcallid_Callmanagerid, cdr.Globalcallid_Callid)
+}
+
+// GlobalCallID returns the Global Call ID of a CMR, like CucmCdr.GlobalCallID.
+func (cmr *CucmCmr) GlobalCallID() CucmGlobalCallID {
+	return cucmGlobalCallID(cmr.Globalcallid_Clusterid, cmr.FileClusterId, cmr.Globalcallid_Callmanagerid, cmr.Globalcallid_Callid)
+}
+
+func cucmGlobalCallID(clusterID *string, fileClusterID *string, callManagerID *int64, callID *int64) CucmGlobalCallID {
+	var id CucmGlobalCallID
+	switch {
+	case clusterID != nil && *clusterID != "":
+		id.ClusterID = *clusterID
+	case fileClusterID != nil:
+		id.ClusterID = *fileClusterID
+	}
+	if callManagerID != nil {
+		id.CallManagerID = *callManagerID
+	}
+	if callID != nil {
+		id.CallID = *callID
+	}
+	return id
+}
+
+// ParseCucmGlobalCallID reverses CucmGlobalCallID.Key.
+func ParseCucmGlobalCallID(key string) (CucmGlobalCallID, bool) {
+	var id CucmGlobalCallID
+	callIndex := strings.LastIndex(key, "/")
+	if callIndex < 0 {
+		return id, false
+	}
+	managerIndex := strings.LastIndex(key[:callIndex], "/")
+	if managerIndex < 0 {
+		return id, false
+	}
+
+	var err error
+	if id.CallManagerID, err = strconv.ParseInt(key[managerIndex+1:callIndex], 10, 64); err != nil {
+		return id, false
+	}
+	if id.CallID, err = strconv.ParseInt(key[callIndex+1:], 10, 64); err != nil {
+		return id, false
+	}
+	id.ClusterID = key[:managerIndex]
+	return id, true
+}
@@ -18,6 +18,7 @@ package models
 
 type CucmCdr struct {
 	ID                                      string
+	IngestedFileId                          *string `gorm:"index"`
 	OriginPkid                              *string `gorm:"unique;not null"`
 	FileClusterId                           *string
 	FileNodeId                              *string
@@ -25,8 +26,8 @@ type CucmCdr struct {
 	FileSequenceNumber                      *int64
 	Cdrrecordtype                           *int64
 	Globalcallid_Callmanagerid              *int64
-	Globalcallid_Callid                     *int64
-	Origlegcallidentifier                   *int64
+	Globalcallid_Callid                     *int64 `gorm:"index"`
+	Origlegcallidentifier                   *int64 `gorm:"index"`
 	Datetimeorigination                     *int64
 	Orignodeid                              *int64
 	Origspan                                *int64
@@ -48,7 +49,7 @@ type CucmCdr struct {
 	Origvideotransportaddress_Port          *int64
 	Origrsvpaudiostat                       *int64
 	Origrsvpvideostat                       *int64
-	Destlegcallidentifier                   *int64
+	Destlegcallidentifier                   *int64 `gorm:"index"`
 	Destnodeid                              *int64
 	Destspan                                *int64
 	Destipaddr                              *string
@@ -71,7 +72,7 @@ type CucmCdr struct {
 	Destrsvpaudiostat                       *int64
 	Destrsvpvideostat                       *int64
 	Datetimeconnect                         *int64
-	Datetimedisconnect                      *int64
+	Datetimedisconnect                      *int64 `gorm:"index"`
 	Lastredirectdn                          *string
 	Originalcalledpartynumberpartition      *string
 	Callingpartynumberpartition             *string
@@ -86,7 +87,7 @@ type CucmCdr struct {
 	Lastredirectredirectonbehalfof          *int64
 	Origcalledpartyredirectreason           *int64
 	Lastredirectredirectreason              *int64
-	Destconversationid                      *int64
+	Destconversationid                      *int64 `gorm:"index"`
 	Globalcallid_Clusterid                  *string
 	Joinonbehalfof                          *int64
 	Comment                                 *string
@@ -96,7 +97,7 @@ type CucmCdr struct {
 	Origdtmfmethod                          *int64
 	Destdtmfmethod                          *int64
 	Callsecuredstatus                       *int64
-	Origconversationid                      *int64
+	Origconversationid                      *int64 `gorm:"index"`
 	Origmediacap_Bandwidth                  *int64
 	Destmediacap_Bandwidth                  *int64
 	Authorizationcodevalue                  *string
@@ -117,9 +118,9 @@ type CucmCdr struct {
 	Destvideotransportaddress_Port_Channel2 *int64
 	Destvideochannel_Role_Channel2          *int64
 	Incomingprotocolid                      *int64
-	Incomingprotocolcallref                 *string
+	Incomingprotocolcallref                 *string `gorm:"index"`
 	Outgoingprotocolid                      *int64
-	Outgoingprotocolcallref                 *string
+	Outgoingprotocolcallref                 *string `gorm:"index"`
 	Currentroutingreason                    *int64
 	Origroutingreason                       *int64
 	Lastredirectingroutingreason            *int64
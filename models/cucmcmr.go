@@ -18,6 +18,7 @@ package models
 
 type CucmCmr struct {
 	ID                                  string
+	IngestedFileId                      *string `gorm:"index"`
 	Originpkid                          *string `gorm:"unique;not null"`
 	FileClusterId                       *string
 	FileNodeId                          *string
@@ -25,7 +26,7 @@ type CucmCmr struct {
 	FileSequenceNumber                  *int64
 	Cdrrecordtype                       *int64
 	Globalcallid_Callmanagerid          *int64
-	Globalcallid_Callid                 *int64
+	Globalcallid_Callid                 *int64 `gorm:"index"`
 	Nodeid                              *int64
 	Directorynum                        *string
 	Callidentifier                      *int64
@@ -69,6 +70,11 @@ type CucmCmr struct {
 	Remotesessionid                     *string
 	Headsetsn                           *string
 	Headsetmetrics                      *string
+	Headsetmodel                        *string
+	Headsetfirmware                     *string
+	Headsetconnection                   *string
+	Headsetbattery                      *int64
+	Headsetsignal                       *int64
 	VQCCR                               *float64
 	VQICR                               *float64
 	Vqicrmx                             *float64
@@ -87,4 +93,7 @@ type CucmCmr struct {
 	Vqmlqkmn                            *float64
 	Vqmlqkmx                            *float64
 	Vqmlqkvr                            *float64
+	// Varvqmetrics holds every key of the VarVQ metrics as a JSON object,
+	// including the ones that have a column of their own.
+	Varvqmetrics *string
 }
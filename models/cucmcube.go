@@ -0,0 +1,71 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import "strings"
+
+// CucmCubeLink links a CUCM CDR to the CUBE call of the same call, for calls
+// that go from CUCM over a SIP trunk through a CUBE, or the other way round.
+// CubeCallId is the ID of the CubeCall. OffsetSeconds is the disconnect time
+// of the CUBE call minus the one of the CDR.
+type CucmCubeLink struct {
+	ID            string
+	CucmCdrId     string `gorm:"index"`
+	CubeCallId    string `gorm:"index"`
+	Direction     string
+	Method        string
+	OffsetSeconds int64
+	UpdatedAt     int64
+}
+
+// Directions of a CucmCubeLink, as seen from CUCM.
+const (
+	CucmCubeOutbound = "outbound"
+	CucmCubeInbound  = "inbound"
+)
+
+// Methods a CucmCubeLink was found with. CucmCubeCallRef is the protocol
+// call reference of CUCM matching the H323ConfId of the CUBE call, and
+// CucmCubeTimeNumber the fallback on disconnect time and numbers.
+const (
+	CucmCubeCallRef    = "call_ref"
+	CucmCubeTimeNumber = "time_number"
+)
+
+// NormalizeCallRef returns a protocol call reference or H323ConfId in one
+// form, so the two can be compared. CUCM writes the Cisco-GUID of a SIP call
+// as 32 hexadecimal digits, and CUBE in four groups of eight, e.g.
+// 8D5E9A21 1D3511EA 8001A1B2 C3D4E5F6.
+func NormalizeCallRef(ref string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(ref)))
+}
+
+// CubeConfIDs returns the forms an H323ConfId with the given call reference
+// can be stored in.
+func CubeConfIDs(ref string) []string {
+	normalized := NormalizeCallRef(ref)
+	if normalized == "" {
+		return nil
+	}
+	ids := []string{normalized}
+	if len(normalized) == 32 {
+		ids = append(ids, normalized[0:8]+" "+normalized[8:16]+" "+normalized[16:24]+" "+normalized[24:32])
+	}
+	if ref != normalized {
+		ids = append(ids, ref)
+	}
+	return ids
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// ExpresswayCall is a call from the call history of a Cisco Expressway or
+// VCS. The Tag is the same on every Expressway the call passed through, so
+// the Expressway-C and Expressway-E records of a call share it. Times are
+// Unix seconds and bandwidth is in kbps.
+type ExpresswayCall struct {
+	ID             string
+	IngestedFileId *string `gorm:"index"`
+	Filename       *string
+	// System is the name of the poller the call was read from.
+	System             *string
+	SerialNumber       *string `gorm:"index"`
+	Tag                *string `gorm:"index"`
+	State              *string
+	StartTime          *int64
+	EndTime            *int64
+	Duration           *int64
+	SourceAlias        *string
+	DestinationAlias   *string
+	SourceAddress      *string
+	DestinationAddress *string
+	Protocol           *string
+	CallType           *string
+	SourceZone         *string
+	DestinationZone    *string
+	RequestedBandwidth *int64
+	AllocatedBandwidth *int64
+	MediaRouted        *string
+	Encryption         *string
+	DisconnectReason   *string
+}
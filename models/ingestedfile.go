@@ -0,0 +1,41 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+const (
+	IngestionProcessing = "processing"
+	IngestionComplete   = "complete"
+	IngestionFailed     = "failed"
+)
+
+// IngestedFile is an entry of the ingested_files ledger. The ID is the
+// SHA-256 of the file content, so a file that is delivered twice or renamed
+// is still recognised.
+type IngestedFile struct {
+	ID         string
+	Size       int64
+	Path       string
+	Type       string
+	Records    int64
+	Rejected   int64
+	Status     string
+	Error      *string
+	StartedAt  int64
+	FinishedAt *int64
+	// UpdatedAt is the version of the entry in nanoseconds. ClickHouse keeps
+	// the newest version of every entry.
+	UpdatedAt int64 `gorm:"autoUpdateTime:nano"`
+}
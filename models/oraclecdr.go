@@ -0,0 +1,125 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// OracleCDR is an accounting record of an Oracle (Acme Packet) SBC. Every
+// session has a Start record, optional Interim-Update records and a Stop
+// record, told apart by Accountingstatus and linked by Accountingsessionid.
+type OracleCDR struct {
+	ID                            string
+	IngestedFileId                *string `gorm:"index"`
+	Filename                      *string
+	Accountingstatus              *string
+	Nasipaddress                  *string
+	Nasport                       *int64
+	Accountingsessionid           *string `gorm:"index"`
+	Ingresssessionid              *string
+	Egresssessionid               *string
+	Sessionprotocoltype           *string
+	Callingstationid              *string
+	Calledstationid               *string
+	Accountingterminationcause    *string
+	Accountingsessiontime         *int64
+	Ciscosetuptime                *int64
+	Ciscoconnecttime              *int64
+	Ciscodisconnecttime           *int64
+	Ciscodisconnectcause          *int64
+	Egressnetworkinterfaceid      *string
+	Egressvlantagvalue            *int64
+	Ingressnetworkinterfaceid     *string
+	Ingressvlantagvalue           *int64
+	Egressrealm                   *string
+	Ingressrealm                  *string
+	Flowidentifier                *string
+	Flowtype                      *string
+	Flowinputrealm                *string
+	Flowinputsrcaddr              *string
+	Flowinputsrcport              *int64
+	Flowinputdestaddress          *string
+	Flowinputdestport             *int64
+	Flowoutputrealm               *string
+	Flowoutputsrcaddress          *string
+	Flowoutputsrcport             *int64
+	Flowoutputdestaddr            *string
+	Flowoutputdestport            *int64
+	Rtcpcallingpacketslost        *int64
+	Rtcpcallingavgjitter          *int64
+	Rtcpcallingavglatency         *int64
+	Rtcpcallingmaxjitter          *int64
+	Rtcpcallingmaxlatency         *int64
+	Rtpcallingpacketslost         *int64
+	Rtpcallingavgjitter           *int64
+	Rtpcallingmaxjitter           *int64
+	Rtpcallingoctets              *int64
+	Rtpcallingpackets             *int64
+	Callingrfactor                *float64
+	Callingmos                    *float64
+	Flowidentifier2               *string
+	Flowtype2                     *string
+	Flowinputrealm2               *string
+	Flowinputsrcaddr2             *string
+	Flowinputsrcport2             *int64
+	Flowinputdestaddress2         *string
+	Flowinputdestport2            *int64
+	Flowoutputrealm2              *string
+	Flowoutputsrcaddress2         *string
+	Flowoutputsrcport2            *int64
+	Flowoutputdestaddr2           *string
+	Flowoutputdestport2           *int64
+	Rtcpcalledpacketslost         *int64
+	Rtcpcalledavgjitter           *int64
+	Rtcpcalledavglatency          *int64
+	Rtcpcalledmaxjitter           *int64
+	Rtcpcalledmaxlatency          *int64
+	Rtpcalledpacketslost          *int64
+	Rtpcalledavgjitter            *int64
+	Rtpcalledmaxjitter            *int64
+	Rtpcalledoctets               *int64
+	Rtpcalledpackets              *int64
+	Calledrfactor                 *float64
+	Calledmos                     *float64
+	Firmwareversion               *string
+	Localtimezone                 *string
+	Postdialdelay                 *int64
+	Primaryroutingnumber          *string
+	Ingresslocaladdress           *string
+	Ingressremoteaddress          *string
+	Egresslocaladdress            *string
+	Egressremoteaddress           *string
+	Sessiondisposition            *int64
+	Disconnectinitiator           *int64
+	Disconnectcause               *int64
+	Sipstatuscode                 *int64
+	Egressroutingnumber           *string
+	Callingmediastoptime          *int64
+	Calledmediastoptime           *int64
+	Flowmediatype                 *string
+	Flowmediatype2                *string
+	Rtpcallingoctetstransmitted   *int64
+	Rtpcallingpacketstransmitted  *int64
+	Rtpcalledoctetstransmitted    *int64
+	Rtpcalledpacketstransmitted   *int64
+	Msrpcalledoctets              *int64
+	Msrpcalledpackets             *int64
+	Msrpcalledoctetstransmitted   *int64
+	Msrpcalledpacketstransmitted  *int64
+	Msrpcallingoctets             *int64
+	Msrpcallingpackets            *int64
+	Msrpcallingoctetstransmitted  *int64
+	Msrpcallingpacketstransmitted *int64
+	Nodefunctionality             *string
+	Cdrsequencenumber             *int64
+}
@@ -17,6 +17,7 @@
 package models
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
@@ -305,13 +306,17 @@ func (raw *RawCucmCdr) Parse(filename string) (*CucmCdr, error) {
 	if err != nil {
 		logger.Error("Error parsing Globalcallid_Callmanagerid: %s in %s", err, filename)
 	}
+	// The call and leg identifiers link the record to its call, so unlike
+	// the other fields they reject the record when they cannot be converted
 	ParsedGlobalcallid_Callid, err = helpers.ConvertStringToInt64(raw.Globalcallid_Callid)
 	if err != nil {
 		logger.Error("Error parsing Globalcallid_Callid: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Globalcallid_Callid: %s", err)
 	}
 	ParsedOriglegcallidentifier, err = helpers.ConvertStringToInt64(raw.Origlegcallidentifier)
 	if err != nil {
 		logger.Error("Error parsing Origlegcallidentifier: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Origlegcallidentifier: %s", err)
 	}
 	ParsedDatetimeorigination, err = helpers.ConvertStringToInt64(raw.Datetimeorigination)
 	if err != nil {
@@ -380,6 +385,7 @@ func (raw *RawCucmCdr) Parse(filename string) (*CucmCdr, error) {
 	ParsedDestlegcallidentifier, err = helpers.ConvertStringToInt64(raw.Destlegcallidentifier)
 	if err != nil {
 		logger.Error("Error parsing Destlegcallidentifier: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Destlegcallidentifier: %s", err)
 	}
 	ParsedDestnodeid, err = helpers.ConvertStringToInt64(raw.Destnodeid)
 	if err != nil {
@@ -17,6 +17,7 @@
 package models
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
@@ -136,6 +137,8 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 	var ParsedRemotesessionid *string
 	var ParsedHeadsetsn *string
 	var ParsedHeadsetmetrics *string
+	var ParsedHeadset headsetMetrics
+	var ParsedVarvqmetrics *string
 	var ParsedVqvorxcodec *string
 
 	var ParsedVQCCR *float64
@@ -156,9 +159,12 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 	if err != nil {
 		logger.Error("Error parsing Globalcallid_Callmanagerid: %s in %s", err, filename)
 	}
+	// The call and leg identifiers link the record to its call, so unlike
+	// the other fields they reject the record when they cannot be converted
 	ParsedGlobalcallid_Callid, err = helpers.ConvertStringToInt64(raw.Globalcallid_Callid)
 	if err != nil {
 		logger.Error("Error parsing Globalcallid_Callid: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Globalcallid_Callid: %s", err)
 	}
 	ParsedNodeid, err = helpers.ConvertStringToInt64(raw.Nodeid)
 	if err != nil {
@@ -167,6 +173,7 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 	ParsedCallidentifier, err = helpers.ConvertStringToInt64(raw.Callidentifier)
 	if err != nil {
 		logger.Error("Error parsing Callidentifier: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Callidentifier: %s", err)
 	}
 	ParsedDatetimestamp, err = helpers.ConvertStringToInt64(raw.Datetimestamp)
 	if err != nil {
@@ -294,10 +301,19 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 	ParsedRemotesessionid = helpers.RemoveSpaceFromString(raw.Remotesessionid)
 	ParsedHeadsetsn = helpers.RemoveSpaceFromString(raw.Headsetsn)
 	ParsedHeadsetmetrics = helpers.RemoveSpaceFromString(raw.Headsetmetrics)
+	ParsedHeadset, err = parseHeadsetMetrics(raw.Headsetmetrics)
+	if err != nil {
+		logger.Error("Error parsing Headsetmetrics: %s in %s", err, filename)
+	}
 
 	if raw.Varvqmetrics != nil {
 		VarVQMap := helpers.ConvertStringToKeyValuePairs(raw.Varvqmetrics, ";", "=")
 
+		ParsedVarvqmetrics, err = varVQJSON(VarVQMap)
+		if err != nil {
+			logger.Error("Error parsing Varvqmetrics: %s in %s", err, filename)
+		}
+
 		VQMLQK, ok := VarVQMap["MLQK"]
 		if ok {
 			ParsedVQMLQK, err = helpers.ConvertStringToFloat64(&VQMLQK)
@@ -476,6 +492,11 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 		Remotesessionid:                     ParsedRemotesessionid,
 		Headsetsn:                           ParsedHeadsetsn,
 		Headsetmetrics:                      ParsedHeadsetmetrics,
+		Headsetmodel:                        ParsedHeadset.Model,
+		Headsetfirmware:                     ParsedHeadset.Firmware,
+		Headsetconnection:                   ParsedHeadset.Connection,
+		Headsetbattery:                      ParsedHeadset.Battery,
+		Headsetsignal:                       ParsedHeadset.Signal,
 		VQCCR:                               ParsedVQCCR,
 		VQICR:                               ParsedVQICR,
 		Vqicrmx:                             ParsedVqicrmx,
@@ -494,5 +515,6 @@ func (raw *RawCucmCmr) Parse(filename string) (*CucmCmr, error) {
 		Vqmlqkmn:                            ParsedVqmlqkmn,
 		Vqmlqkmx:                            ParsedVqmlqkmx,
 		Vqmlqkvr:                            ParsedVqmlqkvr,
+		Varvqmetrics:                        ParsedVarvqmetrics,
 	}, nil
 }
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/helpers"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/google/uuid"
+)
+
+// expresswayTimeLayouts are the time formats of the call history. Times
+// without a zone are UTC.
+var expresswayTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02 15:04:05.000", "2006/01/02 15:04:05"}
+
+// RawExpresswayCall is a call of an Expressway call history export, from a
+// CSV row or a JSON object.
+type RawExpresswayCall struct {
+	Filename           *string
+	SerialNumber       *string
+	Tag                *string
+	State              *string
+	StartTime          *string
+	EndTime            *string
+	Duration           *string
+	SourceAlias        *string
+	DestinationAlias   *string
+	SourceAddress      *string
+	DestinationAddress *string
+	Protocol           *string
+	CallType           *string
+	SourceZone         *string
+	DestinationZone    *string
+	RequestedBandwidth *string
+	AllocatedBandwidth *string
+	MediaRouted        *string
+	Encryption         *string
+	DisconnectReason   *string
+}
+
+// expresswayTime converts a call history time to Unix seconds.
+func expresswayTime(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	for _, layout := range expresswayTimeLayouts {
+		if t, err := time.Parse(layout, *trimmed); err == nil {
+			unix := t.UTC().Unix()
+			return &unix, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", helpers.ErrInvalidTimeFormat, *trimmed)
+}
+
+// expresswayDuration converts a duration in seconds or as h:mm:ss.
+func expresswayDuration(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil || !strings.Contains(*trimmed, ":") {
+		return helpers.ConvertStringToInt64(trimmed)
+	}
+	var seconds int64
+	for _, part := range strings.Split(*trimmed, ":") {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %s", *trimmed)
+		}
+		seconds = seconds*60 + value
+	}
+	return &seconds, nil
+}
+
+// Parse converts the call. Only an invalid Start time rejects it, other
+// invalid values are logged and stored as NULL.
+func (raw *RawExpresswayCall) Parse(filename string) (*ExpresswayCall, error) {
+	var err error
+
+	var ParsedStartTime *int64
+	var ParsedEndTime *int64
+	var ParsedDuration *int64
+	var ParsedRequestedBandwidth *int64
+	var ParsedAllocatedBandwidth *int64
+
+	ParsedStartTime, err = expresswayTime(raw.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Start time: %w", err)
+	}
+	ParsedEndTime, err = expresswayTime(raw.EndTime)
+	if err != nil {
+		logger.Error("Error parsing EndTime: %s in %s", err, filename)
+	}
+	ParsedDuration, err = expresswayDuration(raw.Duration)
+	if err != nil {
+		logger.Error("Error parsing Duration: %s in %s", err, filename)
+	}
+	ParsedRequestedBandwidth, err = helpers.ConvertStringToInt64(trimmedString(raw.RequestedBandwidth))
+	if err != nil {
+		logger.Error("Error parsing RequestedBandwidth: %s in %s", err, filename)
+	}
+	ParsedAllocatedBandwidth, err = helpers.ConvertStringToInt64(trimmedString(raw.AllocatedBandwidth))
+	if err != nil {
+		logger.Error("Error parsing AllocatedBandwidth: %s in %s", err, filename)
+	}
+
+	return &ExpresswayCall{
+		ID:                 uuid.New().String(),
+		Filename:           helpers.RemoveSpaceFromString(raw.Filename),
+		SerialNumber:       trimmedString(raw.SerialNumber),
+		Tag:                trimmedString(raw.Tag),
+		State:              trimmedString(raw.State),
+		StartTime:          ParsedStartTime,
+		EndTime:            ParsedEndTime,
+		Duration:           ParsedDuration,
+		SourceAlias:        trimmedString(raw.SourceAlias),
+		DestinationAlias:   trimmedString(raw.DestinationAlias),
+		SourceAddress:      trimmedString(raw.SourceAddress),
+		DestinationAddress: trimmedString(raw.DestinationAddress),
+		Protocol:           trimmedString(raw.Protocol),
+		CallType:           trimmedString(raw.CallType),
+		SourceZone:         trimmedString(raw.SourceZone),
+		DestinationZone:    trimmedString(raw.DestinationZone),
+		RequestedBandwidth: ParsedRequestedBandwidth,
+		AllocatedBandwidth: ParsedAllocatedBandwidth,
+		MediaRouted:        trimmedString(raw.MediaRouted),
+		Encryption:         trimmedString(raw.Encryption),
+		DisconnectReason:   trimmedString(raw.DisconnectReason),
+	}, nil
+}
@@ -0,0 +1,781 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/helpers"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/google/uuid"
+)
+
+// Oracle accounting record types, as Acct-Status-Type names.
+const (
+	OracleAccountingStart   = "Start"
+	OracleAccountingStop    = "Stop"
+	OracleAccountingInterim = "Interim-Update"
+	OracleAccountingOn      = "Accounting-On"
+	OracleAccountingOff     = "Accounting-Off"
+)
+
+// RawOracleCDR is a Stop record of an Oracle (Acme Packet) SBC CDR file.
+type RawOracleCDR struct {
+	Filename                      *string
+	Accountingstatus              *string
+	Nasipaddress                  *string
+	Nasport                       *string
+	Accountingsessionid           *string
+	Ingresssessionid              *string
+	Egresssessionid               *string
+	Sessionprotocoltype           *string
+	Callingstationid              *string
+	Calledstationid               *string
+	Accountingterminationcause    *string
+	Accountingsessiontime         *string
+	Ciscosetuptime                *string
+	Ciscoconnecttime              *string
+	Ciscodisconnecttime           *string
+	Ciscodisconnectcause          *string
+	Egressnetworkinterfaceid      *string
+	Egressvlantagvalue            *string
+	Ingressnetworkinterfaceid     *string
+	Ingressvlantagvalue           *string
+	Egressrealm                   *string
+	Ingressrealm                  *string
+	Flowidentifier                *string
+	Flowtype                      *string
+	Flowinputrealm                *string
+	Flowinputsrcaddr              *string
+	Flowinputsrcport              *string
+	Flowinputdestaddress          *string
+	Flowinputdestport             *string
+	Flowoutputrealm               *string
+	Flowoutputsrcaddress          *string
+	Flowoutputsrcport             *string
+	Flowoutputdestaddr            *string
+	Flowoutputdestport            *string
+	Rtcpcallingpacketslost        *string
+	Rtcpcallingavgjitter          *string
+	Rtcpcallingavglatency         *string
+	Rtcpcallingmaxjitter          *string
+	Rtcpcallingmaxlatency         *string
+	Rtpcallingpacketslost         *string
+	Rtpcallingavgjitter           *string
+	Rtpcallingmaxjitter           *string
+	Rtpcallingoctets              *string
+	Rtpcallingpackets             *string
+	Callingrfactor                *string
+	Callingmos                    *string
+	Flowidentifier2               *string
+	Flowtype2                     *string
+	Flowinputrealm2               *string
+	Flowinputsrcaddr2             *string
+	Flowinputsrcport2             *string
+	Flowinputdestaddress2         *string
+	Flowinputdestport2            *string
+	Flowoutputrealm2              *string
+	Flowoutputsrcaddress2         *string
+	Flowoutputsrcport2            *string
+	Flowoutputdestaddr2           *string
+	Flowoutputdestport2           *string
+	Rtcpcalledpacketslost         *string
+	Rtcpcalledavgjitter           *string
+	Rtcpcalledavglatency          *string
+	Rtcpcalledmaxjitter           *string
+	Rtcpcalledmaxlatency          *string
+	Rtpcalledpacketslost          *string
+	Rtpcalledavgjitter            *string
+	Rtpcalledmaxjitter            *string
+	Rtpcalledoctets               *string
+	Rtpcalledpackets              *string
+	Calledrfactor                 *string
+	Calledmos                     *string
+	Firmwareversion               *string
+	Localtimezone                 *string
+	Postdialdelay                 *string
+	Primaryroutingnumber          *string
+	Ingresslocaladdress           *string
+	Ingressremoteaddress          *string
+	Egresslocaladdress            *string
+	Egressremoteaddress           *string
+	Sessiondisposition            *string
+	Disconnectinitiator           *string
+	Disconnectcause               *string
+	Sipstatuscode                 *string
+	Egressroutingnumber           *string
+	Callingmediastoptime          *string
+	Calledmediastoptime           *string
+	Flowmediatype                 *string
+	Flowmediatype2                *string
+	Rtpcallingoctetstransmitted   *string
+	Rtpcallingpacketstransmitted  *string
+	Rtpcalledoctetstransmitted    *string
+	Rtpcalledpacketstransmitted   *string
+	Msrpcalledoctets              *string
+	Msrpcalledpackets             *string
+	Msrpcalledoctetstransmitted   *string
+	Msrpcalledpacketstransmitted  *string
+	Msrpcallingoctets             *string
+	Msrpcallingpackets            *string
+	Msrpcallingoctetstransmitted  *string
+	Msrpcallingpacketstransmitted *string
+	Nodefunctionality             *string
+	Cdrsequencenumber             *string
+}
+
+// OracleAccountingStatus returns the name of an Acct-Status-Type, which the
+// SBC writes either as a number or as a name.
+func OracleAccountingStatus(status *string) *string {
+	trimmed := trimmedString(status)
+	if trimmed == nil {
+		return nil
+	}
+	var name string
+	switch *trimmed {
+	case "1", OracleAccountingStart:
+		name = OracleAccountingStart
+	case "2", OracleAccountingStop:
+		name = OracleAccountingStop
+	case "3", "Interim", OracleAccountingInterim:
+		name = OracleAccountingInterim
+	case "7", OracleAccountingOn:
+		name = OracleAccountingOn
+	case "8", OracleAccountingOff:
+		name = OracleAccountingOff
+	default:
+		name = *trimmed
+	}
+	return &name
+}
+
+// trimmedString returns s without surrounding spaces, or nil if it is empty.
+func trimmedString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// oracleInt64 converts a decimal value. Unlike helpers.ConvertStringToInt64
+// it does not drop other characters, so a malformed value is an error.
+func oracleInt64(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseInt(*trimmed, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// oracleHexInt64 converts a hexadecimal value such as the
+// h323-disconnect-cause.
+func oracleHexInt64(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseInt(*trimmed, 16, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// scaledFloat converts a value the SBC reports multiplied by 100, such as the
+// R-Factor and MOS.
+func scaledFloat(s *string) (*float64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(*trimmed, 64)
+	if err != nil {
+		return nil, err
+	}
+	scaled := value / 100
+	return &scaled, nil
+}
+
+func (raw *RawOracleCDR) Parse(filename string) (*OracleCDR, error) {
+	var err error
+
+	var ParsedAccountingstatus *string
+	var ParsedNasipaddress *string
+	var ParsedNasport *int64
+	var ParsedAccountingsessionid *string
+	var ParsedIngresssessionid *string
+	var ParsedEgresssessionid *string
+	var ParsedSessionprotocoltype *string
+	var ParsedCallingstationid *string
+	var ParsedCalledstationid *string
+	var ParsedAccountingterminationcause *string
+	var ParsedAccountingsessiontime *int64
+	var ParsedCiscosetuptime *int64
+	var ParsedCiscoconnecttime *int64
+	var ParsedCiscodisconnecttime *int64
+	var ParsedCiscodisconnectcause *int64
+	var ParsedEgressnetworkinterfaceid *string
+	var ParsedEgressvlantagvalue *int64
+	var ParsedIngressnetworkinterfaceid *string
+	var ParsedIngressvlantagvalue *int64
+	var ParsedEgressrealm *string
+	var ParsedIngressrealm *string
+	var ParsedFlowidentifier *string
+	var ParsedFlowtype *string
+	var ParsedFlowinputrealm *string
+	var ParsedFlowinputsrcaddr *string
+	var ParsedFlowinputsrcport *int64
+	var ParsedFlowinputdestaddress *string
+	var ParsedFlowinputdestport *int64
+	var ParsedFlowoutputrealm *string
+	var ParsedFlowoutputsrcaddress *string
+	var ParsedFlowoutputsrcport *int64
+	var ParsedFlowoutputdestaddr *string
+	var ParsedFlowoutputdestport *int64
+	var ParsedRtcpcallingpacketslost *int64
+	var ParsedRtcpcallingavgjitter *int64
+	var ParsedRtcpcallingavglatency *int64
+	var ParsedRtcpcallingmaxjitter *int64
+	var ParsedRtcpcallingmaxlatency *int64
+	var ParsedRtpcallingpacketslost *int64
+	var ParsedRtpcallingavgjitter *int64
+	var ParsedRtpcallingmaxjitter *int64
+	var ParsedRtpcallingoctets *int64
+	var ParsedRtpcallingpackets *int64
+	var ParsedCallingrfactor *float64
+	var ParsedCallingmos *float64
+	var ParsedFlowidentifier2 *string
+	var ParsedFlowtype2 *string
+	var ParsedFlowinputrealm2 *string
+	var ParsedFlowinputsrcaddr2 *string
+	var ParsedFlowinputsrcport2 *int64
+	var ParsedFlowinputdestaddress2 *string
+	var ParsedFlowinputdestport2 *int64
+	var ParsedFlowoutputrealm2 *string
+	var ParsedFlowoutputsrcaddress2 *string
+	var ParsedFlowoutputsrcport2 *int64
+	var ParsedFlowoutputdestaddr2 *string
+	var ParsedFlowoutputdestport2 *int64
+	var ParsedRtcpcalledpacketslost *int64
+	var ParsedRtcpcalledavgjitter *int64
+	var ParsedRtcpcalledavglatency *int64
+	var ParsedRtcpcalledmaxjitter *int64
+	var ParsedRtcpcalledmaxlatency *int64
+	var ParsedRtpcalledpacketslost *int64
+	var ParsedRtpcalledavgjitter *int64
+	var ParsedRtpcalledmaxjitter *int64
+	var ParsedRtpcalledoctets *int64
+	var ParsedRtpcalledpackets *int64
+	var ParsedCalledrfactor *float64
+	var ParsedCalledmos *float64
+	var ParsedFirmwareversion *string
+	var ParsedLocaltimezone *string
+	var ParsedPostdialdelay *int64
+	var ParsedPrimaryroutingnumber *string
+	var ParsedIngresslocaladdress *string
+	var ParsedIngressremoteaddress *string
+	var ParsedEgresslocaladdress *string
+	var ParsedEgressremoteaddress *string
+	var ParsedSessiondisposition *int64
+	var ParsedDisconnectinitiator *int64
+	var ParsedDisconnectcause *int64
+	var ParsedSipstatuscode *int64
+	var ParsedEgressroutingnumber *string
+	var ParsedCallingmediastoptime *int64
+	var ParsedCalledmediastoptime *int64
+	var ParsedFlowmediatype *string
+	var ParsedFlowmediatype2 *string
+	var ParsedRtpcallingoctetstransmitted *int64
+	var ParsedRtpcallingpacketstransmitted *int64
+	var ParsedRtpcalledoctetstransmitted *int64
+	var ParsedRtpcalledpacketstransmitted *int64
+	var ParsedMsrpcalledoctets *int64
+	var ParsedMsrpcalledpackets *int64
+	var ParsedMsrpcalledoctetstransmitted *int64
+	var ParsedMsrpcalledpacketstransmitted *int64
+	var ParsedMsrpcallingoctets *int64
+	var ParsedMsrpcallingpackets *int64
+	var ParsedMsrpcallingoctetstransmitted *int64
+	var ParsedMsrpcallingpacketstransmitted *int64
+	var ParsedNodefunctionality *string
+	var ParsedCdrsequencenumber *int64
+
+	ParsedAccountingstatus = OracleAccountingStatus(raw.Accountingstatus)
+	ParsedNasipaddress = helpers.RemoveSpaceFromString(raw.Nasipaddress)
+	ParsedNasport, err = oracleInt64(raw.Nasport)
+	if err != nil {
+		logger.Error("Error parsing Nasport: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Nasport: %s", err)
+	}
+	ParsedAccountingsessionid = helpers.RemoveSpaceFromString(raw.Accountingsessionid)
+	ParsedIngresssessionid = helpers.RemoveSpaceFromString(raw.Ingresssessionid)
+	ParsedEgresssessionid = helpers.RemoveSpaceFromString(raw.Egresssessionid)
+	ParsedSessionprotocoltype = helpers.RemoveSpaceFromString(raw.Sessionprotocoltype)
+	ParsedCallingstationid = helpers.RemoveSpaceFromString(raw.Callingstationid)
+	ParsedCalledstationid = helpers.RemoveSpaceFromString(raw.Calledstationid)
+	ParsedAccountingterminationcause = trimmedString(raw.Accountingterminationcause)
+	ParsedAccountingsessiontime, err = oracleInt64(raw.Accountingsessiontime)
+	if err != nil {
+		logger.Error("Error parsing Accountingsessiontime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Accountingsessiontime: %s", err)
+	}
+	ParsedCiscosetuptime, err = helpers.ConvertStringToUnixTime(trimmedString(raw.Ciscosetuptime), nil)
+	if err == helpers.ErrInvalidNTPReferenceAsterisk || err == helpers.ErrInvalidNTPReferencePeriod {
+		logger.Error("Error parsing Ciscosetuptime: %s in %s", err, filename)
+	} else if err != nil {
+		logger.Error("Error parsing Ciscosetuptime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Ciscosetuptime: %s", err)
+	}
+	ParsedCiscoconnecttime, err = helpers.ConvertStringToUnixTime(trimmedString(raw.Ciscoconnecttime), nil)
+	if err == helpers.ErrInvalidNTPReferenceAsterisk || err == helpers.ErrInvalidNTPReferencePeriod {
+		logger.Error("Error parsing Ciscoconnecttime: %s in %s", err, filename)
+	} else if err != nil {
+		logger.Error("Error parsing Ciscoconnecttime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Ciscoconnecttime: %s", err)
+	}
+	ParsedCiscodisconnecttime, err = helpers.ConvertStringToUnixTime(trimmedString(raw.Ciscodisconnecttime), nil)
+	if err == helpers.ErrInvalidNTPReferenceAsterisk || err == helpers.ErrInvalidNTPReferencePeriod {
+		logger.Error("Error parsing Ciscodisconnecttime: %s in %s", err, filename)
+	} else if err != nil {
+		logger.Error("Error parsing Ciscodisconnecttime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Ciscodisconnecttime: %s", err)
+	}
+	ParsedCiscodisconnectcause, err = oracleHexInt64(raw.Ciscodisconnectcause)
+	if err != nil {
+		logger.Error("Error parsing Ciscodisconnectcause: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Ciscodisconnectcause: %s", err)
+	}
+	ParsedEgressnetworkinterfaceid = helpers.RemoveSpaceFromString(raw.Egressnetworkinterfaceid)
+	ParsedEgressvlantagvalue, err = oracleInt64(raw.Egressvlantagvalue)
+	if err != nil {
+		logger.Error("Error parsing Egressvlantagvalue: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Egressvlantagvalue: %s", err)
+	}
+	ParsedIngressnetworkinterfaceid = helpers.RemoveSpaceFromString(raw.Ingressnetworkinterfaceid)
+	ParsedIngressvlantagvalue, err = oracleInt64(raw.Ingressvlantagvalue)
+	if err != nil {
+		logger.Error("Error parsing Ingressvlantagvalue: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Ingressvlantagvalue: %s", err)
+	}
+	ParsedEgressrealm = helpers.RemoveSpaceFromString(raw.Egressrealm)
+	ParsedIngressrealm = helpers.RemoveSpaceFromString(raw.Ingressrealm)
+	ParsedFlowidentifier = helpers.RemoveSpaceFromString(raw.Flowidentifier)
+	ParsedFlowtype = helpers.RemoveSpaceFromString(raw.Flowtype)
+	ParsedFlowinputrealm = helpers.RemoveSpaceFromString(raw.Flowinputrealm)
+	ParsedFlowinputsrcaddr = helpers.RemoveSpaceFromString(raw.Flowinputsrcaddr)
+	ParsedFlowinputsrcport, err = oracleInt64(raw.Flowinputsrcport)
+	if err != nil {
+		logger.Error("Error parsing Flowinputsrcport: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowinputsrcport: %s", err)
+	}
+	ParsedFlowinputdestaddress = helpers.RemoveSpaceFromString(raw.Flowinputdestaddress)
+	ParsedFlowinputdestport, err = oracleInt64(raw.Flowinputdestport)
+	if err != nil {
+		logger.Error("Error parsing Flowinputdestport: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowinputdestport: %s", err)
+	}
+	ParsedFlowoutputrealm = helpers.RemoveSpaceFromString(raw.Flowoutputrealm)
+	ParsedFlowoutputsrcaddress = helpers.RemoveSpaceFromString(raw.Flowoutputsrcaddress)
+	ParsedFlowoutputsrcport, err = oracleInt64(raw.Flowoutputsrcport)
+	if err != nil {
+		logger.Error("Error parsing Flowoutputsrcport: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowoutputsrcport: %s", err)
+	}
+	ParsedFlowoutputdestaddr = helpers.RemoveSpaceFromString(raw.Flowoutputdestaddr)
+	ParsedFlowoutputdestport, err = oracleInt64(raw.Flowoutputdestport)
+	if err != nil {
+		logger.Error("Error parsing Flowoutputdestport: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowoutputdestport: %s", err)
+	}
+	ParsedRtcpcallingpacketslost, err = oracleInt64(raw.Rtcpcallingpacketslost)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcallingpacketslost: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcallingpacketslost: %s", err)
+	}
+	ParsedRtcpcallingavgjitter, err = oracleInt64(raw.Rtcpcallingavgjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcallingavgjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcallingavgjitter: %s", err)
+	}
+	ParsedRtcpcallingavglatency, err = oracleInt64(raw.Rtcpcallingavglatency)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcallingavglatency: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcallingavglatency: %s", err)
+	}
+	ParsedRtcpcallingmaxjitter, err = oracleInt64(raw.Rtcpcallingmaxjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcallingmaxjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcallingmaxjitter: %s", err)
+	}
+	ParsedRtcpcallingmaxlatency, err = oracleInt64(raw.Rtcpcallingmaxlatency)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcallingmaxlatency: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcallingmaxlatency: %s", err)
+	}
+	ParsedRtpcallingpacketslost, err = oracleInt64(raw.Rtpcallingpacketslost)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingpacketslost: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingpacketslost: %s", err)
+	}
+	ParsedRtpcallingavgjitter, err = oracleInt64(raw.Rtpcallingavgjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingavgjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingavgjitter: %s", err)
+	}
+	ParsedRtpcallingmaxjitter, err = oracleInt64(raw.Rtpcallingmaxjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingmaxjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingmaxjitter: %s", err)
+	}
+	ParsedRtpcallingoctets, err = oracleInt64(raw.Rtpcallingoctets)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingoctets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingoctets: %s", err)
+	}
+	ParsedRtpcallingpackets, err = oracleInt64(raw.Rtpcallingpackets)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingpackets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingpackets: %s", err)
+	}
+	ParsedCallingrfactor, err = scaledFloat(raw.Callingrfactor)
+	if err != nil {
+		logger.Error("Error parsing Callingrfactor: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Callingrfactor: %s", err)
+	}
+	ParsedCallingmos, err = scaledFloat(raw.Callingmos)
+	if err != nil {
+		logger.Error("Error parsing Callingmos: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Callingmos: %s", err)
+	}
+	ParsedFlowidentifier2 = helpers.RemoveSpaceFromString(raw.Flowidentifier2)
+	ParsedFlowtype2 = helpers.RemoveSpaceFromString(raw.Flowtype2)
+	ParsedFlowinputrealm2 = helpers.RemoveSpaceFromString(raw.Flowinputrealm2)
+	ParsedFlowinputsrcaddr2 = helpers.RemoveSpaceFromString(raw.Flowinputsrcaddr2)
+	ParsedFlowinputsrcport2, err = oracleInt64(raw.Flowinputsrcport2)
+	if err != nil {
+		logger.Error("Error parsing Flowinputsrcport2: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowinputsrcport2: %s", err)
+	}
+	ParsedFlowinputdestaddress2 = helpers.RemoveSpaceFromString(raw.Flowinputdestaddress2)
+	ParsedFlowinputdestport2, err = oracleInt64(raw.Flowinputdestport2)
+	if err != nil {
+		logger.Error("Error parsing Flowinputdestport2: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowinputdestport2: %s", err)
+	}
+	ParsedFlowoutputrealm2 = helpers.RemoveSpaceFromString(raw.Flowoutputrealm2)
+	ParsedFlowoutputsrcaddress2 = helpers.RemoveSpaceFromString(raw.Flowoutputsrcaddress2)
+	ParsedFlowoutputsrcport2, err = oracleInt64(raw.Flowoutputsrcport2)
+	if err != nil {
+		logger.Error("Error parsing Flowoutputsrcport2: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowoutputsrcport2: %s", err)
+	}
+	ParsedFlowoutputdestaddr2 = helpers.RemoveSpaceFromString(raw.Flowoutputdestaddr2)
+	ParsedFlowoutputdestport2, err = oracleInt64(raw.Flowoutputdestport2)
+	if err != nil {
+		logger.Error("Error parsing Flowoutputdestport2: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Flowoutputdestport2: %s", err)
+	}
+	ParsedRtcpcalledpacketslost, err = oracleInt64(raw.Rtcpcalledpacketslost)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcalledpacketslost: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcalledpacketslost: %s", err)
+	}
+	ParsedRtcpcalledavgjitter, err = oracleInt64(raw.Rtcpcalledavgjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcalledavgjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcalledavgjitter: %s", err)
+	}
+	ParsedRtcpcalledavglatency, err = oracleInt64(raw.Rtcpcalledavglatency)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcalledavglatency: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcalledavglatency: %s", err)
+	}
+	ParsedRtcpcalledmaxjitter, err = oracleInt64(raw.Rtcpcalledmaxjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcalledmaxjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcalledmaxjitter: %s", err)
+	}
+	ParsedRtcpcalledmaxlatency, err = oracleInt64(raw.Rtcpcalledmaxlatency)
+	if err != nil {
+		logger.Error("Error parsing Rtcpcalledmaxlatency: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtcpcalledmaxlatency: %s", err)
+	}
+	ParsedRtpcalledpacketslost, err = oracleInt64(raw.Rtpcalledpacketslost)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledpacketslost: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledpacketslost: %s", err)
+	}
+	ParsedRtpcalledavgjitter, err = oracleInt64(raw.Rtpcalledavgjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledavgjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledavgjitter: %s", err)
+	}
+	ParsedRtpcalledmaxjitter, err = oracleInt64(raw.Rtpcalledmaxjitter)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledmaxjitter: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledmaxjitter: %s", err)
+	}
+	ParsedRtpcalledoctets, err = oracleInt64(raw.Rtpcalledoctets)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledoctets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledoctets: %s", err)
+	}
+	ParsedRtpcalledpackets, err = oracleInt64(raw.Rtpcalledpackets)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledpackets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledpackets: %s", err)
+	}
+	ParsedCalledrfactor, err = scaledFloat(raw.Calledrfactor)
+	if err != nil {
+		logger.Error("Error parsing Calledrfactor: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Calledrfactor: %s", err)
+	}
+	ParsedCalledmos, err = scaledFloat(raw.Calledmos)
+	if err != nil {
+		logger.Error("Error parsing Calledmos: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Calledmos: %s", err)
+	}
+	ParsedFirmwareversion = trimmedString(raw.Firmwareversion)
+	ParsedLocaltimezone = trimmedString(raw.Localtimezone)
+	ParsedPostdialdelay, err = oracleInt64(raw.Postdialdelay)
+	if err != nil {
+		logger.Error("Error parsing Postdialdelay: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Postdialdelay: %s", err)
+	}
+	ParsedPrimaryroutingnumber = helpers.RemoveSpaceFromString(raw.Primaryroutingnumber)
+	ParsedIngresslocaladdress = helpers.RemoveSpaceFromString(raw.Ingresslocaladdress)
+	ParsedIngressremoteaddress = helpers.RemoveSpaceFromString(raw.Ingressremoteaddress)
+	ParsedEgresslocaladdress = helpers.RemoveSpaceFromString(raw.Egresslocaladdress)
+	ParsedEgressremoteaddress = helpers.RemoveSpaceFromString(raw.Egressremoteaddress)
+	ParsedSessiondisposition, err = oracleInt64(raw.Sessiondisposition)
+	if err != nil {
+		logger.Error("Error parsing Sessiondisposition: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Sessiondisposition: %s", err)
+	}
+	ParsedDisconnectinitiator, err = oracleInt64(raw.Disconnectinitiator)
+	if err != nil {
+		logger.Error("Error parsing Disconnectinitiator: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Disconnectinitiator: %s", err)
+	}
+	ParsedDisconnectcause, err = oracleInt64(raw.Disconnectcause)
+	if err != nil {
+		logger.Error("Error parsing Disconnectcause: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Disconnectcause: %s", err)
+	}
+	ParsedSipstatuscode, err = oracleInt64(raw.Sipstatuscode)
+	if err != nil {
+		logger.Error("Error parsing Sipstatuscode: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Sipstatuscode: %s", err)
+	}
+	ParsedEgressroutingnumber = helpers.RemoveSpaceFromString(raw.Egressroutingnumber)
+	ParsedCallingmediastoptime, err = helpers.ConvertStringToUnixTime(trimmedString(raw.Callingmediastoptime), nil)
+	if err == helpers.ErrInvalidNTPReferenceAsterisk || err == helpers.ErrInvalidNTPReferencePeriod {
+		logger.Error("Error parsing Callingmediastoptime: %s in %s", err, filename)
+	} else if err != nil {
+		logger.Error("Error parsing Callingmediastoptime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Callingmediastoptime: %s", err)
+	}
+	ParsedCalledmediastoptime, err = helpers.ConvertStringToUnixTime(trimmedString(raw.Calledmediastoptime), nil)
+	if err == helpers.ErrInvalidNTPReferenceAsterisk || err == helpers.ErrInvalidNTPReferencePeriod {
+		logger.Error("Error parsing Calledmediastoptime: %s in %s", err, filename)
+	} else if err != nil {
+		logger.Error("Error parsing Calledmediastoptime: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Calledmediastoptime: %s", err)
+	}
+	ParsedFlowmediatype = helpers.RemoveSpaceFromString(raw.Flowmediatype)
+	ParsedFlowmediatype2 = helpers.RemoveSpaceFromString(raw.Flowmediatype2)
+	ParsedRtpcallingoctetstransmitted, err = oracleInt64(raw.Rtpcallingoctetstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingoctetstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingoctetstransmitted: %s", err)
+	}
+	ParsedRtpcallingpacketstransmitted, err = oracleInt64(raw.Rtpcallingpacketstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Rtpcallingpacketstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcallingpacketstransmitted: %s", err)
+	}
+	ParsedRtpcalledoctetstransmitted, err = oracleInt64(raw.Rtpcalledoctetstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledoctetstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledoctetstransmitted: %s", err)
+	}
+	ParsedRtpcalledpacketstransmitted, err = oracleInt64(raw.Rtpcalledpacketstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Rtpcalledpacketstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Rtpcalledpacketstransmitted: %s", err)
+	}
+	ParsedMsrpcalledoctets, err = oracleInt64(raw.Msrpcalledoctets)
+	if err != nil {
+		logger.Error("Error parsing Msrpcalledoctets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcalledoctets: %s", err)
+	}
+	ParsedMsrpcalledpackets, err = oracleInt64(raw.Msrpcalledpackets)
+	if err != nil {
+		logger.Error("Error parsing Msrpcalledpackets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcalledpackets: %s", err)
+	}
+	ParsedMsrpcalledoctetstransmitted, err = oracleInt64(raw.Msrpcalledoctetstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Msrpcalledoctetstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcalledoctetstransmitted: %s", err)
+	}
+	ParsedMsrpcalledpacketstransmitted, err = oracleInt64(raw.Msrpcalledpacketstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Msrpcalledpacketstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcalledpacketstransmitted: %s", err)
+	}
+	ParsedMsrpcallingoctets, err = oracleInt64(raw.Msrpcallingoctets)
+	if err != nil {
+		logger.Error("Error parsing Msrpcallingoctets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcallingoctets: %s", err)
+	}
+	ParsedMsrpcallingpackets, err = oracleInt64(raw.Msrpcallingpackets)
+	if err != nil {
+		logger.Error("Error parsing Msrpcallingpackets: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcallingpackets: %s", err)
+	}
+	ParsedMsrpcallingoctetstransmitted, err = oracleInt64(raw.Msrpcallingoctetstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Msrpcallingoctetstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcallingoctetstransmitted: %s", err)
+	}
+	ParsedMsrpcallingpacketstransmitted, err = oracleInt64(raw.Msrpcallingpacketstransmitted)
+	if err != nil {
+		logger.Error("Error parsing Msrpcallingpacketstransmitted: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Msrpcallingpacketstransmitted: %s", err)
+	}
+	ParsedNodefunctionality = helpers.RemoveSpaceFromString(raw.Nodefunctionality)
+	ParsedCdrsequencenumber, err = oracleInt64(raw.Cdrsequencenumber)
+	if err != nil {
+		logger.Error("Error parsing Cdrsequencenumber: %s in %s", err, filename)
+		return nil, fmt.Errorf("error parsing Cdrsequencenumber: %s", err)
+	}
+
+	return &OracleCDR{
+		ID:                            uuid.New().String(),
+		Filename:                      helpers.RemoveSpaceFromString(raw.Filename),
+		Accountingstatus:              ParsedAccountingstatus,
+		Nasipaddress:                  ParsedNasipaddress,
+		Nasport:                       ParsedNasport,
+		Accountingsessionid:           ParsedAccountingsessionid,
+		Ingresssessionid:              ParsedIngresssessionid,
+		Egresssessionid:               ParsedEgresssessionid,
+		Sessionprotocoltype:           ParsedSessionprotocoltype,
+		Callingstationid:              ParsedCallingstationid,
+		Calledstationid:               ParsedCalledstationid,
+		Accountingterminationcause:    ParsedAccountingterminationcause,
+		Accountingsessiontime:         ParsedAccountingsessiontime,
+		Ciscosetuptime:                ParsedCiscosetuptime,
+		Ciscoconnecttime:              ParsedCiscoconnecttime,
+		Ciscodisconnecttime:           ParsedCiscodisconnecttime,
+		Ciscodisconnectcause:          ParsedCiscodisconnectcause,
+		Egressnetworkinterfaceid:      ParsedEgressnetworkinterfaceid,
+		Egressvlantagvalue:            ParsedEgressvlantagvalue,
+		Ingressnetworkinterfaceid:     ParsedIngressnetworkinterfaceid,
+		Ingressvlantagvalue:           ParsedIngressvlantagvalue,
+		Egressrealm:                   ParsedEgressrealm,
+		Ingressrealm:                  ParsedIngressrealm,
+		Flowidentifier:                ParsedFlowidentifier,
+		Flowtype:                      ParsedFlowtype,
+		Flowinputrealm:                ParsedFlowinputrealm,
+		Flowinputsrcaddr:              ParsedFlowinputsrcaddr,
+		Flowinputsrcport:              ParsedFlowinputsrcport,
+		Flowinputdestaddress:          ParsedFlowinputdestaddress,
+		Flowinputdestport:             ParsedFlowinputdestport,
+		Flowoutputrealm:               ParsedFlowoutputrealm,
+		Flowoutputsrcaddress:          ParsedFlowoutputsrcaddress,
+		Flowoutputsrcport:             ParsedFlowoutputsrcport,
+		Flowoutputdestaddr:            ParsedFlowoutputdestaddr,
+		Flowoutputdestport:            ParsedFlowoutputdestport,
+		Rtcpcallingpacketslost:        ParsedRtcpcallingpacketslost,
+		Rtcpcallingavgjitter:          ParsedRtcpcallingavgjitter,
+		Rtcpcallingavglatency:         ParsedRtcpcallingavglatency,
+		Rtcpcallingmaxjitter:          ParsedRtcpcallingmaxjitter,
+		Rtcpcallingmaxlatency:         ParsedRtcpcallingmaxlatency,
+		Rtpcallingpacketslost:         ParsedRtpcallingpacketslost,
+		Rtpcallingavgjitter:           ParsedRtpcallingavgjitter,
+		Rtpcallingmaxjitter:           ParsedRtpcallingmaxjitter,
+		Rtpcallingoctets:              ParsedRtpcallingoctets,
+		Rtpcallingpackets:             ParsedRtpcallingpackets,
+		Callingrfactor:                ParsedCallingrfactor,
+		Callingmos:                    ParsedCallingmos,
+		Flowidentifier2:               ParsedFlowidentifier2,
+		Flowtype2:                     ParsedFlowtype2,
+		Flowinputrealm2:               ParsedFlowinputrealm2,
+		Flowinputsrcaddr2:             ParsedFlowinputsrcaddr2,
+		Flowinputsrcport2:             ParsedFlowinputsrcport2,
+		Flowinputdestaddress2:         ParsedFlowinputdestaddress2,
+		Flowinputdestport2:            ParsedFlowinputdestport2,
+		Flowoutputrealm2:              ParsedFlowoutputrealm2,
+		Flowoutputsrcaddress2:         ParsedFlowoutputsrcaddress2,
+		Flowoutputsrcport2:            ParsedFlowoutputsrcport2,
+		Flowoutputdestaddr2:           ParsedFlowoutputdestaddr2,
+		Flowoutputdestport2:           ParsedFlowoutputdestport2,
+		Rtcpcalledpacketslost:         ParsedRtcpcalledpacketslost,
+		Rtcpcalledavgjitter:           ParsedRtcpcalledavgjitter,
+		Rtcpcalledavglatency:          ParsedRtcpcalledavglatency,
+		Rtcpcalledmaxjitter:           ParsedRtcpcalledmaxjitter,
+		Rtcpcalledmaxlatency:          ParsedRtcpcalledmaxlatency,
+		Rtpcalledpacketslost:          ParsedRtpcalledpacketslost,
+		Rtpcalledavgjitter:            ParsedRtpcalledavgjitter,
+		Rtpcalledmaxjitter:            ParsedRtpcalledmaxjitter,
+		Rtpcalledoctets:               ParsedRtpcalledoctets,
+		Rtpcalledpackets:              ParsedRtpcalledpackets,
+		Calledrfactor:                 ParsedCalledrfactor,
+		Calledmos:                     ParsedCalledmos,
+		Firmwareversion:               ParsedFirmwareversion,
+		Localtimezone:                 ParsedLocaltimezone,
+		Postdialdelay:                 ParsedPostdialdelay,
+		Primaryroutingnumber:          ParsedPrimaryroutingnumber,
+		Ingresslocaladdress:           ParsedIngresslocaladdress,
+		Ingressremoteaddress:          ParsedIngressremoteaddress,
+		Egresslocaladdress:            ParsedEgresslocaladdress,
+		Egressremoteaddress:           ParsedEgressremoteaddress,
+		Sessiondisposition:            ParsedSessiondisposition,
+		Disconnectinitiator:           ParsedDisconnectinitiator,
+		Disconnectcause:               ParsedDisconnectcause,
+		Sipstatuscode:                 ParsedSipstatuscode,
+		Egressroutingnumber:           ParsedEgressroutingnumber,
+		Callingmediastoptime:          ParsedCallingmediastoptime,
+		Calledmediastoptime:           ParsedCalledmediastoptime,
+		Flowmediatype:                 ParsedFlowmediatype,
+		Flowmediatype2:                ParsedFlowmediatype2,
+		Rtpcallingoctetstransmitted:   ParsedRtpcallingoctetstransmitted,
+		Rtpcallingpacketstransmitted:  ParsedRtpcallingpacketstransmitted,
+		Rtpcalledoctetstransmitted:    ParsedRtpcalledoctetstransmitted,
+		Rtpcalledpacketstransmitted:   ParsedRtpcalledpacketstransmitted,
+		Msrpcalledoctets:              ParsedMsrpcalledoctets,
+		Msrpcalledpackets:             ParsedMsrpcalledpackets,
+		Msrpcalledoctetstransmitted:   ParsedMsrpcalledoctetstransmitted,
+		Msrpcalledpacketstransmitted:  ParsedMsrpcalledpacketstransmitted,
+		Msrpcallingoctets:             ParsedMsrpcallingoctets,
+		Msrpcallingpackets:            ParsedMsrpcallingpackets,
+		Msrpcallingoctetstransmitted:  ParsedMsrpcallingoctetstransmitted,
+		Msrpcallingpacketstransmitted: ParsedMsrpcallingpacketstransmitted,
+		Nodefunctionality:             ParsedNodefunctionality,
+		Cdrsequencenumber:             ParsedCdrsequencenumber,
+	}, nil
+}
@@ -0,0 +1,463 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/helpers"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/google/uuid"
+)
+
+// uccxTimeLayouts are the time formats of UCCX exports. The database keeps
+// times in UTC, so times without a zone are UTC.
+var uccxTimeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", time.RFC3339Nano, "01/02/2006 15:04:05"}
+
+// uccxTime converts a UCCX time to Unix seconds. Fractional seconds are
+// accepted with every layout.
+func uccxTime(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	for _, layout := range uccxTimeLayouts {
+		if t, err := time.Parse(layout, *trimmed); err == nil {
+			unix := t.UTC().Unix()
+			return &unix, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", helpers.ErrInvalidTimeFormat, *trimmed)
+}
+
+// uccxBool converts the boolean columns, which Informix exports as t and f.
+func uccxBool(s *string) (*bool, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(*trimmed)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// uccxSignedInt converts a column that may be negative, such as the offset of
+// the node from UTC in minutes.
+func uccxSignedInt(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseInt(*trimmed, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// uccxGlobalCallID returns the CUCM Global Call ID of a contact. UCCX stores
+// the call reference Unified CM gives it as 32 hexadecimal digits, of which
+// digits 9 to 16 are the call ID, e.g. 0000000000018B0F02E2F4B900000000 is
+// call 101135. A decimal reference is the call ID itself. The originating
+// reference is used, or the destination one for outbound contacts.
+func uccxGlobalCallID(refs ...*string) *int64 {
+	for _, ref := range refs {
+		trimmed := trimmedString(ref)
+		if trimmed == nil {
+			continue
+		}
+		if value, err := strconv.ParseInt(*trimmed, 10, 64); err == nil && len(*trimmed) < 16 {
+			return &value
+		}
+		if len(*trimmed) != 32 {
+			continue
+		}
+		if value, err := strconv.ParseInt((*trimmed)[8:16], 16, 64); err == nil && value > 0 {
+			return &value
+		}
+	}
+	return nil
+}
+
+// RawUccxContactCallDetail is a row of a ContactCallDetail export.
+type RawUccxContactCallDetail struct {
+	Filename            *string
+	Sessionid           *string
+	Sessionseqnum       *string
+	Nodeid              *string
+	Profileid           *string
+	Contacttype         *string
+	Contactdisposition  *string
+	Dispositionreason   *string
+	Originatortype      *string
+	Originatorid        *string
+	Originatordn        *string
+	Destinationtype     *string
+	Destinationid       *string
+	Destinationdn       *string
+	Startdatetime       *string
+	Enddatetime         *string
+	Gmtoffset           *string
+	Callednumber        *string
+	Origcallednumber    *string
+	Applicationtaskid   *string
+	Applicationid       *string
+	Applicationname     *string
+	Connecttime         *string
+	Customvariable1     *string
+	Customvariable2     *string
+	Customvariable3     *string
+	Customvariable4     *string
+	Customvariable5     *string
+	Customvariable6     *string
+	Customvariable7     *string
+	Customvariable8     *string
+	Customvariable9     *string
+	Customvariable10    *string
+	Accountnumber       *string
+	Callerentereddigits *string
+	Badcalltag          *string
+	Transfer            *string
+	Redirect            *string
+	Conference          *string
+	Flowout             *string
+	Metservicelevel     *string
+	Campaignid          *string
+	Origprotocolcallref *string
+	Destprotocolcallref *string
+	Callresult          *string
+	Dialinglistid       *string
+}
+
+// Parse converts the row. Only an invalid sessionID or startDateTime rejects it,
+// other invalid values are logged and stored as NULL.
+func (raw *RawUccxContactCallDetail) Parse(filename string) (*UccxContactCallDetail, error) {
+	var err error
+
+	var ParsedSessionid *int64
+	var ParsedSessionseqnum *int64
+	var ParsedNodeid *int64
+	var ParsedProfileid *int64
+	var ParsedContacttype *int64
+	var ParsedContactdisposition *int64
+	var ParsedOriginatortype *int64
+	var ParsedOriginatorid *int64
+	var ParsedDestinationtype *int64
+	var ParsedDestinationid *int64
+	var ParsedStartdatetime *int64
+	var ParsedEnddatetime *int64
+	var ParsedGmtoffset *int64
+	var ParsedApplicationtaskid *int64
+	var ParsedApplicationid *int64
+	var ParsedConnecttime *int64
+	var ParsedTransfer *bool
+	var ParsedRedirect *bool
+	var ParsedConference *bool
+	var ParsedFlowout *bool
+	var ParsedMetservicelevel *bool
+	var ParsedCampaignid *int64
+	var ParsedCallresult *int64
+	var ParsedDialinglistid *int64
+
+	ParsedSessionid, err = helpers.ConvertStringToInt64(trimmedString(raw.Sessionid))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sessionID: %w", err)
+	}
+	ParsedSessionseqnum, err = helpers.ConvertStringToInt64(trimmedString(raw.Sessionseqnum))
+	if err != nil {
+		logger.Error("Error parsing Sessionseqnum: %s in %s", err, filename)
+	}
+	ParsedNodeid, err = helpers.ConvertStringToInt64(trimmedString(raw.Nodeid))
+	if err != nil {
+		logger.Error("Error parsing Nodeid: %s in %s", err, filename)
+	}
+	ParsedProfileid, err = helpers.ConvertStringToInt64(trimmedString(raw.Profileid))
+	if err != nil {
+		logger.Error("Error parsing Profileid: %s in %s", err, filename)
+	}
+	ParsedContacttype, err = helpers.ConvertStringToInt64(trimmedString(raw.Contacttype))
+	if err != nil {
+		logger.Error("Error parsing Contacttype: %s in %s", err, filename)
+	}
+	ParsedContactdisposition, err = helpers.ConvertStringToInt64(trimmedString(raw.Contactdisposition))
+	if err != nil {
+		logger.Error("Error parsing Contactdisposition: %s in %s", err, filename)
+	}
+	ParsedOriginatortype, err = helpers.ConvertStringToInt64(trimmedString(raw.Originatortype))
+	if err != nil {
+		logger.Error("Error parsing Originatortype: %s in %s", err, filename)
+	}
+	ParsedOriginatorid, err = helpers.ConvertStringToInt64(trimmedString(raw.Originatorid))
+	if err != nil {
+		logger.Error("Error parsing Originatorid: %s in %s", err, filename)
+	}
+	ParsedDestinationtype, err = helpers.ConvertStringToInt64(trimmedString(raw.Destinationtype))
+	if err != nil {
+		logger.Error("Error parsing Destinationtype: %s in %s", err, filename)
+	}
+	ParsedDestinationid, err = helpers.ConvertStringToInt64(trimmedString(raw.Destinationid))
+	if err != nil {
+		logger.Error("Error parsing Destinationid: %s in %s", err, filename)
+	}
+	ParsedStartdatetime, err = uccxTime(raw.Startdatetime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startDateTime: %w", err)
+	}
+	ParsedEnddatetime, err = uccxTime(raw.Enddatetime)
+	if err != nil {
+		logger.Error("Error parsing Enddatetime: %s in %s", err, filename)
+	}
+	ParsedGmtoffset, err = uccxSignedInt(raw.Gmtoffset)
+	if err != nil {
+		logger.Error("Error parsing Gmtoffset: %s in %s", err, filename)
+	}
+	ParsedApplicationtaskid, err = helpers.ConvertStringToInt64(trimmedString(raw.Applicationtaskid))
+	if err != nil {
+		logger.Error("Error parsing Applicationtaskid: %s in %s", err, filename)
+	}
+	ParsedApplicationid, err = helpers.ConvertStringToInt64(trimmedString(raw.Applicationid))
+	if err != nil {
+		logger.Error("Error parsing Applicationid: %s in %s", err, filename)
+	}
+	ParsedConnecttime, err = helpers.ConvertStringToInt64(trimmedString(raw.Connecttime))
+	if err != nil {
+		logger.Error("Error parsing Connecttime: %s in %s", err, filename)
+	}
+	ParsedTransfer, err = uccxBool(raw.Transfer)
+	if err != nil {
+		logger.Error("Error parsing Transfer: %s in %s", err, filename)
+	}
+	ParsedRedirect, err = uccxBool(raw.Redirect)
+	if err != nil {
+		logger.Error("Error parsing Redirect: %s in %s", err, filename)
+	}
+	ParsedConference, err = uccxBool(raw.Conference)
+	if err != nil {
+		logger.Error("Error parsing Conference: %s in %s", err, filename)
+	}
+	ParsedFlowout, err = uccxBool(raw.Flowout)
+	if err != nil {
+		logger.Error("Error parsing Flowout: %s in %s", err, filename)
+	}
+	ParsedMetservicelevel, err = uccxBool(raw.Metservicelevel)
+	if err != nil {
+		logger.Error("Error parsing Metservicelevel: %s in %s", err, filename)
+	}
+	ParsedCampaignid, err = helpers.ConvertStringToInt64(trimmedString(raw.Campaignid))
+	if err != nil {
+		logger.Error("Error parsing Campaignid: %s in %s", err, filename)
+	}
+	ParsedCallresult, err = helpers.ConvertStringToInt64(trimmedString(raw.Callresult))
+	if err != nil {
+		logger.Error("Error parsing Callresult: %s in %s", err, filename)
+	}
+	ParsedDialinglistid, err = helpers.ConvertStringToInt64(trimmedString(raw.Dialinglistid))
+	if err != nil {
+		logger.Error("Error parsing Dialinglistid: %s in %s", err, filename)
+	}
+	if ParsedSessionid == nil {
+		return nil, fmt.Errorf("missing sessionID")
+	}
+	if ParsedStartdatetime == nil {
+		return nil, fmt.Errorf("missing startDateTime")
+	}
+
+	return &UccxContactCallDetail{
+		ID:                  uuid.New().String(),
+		Filename:            helpers.RemoveSpaceFromString(raw.Filename),
+		Sessionid:           ParsedSessionid,
+		Sessionseqnum:       ParsedSessionseqnum,
+		Nodeid:              ParsedNodeid,
+		Profileid:           ParsedProfileid,
+		Contacttype:         ParsedContacttype,
+		Contactdisposition:  ParsedContactdisposition,
+		Dispositionreason:   trimmedString(raw.Dispositionreason),
+		Originatortype:      ParsedOriginatortype,
+		Originatorid:        ParsedOriginatorid,
+		Originatordn:        trimmedString(raw.Originatordn),
+		Destinationtype:     ParsedDestinationtype,
+		Destinationid:       ParsedDestinationid,
+		Destinationdn:       trimmedString(raw.Destinationdn),
+		Startdatetime:       ParsedStartdatetime,
+		Enddatetime:         ParsedEnddatetime,
+		Gmtoffset:           ParsedGmtoffset,
+		Callednumber:        trimmedString(raw.Callednumber),
+		Origcallednumber:    trimmedString(raw.Origcallednumber),
+		Applicationtaskid:   ParsedApplicationtaskid,
+		Applicationid:       ParsedApplicationid,
+		Applicationname:     trimmedString(raw.Applicationname),
+		Connecttime:         ParsedConnecttime,
+		Customvariable1:     trimmedString(raw.Customvariable1),
+		Customvariable2:     trimmedString(raw.Customvariable2),
+		Customvariable3:     trimmedString(raw.Customvariable3),
+		Customvariable4:     trimmedString(raw.Customvariable4),
+		Customvariable5:     trimmedString(raw.Customvariable5),
+		Customvariable6:     trimmedString(raw.Customvariable6),
+		Customvariable7:     trimmedString(raw.Customvariable7),
+		Customvariable8:     trimmedString(raw.Customvariable8),
+		Customvariable9:     trimmedString(raw.Customvariable9),
+		Customvariable10:    trimmedString(raw.Customvariable10),
+		Accountnumber:       trimmedString(raw.Accountnumber),
+		Callerentereddigits: trimmedString(raw.Callerentereddigits),
+		Badcalltag:          trimmedString(raw.Badcalltag),
+		Transfer:            ParsedTransfer,
+		Redirect:            ParsedRedirect,
+		Conference:          ParsedConference,
+		Flowout:             ParsedFlowout,
+		Metservicelevel:     ParsedMetservicelevel,
+		Campaignid:          ParsedCampaignid,
+		Origprotocolcallref: trimmedString(raw.Origprotocolcallref),
+		Destprotocolcallref: trimmedString(raw.Destprotocolcallref),
+		Globalcallid_Callid: uccxGlobalCallID(raw.Origprotocolcallref, raw.Destprotocolcallref),
+		Callresult:          ParsedCallresult,
+		Dialinglistid:       ParsedDialinglistid,
+	}, nil
+}
+
+// RawUccxAgentConnectionDetail is a row of an AgentConnectionDetail export.
+type RawUccxAgentConnectionDetail struct {
+	Filename       *string
+	Sessionid      *string
+	Sessionseqnum  *string
+	Nodeid         *string
+	Profileid      *string
+	Resourceid     *string
+	Startdatetime  *string
+	Enddatetime    *string
+	Qindex         *string
+	Gmtoffset      *string
+	Ringtime       *string
+	Talktime       *string
+	Holdtime       *string
+	Worktime       *string
+	Callwrapupdata *string
+	Callresult     *string
+	Dialinglistid  *string
+}
+
+// Parse converts the row. Only an invalid sessionID or startDateTime rejects it,
+// other invalid values are logged and stored as NULL.
+func (raw *RawUccxAgentConnectionDetail) Parse(filename string) (*UccxAgentConnectionDetail, error) {
+	var err error
+
+	var ParsedSessionid *int64
+	var ParsedSessionseqnum *int64
+	var ParsedNodeid *int64
+	var ParsedProfileid *int64
+	var ParsedResourceid *int64
+	var ParsedStartdatetime *int64
+	var ParsedEnddatetime *int64
+	var ParsedQindex *int64
+	var ParsedGmtoffset *int64
+	var ParsedRingtime *int64
+	var ParsedTalktime *int64
+	var ParsedHoldtime *int64
+	var ParsedWorktime *int64
+	var ParsedCallresult *int64
+	var ParsedDialinglistid *int64
+
+	ParsedSessionid, err = helpers.ConvertStringToInt64(trimmedString(raw.Sessionid))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sessionID: %w", err)
+	}
+	ParsedSessionseqnum, err = helpers.ConvertStringToInt64(trimmedString(raw.Sessionseqnum))
+	if err != nil {
+		logger.Error("Error parsing Sessionseqnum: %s in %s", err, filename)
+	}
+	ParsedNodeid, err = helpers.ConvertStringToInt64(trimmedString(raw.Nodeid))
+	if err != nil {
+		logger.Error("Error parsing Nodeid: %s in %s", err, filename)
+	}
+	ParsedProfileid, err = helpers.ConvertStringToInt64(trimmedString(raw.Profileid))
+	if err != nil {
+		logger.Error("Error parsing Profileid: %s in %s", err, filename)
+	}
+	ParsedResourceid, err = helpers.ConvertStringToInt64(trimmedString(raw.Resourceid))
+	if err != nil {
+		logger.Error("Error parsing Resourceid: %s in %s", err, filename)
+	}
+	ParsedStartdatetime, err = uccxTime(raw.Startdatetime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startDateTime: %w", err)
+	}
+	ParsedEnddatetime, err = uccxTime(raw.Enddatetime)
+	if err != nil {
+		logger.Error("Error parsing Enddatetime: %s in %s", err, filename)
+	}
+	ParsedQindex, err = helpers.ConvertStringToInt64(trimmedString(raw.Qindex))
+	if err != nil {
+		logger.Error("Error parsing Qindex: %s in %s", err, filename)
+	}
+	ParsedGmtoffset, err = uccxSignedInt(raw.Gmtoffset)
+	if err != nil {
+		logger.Error("Error parsing Gmtoffset: %s in %s", err, filename)
+	}
+	ParsedRingtime, err = helpers.ConvertStringToInt64(trimmedString(raw.Ringtime))
+	if err != nil {
+		logger.Error("Error parsing Ringtime: %s in %s", err, filename)
+	}
+	ParsedTalktime, err = helpers.ConvertStringToInt64(trimmedString(raw.Talktime))
+	if err != nil {
+		logger.Error("Error parsing Talktime: %s in %s", err, filename)
+	}
+	ParsedHoldtime, err = helpers.ConvertStringToInt64(trimmedString(raw.Holdtime))
+	if err != nil {
+		logger.Error("Error parsing Holdtime: %s in %s", err, filename)
+	}
+	ParsedWorktime, err = helpers.ConvertStringToInt64(trimmedString(raw.Worktime))
+	if err != nil {
+		logger.Error("Error parsing Worktime: %s in %s", err, filename)
+	}
+	ParsedCallresult, err = helpers.ConvertStringToInt64(trimmedString(raw.Callresult))
+	if err != nil {
+		logger.Error("Error parsing Callresult: %s in %s", err, filename)
+	}
+	ParsedDialinglistid, err = helpers.ConvertStringToInt64(trimmedString(raw.Dialinglistid))
+	if err != nil {
+		logger.Error("Error parsing Dialinglistid: %s in %s", err, filename)
+	}
+	if ParsedSessionid == nil {
+		return nil, fmt.Errorf("missing sessionID")
+	}
+	if ParsedStartdatetime == nil {
+		return nil, fmt.Errorf("missing startDateTime")
+	}
+
+	return &UccxAgentConnectionDetail{
+		ID:             uuid.New().String(),
+		Filename:       helpers.RemoveSpaceFromString(raw.Filename),
+		Sessionid:      ParsedSessionid,
+		Sessionseqnum:  ParsedSessionseqnum,
+		Nodeid:         ParsedNodeid,
+		Profileid:      ParsedProfileid,
+		Resourceid:     ParsedResourceid,
+		Startdatetime:  ParsedStartdatetime,
+		Enddatetime:    ParsedEnddatetime,
+		Qindex:         ParsedQindex,
+		Gmtoffset:      ParsedGmtoffset,
+		Ringtime:       ParsedRingtime,
+		Talktime:       ParsedTalktime,
+		Holdtime:       ParsedHoldtime,
+		Worktime:       ParsedWorktime,
+		Callwrapupdata: trimmedString(raw.Callwrapupdata),
+		Callresult:     ParsedCallresult,
+		Dialinglistid:  ParsedDialinglistid,
+	}, nil
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/helpers"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/google/uuid"
+)
+
+// webexTimeLayouts are the time formats of the Detailed Call History. The
+// export uses ISO 8601 in UTC, older reports a plain UTC date and time.
+var webexTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02 15:04:05.000"}
+
+// RawWebexCDR is a row of a Webex Calling Detailed Call History CSV file.
+type RawWebexCDR struct {
+	Filename               *string
+	Starttime              *string
+	Answertime             *string
+	Duration               *string
+	Reportid               *string
+	Reporttime             *string
+	Callid                 *string
+	Correlationid          *string
+	Localcallid            *string
+	Remotecallid           *string
+	Networkcallid          *string
+	Relatedcallid          *string
+	Transferrelatedcallid  *string
+	User                   *string
+	Usertype               *string
+	Useruuid               *string
+	Callinglineid          *string
+	Calledlineid           *string
+	Callingnumber          *string
+	Callednumber           *string
+	Dialeddigits           *string
+	Calleridnumber         *string
+	Redirectingnumber      *string
+	Direction              *string
+	Calltype               *string
+	Clienttype             *string
+	Clientversion          *string
+	Subclienttype          *string
+	Devicemac              *string
+	Model                  *string
+	Ostype                 *string
+	Location               *string
+	Locationuuid           *string
+	Siteuuid               *string
+	Orguuid                *string
+	Departmentid           *string
+	Answered               *string
+	Answerindicator        *string
+	Originalreason         *string
+	Redirectreason         *string
+	Relatedreason          *string
+	Inboundtrunk           *string
+	Outboundtrunk          *string
+	Routegroup             *string
+	Internationalcountry   *string
+	Authorizationcode      *string
+	Calltransfertime       *string
+	Ringduration           *string
+	Holdduration           *string
+	Waittime               *string
+	Queuetype              *string
+	Releasetime            *string
+	Releasingparty         *string
+	Releasecause           *string
+	Calloutcome            *string
+	Calloutcomereason      *string
+	Localsessionid         *string
+	Remotesessionid        *string
+	Finallocalsessionid    *string
+	Finalremotesessionid   *string
+	Pstnvendorname         *string
+	Pstnlegalentity        *string
+	Pstnvendororgid        *string
+	Pstnproviderid         *string
+	Publiccallingipaddress *string
+	Publiccalledipaddress  *string
+}
+
+// webexTime converts a Detailed Call History time to Unix seconds.
+func webexTime(s *string) (*int64, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	for _, layout := range webexTimeLayouts {
+		if t, err := time.Parse(layout, *trimmed); err == nil {
+			unix := t.UTC().Unix()
+			return &unix, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", helpers.ErrInvalidTimeFormat, *trimmed)
+}
+
+// webexBool converts the true and false values of the export.
+func webexBool(s *string) (*bool, error) {
+	trimmed := trimmedString(s)
+	if trimmed == nil {
+		return nil, nil
+	}
+	value, err := strconv.ParseBool(*trimmed)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// Parse converts the row. Only an invalid Start time rejects it, other
+// invalid values are logged and stored as NULL.
+func (raw *RawWebexCDR) Parse(filename string) (*WebexCDR, error) {
+	var err error
+
+	var ParsedStarttime *int64
+	var ParsedAnswertime *int64
+	var ParsedDuration *int64
+	var ParsedReportid *string
+	var ParsedReporttime *int64
+	var ParsedCallid *string
+	var ParsedCorrelationid *string
+	var ParsedLocalcallid *string
+	var ParsedRemotecallid *string
+	var ParsedNetworkcallid *string
+	var ParsedRelatedcallid *string
+	var ParsedTransferrelatedcallid *string
+	var ParsedUser *string
+	var ParsedUsertype *string
+	var ParsedUseruuid *string
+	var ParsedCallinglineid *string
+	var ParsedCalledlineid *string
+	var ParsedCallingnumber *string
+	var ParsedCallednumber *string
+	var ParsedDialeddigits *string
+	var ParsedCalleridnumber *string
+	var ParsedRedirectingnumber *string
+	var ParsedDirection *string
+	var ParsedCalltype *string
+	var ParsedClienttype *string
+	var ParsedClientversion *string
+	var ParsedSubclienttype *string
+	var ParsedDevicemac *string
+	var ParsedModel *string
+	var ParsedOstype *string
+	var ParsedLocation *string
+	var ParsedLocationuuid *string
+	var ParsedSiteuuid *string
+	var ParsedOrguuid *string
+	var ParsedDepartmentid *string
+	var ParsedAnswered *bool
+	var ParsedAnswerindicator *string
+	var ParsedOriginalreason *string
+	var ParsedRedirectreason *string
+	var ParsedRelatedreason *string
+	var ParsedInboundtrunk *string
+	var ParsedOutboundtrunk *string
+	var ParsedRoutegroup *string
+	var ParsedInternationalcountry *string
+	var ParsedAuthorizationcode *string
+	var ParsedCalltransfertime *int64
+	var ParsedRingduration *int64
+	var ParsedHoldduration *int64
+	var ParsedWaittime *int64
+	var ParsedQueuetype *string
+	var ParsedReleasetime *int64
+	var ParsedReleasingparty *string
+	var ParsedReleasecause *string
+	var ParsedCalloutcome *string
+	var ParsedCalloutcomereason *string
+	var ParsedLocalsessionid *string
+	var ParsedRemotesessionid *string
+	var ParsedFinallocalsessionid *string
+	var ParsedFinalremotesessionid *string
+	var ParsedPstnvendorname *string
+	var ParsedPstnlegalentity *string
+	var ParsedPstnvendororgid *string
+	var ParsedPstnproviderid *string
+	var ParsedPubliccallingipaddress *string
+	var ParsedPubliccalledipaddress *string
+
+	ParsedStarttime, err = webexTime(raw.Starttime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Start time: %w", err)
+	}
+	ParsedAnswertime, err = webexTime(raw.Answertime)
+	if err != nil {
+		logger.Error("Error parsing Answertime: %s in %s", err, filename)
+	}
+	ParsedDuration, err = helpers.ConvertStringToInt64(raw.Duration)
+	if err != nil {
+		logger.Error("Error parsing Duration: %s in %s", err, filename)
+	}
+	ParsedReportid = trimmedString(raw.Reportid)
+	ParsedReporttime, err = webexTime(raw.Reporttime)
+	if err != nil {
+		logger.Error("Error parsing Reporttime: %s in %s", err, filename)
+	}
+	ParsedCallid = trimmedString(raw.Callid)
+	ParsedCorrelationid = trimmedString(raw.Correlationid)
+	ParsedLocalcallid = trimmedString(raw.Localcallid)
+	ParsedRemotecallid = trimmedString(raw.Remotecallid)
+	ParsedNetworkcallid = trimmedString(raw.Networkcallid)
+	ParsedRelatedcallid = trimmedString(raw.Relatedcallid)
+	ParsedTransferrelatedcallid = trimmedString(raw.Transferrelatedcallid)
+	ParsedUser = trimmedString(raw.User)
+	ParsedUsertype = trimmedString(raw.Usertype)
+	ParsedUseruuid = trimmedString(raw.Useruuid)
+	ParsedCallinglineid = trimmedString(raw.Callinglineid)
+	ParsedCalledlineid = trimmedString(raw.Calledlineid)
+	ParsedCallingnumber = trimmedString(raw.Callingnumber)
+	ParsedCallednumber = trimmedString(raw.Callednumber)
+	ParsedDialeddigits = trimmedString(raw.Dialeddigits)
+	ParsedCalleridnumber = trimmedString(raw.Calleridnumber)
+	ParsedRedirectingnumber = trimmedString(raw.Redirectingnumber)
+	ParsedDirection = trimmedString(raw.Direction)
+	ParsedCalltype = trimmedString(raw.Calltype)
+	ParsedClienttype = trimmedString(raw.Clienttype)
+	ParsedClientversion = trimmedString(raw.Clientversion)
+	ParsedSubclienttype = trimmedString(raw.Subclienttype)
+	ParsedDevicemac = trimmedString(raw.Devicemac)
+	ParsedModel = trimmedString(raw.Model)
+	ParsedOstype = trimmedString(raw.Ostype)
+	ParsedLocation = trimmedString(raw.Location)
+	ParsedLocationuuid = trimmedString(raw.Locationuuid)
+	ParsedSiteuuid = trimmedString(raw.Siteuuid)
+	ParsedOrguuid = trimmedString(raw.Orguuid)
+	ParsedDepartmentid = trimmedString(raw.Departmentid)
+	ParsedAnswered, err = webexBool(raw.Answered)
+	if err != nil {
+		logger.Error("Error parsing Answered: %s in %s", err, filename)
+	}
+	ParsedAnswerindicator = trimmedString(raw.Answerindicator)
+	ParsedOriginalreason = trimmedString(raw.Originalreason)
+	ParsedRedirectreason = trimmedString(raw.Redirectreason)
+	ParsedRelatedreason = trimmedString(raw.Relatedreason)
+	ParsedInboundtrunk = trimmedString(raw.Inboundtrunk)
+	ParsedOutboundtrunk = trimmedString(raw.Outboundtrunk)
+	ParsedRoutegroup = trimmedString(raw.Routegroup)
+	ParsedInternationalcountry = trimmedString(raw.Internationalcountry)
+	ParsedAuthorizationcode = trimmedString(raw.Authorizationcode)
+	ParsedCalltransfertime, err = webexTime(raw.Calltransfertime)
+	if err != nil {
+		logger.Error("Error parsing Calltransfertime: %s in %s", err, filename)
+	}
+	ParsedRingduration, err = helpers.ConvertStringToInt64(raw.Ringduration)
+	if err != nil {
+		logger.Error("Error parsing Ringduration: %s in %s", err, filename)
+	}
+	ParsedHoldduration, err = helpers.ConvertStringToInt64(raw.Holdduration)
+	if err != nil {
+		logger.Error("Error parsing Holdduration: %s in %s", err, filename)
+	}
+	ParsedWaittime, err = helpers.ConvertStringToInt64(raw.Waittime)
+	if err != nil {
+		logger.Error("Error parsing Waittime: %s in %s", err, filename)
+	}
+	ParsedQueuetype = trimmedString(raw.Queuetype)
+	ParsedReleasetime, err = webexTime(raw.Releasetime)
+	if err != nil {
+		logger.Error("Error parsing Releasetime: %s in %s", err, filename)
+	}
+	ParsedReleasingparty = trimmedString(raw.Releasingparty)
+	ParsedReleasecause = trimmedString(raw.Releasecause)
+	ParsedCalloutcome = trimmedString(raw.Calloutcome)
+	ParsedCalloutcomereason = trimmedString(raw.Calloutcomereason)
+	ParsedLocalsessionid = trimmedString(raw.Localsessionid)
+	ParsedRemotesessionid = trimmedString(raw.Remotesessionid)
+	ParsedFinallocalsessionid = trimmedString(raw.Finallocalsessionid)
+	ParsedFinalremotesessionid = trimmedString(raw.Finalremotesessionid)
+	ParsedPstnvendorname = trimmedString(raw.Pstnvendorname)
+	ParsedPstnlegalentity = trimmedString(raw.Pstnlegalentity)
+	ParsedPstnvendororgid = trimmedString(raw.Pstnvendororgid)
+	ParsedPstnproviderid = trimmedString(raw.Pstnproviderid)
+	ParsedPubliccallingipaddress = trimmedString(raw.Publiccallingipaddress)
+	ParsedPubliccalledipaddress = trimmedString(raw.Publiccalledipaddress)
+
+	return &WebexCDR{
+		ID:                     uuid.New().String(),
+		Filename:               helpers.RemoveSpaceFromString(raw.Filename),
+		Starttime:              ParsedStarttime,
+		Answertime:             ParsedAnswertime,
+		Duration:               ParsedDuration,
+		Reportid:               ParsedReportid,
+		Reporttime:             ParsedReporttime,
+		Callid:                 ParsedCallid,
+		Correlationid:          ParsedCorrelationid,
+		Localcallid:            ParsedLocalcallid,
+		Remotecallid:           ParsedRemotecallid,
+		Networkcallid:          ParsedNetworkcallid,
+		Relatedcallid:          ParsedRelatedcallid,
+		Transferrelatedcallid:  ParsedTransferrelatedcallid,
+		User:                   ParsedUser,
+		Usertype:               ParsedUsertype,
+		Useruuid:               ParsedUseruuid,
+		Callinglineid:          ParsedCallinglineid,
+		Calledlineid:           ParsedCalledlineid,
+		Callingnumber:          ParsedCallingnumber,
+		Callednumber:           ParsedCallednumber,
+		Dialeddigits:           ParsedDialeddigits,
+		Calleridnumber:         ParsedCalleridnumber,
+		Redirectingnumber:      ParsedRedirectingnumber,
+		Direction:              ParsedDirection,
+		Calltype:               ParsedCalltype,
+		Clienttype:             ParsedClienttype,
+		Clientversion:          ParsedClientversion,
+		Subclienttype:          ParsedSubclienttype,
+		Devicemac:              ParsedDevicemac,
+		Model:                  ParsedModel,
+		Ostype:                 ParsedOstype,
+		Location:               ParsedLocation,
+		Locationuuid:           ParsedLocationuuid,
+		Siteuuid:               ParsedSiteuuid,
+		Orguuid:                ParsedOrguuid,
+		Departmentid:           ParsedDepartmentid,
+		Answered:               ParsedAnswered,
+		Answerindicator:        ParsedAnswerindicator,
+		Originalreason:         ParsedOriginalreason,
+		Redirectreason:         ParsedRedirectreason,
+		Relatedreason:          ParsedRelatedreason,
+		Inboundtrunk:           ParsedInboundtrunk,
+		Outboundtrunk:          ParsedOutboundtrunk,
+		Routegroup:             ParsedRoutegroup,
+		Internationalcountry:   ParsedInternationalcountry,
+		Authorizationcode:      ParsedAuthorizationcode,
+		Calltransfertime:       ParsedCalltransfertime,
+		Ringduration:           ParsedRingduration,
+		Holdduration:           ParsedHoldduration,
+		Waittime:               ParsedWaittime,
+		Queuetype:              ParsedQueuetype,
+		Releasetime:            ParsedReleasetime,
+		Releasingparty:         ParsedReleasingparty,
+		Releasecause:           ParsedReleasecause,
+		Calloutcome:            ParsedCalloutcome,
+		Calloutcomereason:      ParsedCalloutcomereason,
+		Localsessionid:         ParsedLocalsessionid,
+		Remotesessionid:        ParsedRemotesessionid,
+		Finallocalsessionid:    ParsedFinallocalsessionid,
+		Finalremotesessionid:   ParsedFinalremotesessionid,
+		Pstnvendorname:         ParsedPstnvendorname,
+		Pstnlegalentity:        ParsedPstnlegalentity,
+		Pstnvendororgid:        ParsedPstnvendororgid,
+		Pstnproviderid:         ParsedPstnproviderid,
+		Publiccallingipaddress: ParsedPubliccallingipaddress,
+		Publiccalledipaddress:  ParsedPubliccalledipaddress,
+	}, nil
+}
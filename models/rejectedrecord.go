@@ -0,0 +1,49 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// RejectReason says why a row of an input file was not written.
+type RejectReason string
+
+const (
+	// RejectMalformedRow is a row that is not valid CSV, such as one with a
+	// stray quote.
+	RejectMalformedRow RejectReason = "malformed_row"
+	// RejectFieldCount is a row with fewer or more fields than the file
+	// format has.
+	RejectFieldCount RejectReason = "field_count"
+	// RejectInvalidValue is a row with a field that could not be converted to
+	// its column type.
+	RejectInvalidValue RejectReason = "invalid_value"
+)
+
+// RejectedRecord is a row that was read from an input file but not written,
+// kept with its raw content so it can be audited and replayed once the data
+// is fixed.
+type RejectedRecord struct {
+	ID             string
+	IngestedFileId *string `gorm:"index"`
+	// File is the name of the file the row was read from, the member name
+	// for archives.
+	File   string
+	Line   int64
+	Type   string
+	Reason RejectReason
+	Error  *string
+	Raw    string
+	// CreatedAt is the time the row was rejected.
+	CreatedAt int64
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// UccxContactCallDetail is a row of the ContactCallDetail table of Cisco
+// Unified Contact Center Express. Every leg of a contact through UCCX is a
+// row, and the rows of a contact share the Sessionid. Times are Unix seconds
+// in UTC and durations are seconds.
+//
+// Globalcallid_Callid is read from the protocol call reference of the
+// contact, and matches the Globalcallid_Callid of the CUCM CDR of the leg to
+// the CTI route point.
+type UccxContactCallDetail struct {
+	ID                  string
+	IngestedFileId      *string `gorm:"index"`
+	Filename            *string
+	Sessionid           *int64 `gorm:"index"`
+	Sessionseqnum       *int64
+	Nodeid              *int64
+	Profileid           *int64
+	Contacttype         *int64
+	Contactdisposition  *int64
+	Dispositionreason   *string
+	Originatortype      *int64
+	Originatorid        *int64
+	Originatordn        *string
+	Destinationtype     *int64
+	Destinationid       *int64
+	Destinationdn       *string
+	Startdatetime       *int64
+	Enddatetime         *int64
+	Gmtoffset           *int64
+	Callednumber        *string
+	Origcallednumber    *string
+	Applicationtaskid   *int64
+	Applicationid       *int64
+	Applicationname     *string
+	Connecttime         *int64
+	Customvariable1     *string
+	Customvariable2     *string
+	Customvariable3     *string
+	Customvariable4     *string
+	Customvariable5     *string
+	Customvariable6     *string
+	Customvariable7     *string
+	Customvariable8     *string
+	Customvariable9     *string
+	Customvariable10    *string
+	Accountnumber       *string
+	Callerentereddigits *string
+	Badcalltag          *string
+	Transfer            *bool
+	Redirect            *bool
+	Conference          *bool
+	Flowout             *bool
+	Metservicelevel     *bool
+	Campaignid          *int64
+	Origprotocolcallref *string
+	Destprotocolcallref *string
+	Globalcallid_Callid *int64 `gorm:"index"`
+	Callresult          *int64
+	Dialinglistid       *int64
+}
+
+// UccxAgentConnectionDetail is a row of the AgentConnectionDetail table of
+// UCCX, one for every agent a contact was presented to. It belongs to the
+// UccxContactCallDetail with the same Sessionid, Sessionseqnum, Nodeid and
+// Profileid. Times are Unix seconds in UTC and durations are seconds.
+type UccxAgentConnectionDetail struct {
+	ID             string
+	IngestedFileId *string `gorm:"index"`
+	Filename       *string
+	Sessionid      *int64 `gorm:"index"`
+	Sessionseqnum  *int64
+	Nodeid         *int64
+	Profileid      *int64
+	Resourceid     *int64
+	Startdatetime  *int64
+	Enddatetime    *int64
+	Qindex         *int64
+	Gmtoffset      *int64
+	Ringtime       *int64
+	Talktime       *int64
+	Holdtime       *int64
+	Worktime       *int64
+	Callwrapupdata *string
+	Callresult     *int64
+	Dialinglistid  *int64
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package models
+
+// WebexCDR is a record of the Webex Calling Detailed Call History. Every leg
+// of a call is a record, and the legs of a call share the Correlationid.
+// Times are Unix seconds in UTC.
+type WebexCDR struct {
+	ID                     string
+	IngestedFileId         *string `gorm:"index"`
+	Filename               *string
+	Starttime              *int64
+	Answertime             *int64
+	Duration               *int64
+	Reportid               *string
+	Reporttime             *int64
+	Callid                 *string `gorm:"index"`
+	Correlationid          *string `gorm:"index"`
+	Localcallid            *string
+	Remotecallid           *string
+	Networkcallid          *string
+	Relatedcallid          *string
+	Transferrelatedcallid  *string
+	User                   *string
+	Usertype               *string
+	Useruuid               *string
+	Callinglineid          *string
+	Calledlineid           *string
+	Callingnumber          *string
+	Callednumber           *string
+	Dialeddigits           *string
+	Calleridnumber         *string
+	Redirectingnumber      *string
+	Direction              *string
+	Calltype               *string
+	Clienttype             *string
+	Clientversion          *string
+	Subclienttype          *string
+	Devicemac              *string
+	Model                  *string
+	Ostype                 *string
+	Location               *string
+	Locationuuid           *string
+	Siteuuid               *string
+	Orguuid                *string
+	Departmentid           *string
+	Answered               *bool
+	Answerindicator        *string
+	Originalreason         *string
+	Redirectreason         *string
+	Relatedreason          *string
+	Inboundtrunk           *string
+	Outboundtrunk          *string
+	Routegroup             *string
+	Internationalcountry   *string
+	Authorizationcode      *string
+	Calltransfertime       *int64
+	Ringduration           *int64
+	Holdduration           *int64
+	Waittime               *int64
+	Queuetype              *string
+	Releasetime            *int64
+	Releasingparty         *string
+	Releasecause           *string
+	Calloutcome            *string
+	Calloutcomereason      *string
+	Localsessionid         *string
+	Remotesessionid        *string
+	Finallocalsessionid    *string
+	Finalremotesessionid   *string
+	Pstnvendorname         *string
+	Pstnlegalentity        *string
+	Pstnvendororgid        *string
+	Pstnproviderid         *string
+	Publiccallingipaddress *string
+	Publiccalledipaddress  *string
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+)
+
+// tarMagicOffset is where the "ustar" magic sits in a tar header.
+const tarMagicOffset = 257
+
+// memberFunc is called for every CDR file found in an input file. The name is
+// the base name of the member, so the CUCM and CUBE file name metadata can be
+// parsed from it exactly as for a plain file.
+type memberFunc func(name string, reader io.Reader) error
+
+// readMembers calls fn for every CDR file contained in inputFile. A plain file
+// is a single member. Gzip and zstd files are decompressed on the fly, and zip
+// and tar archives (compressed or not) yield one member per regular file.
+// The format is detected from the content, not the extension.
+func readMembers(inputFile string, fn memberFunc) error {
+	file, err := os.Open(inputFile)
+	if err != nil {
+		logger.Error("Error opening file: %s Error: %s", inputFile, err)
+		return err
+	}
+	defer file.Close()
+
+	header := make([]byte, len(zipMagic))
+	n, _ := file.ReadAt(header, 0)
+	if bytes.Equal(header[:n], zipMagic) {
+		return readZip(file, fn)
+	}
+
+	return readStream(filepath.Base(inputFile), file, fn)
+}
+
+func readZip(file *os.File, fn memberFunc) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range archive.File {
+		name := path.Base(entry.Name)
+		if entry.FileInfo().IsDir() || skipMember(name) {
+			continue
+		}
+		reader, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		logger.Info("Reading %s from archive %s", name, file.Name())
+		err = fn(name, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readStream(name string, reader io.Reader, fn memberFunc) error {
+	buffered := bufio.NewReader(reader)
+	header, _ := buffered.Peek(tarMagicOffset + len(tarMagic))
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		decompressed, err := gzip.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer decompressed.Close()
+		return readStream(trimCompressionExtension(name), decompressed, fn)
+
+	case bytes.HasPrefix(header, zstdMagic):
+		decompressed, err := zstd.NewReader(buffered)
+		if err != nil {
+			return err
+		}
+		defer decompressed.Close()
+		return readStream(trimCompressionExtension(name), decompressed, fn)
+
+	case bytes.HasPrefix(header, zipMagic):
+		return errors.New("zip archives inside compressed files are not supported")
+
+	case len(header) > tarMagicOffset && bytes.HasPrefix(header[tarMagicOffset:], tarMagic):
+		return readTar(name, buffered, fn)
+
+	default:
+		return fn(name, buffered)
+	}
+}
+
+func readTar(name string, reader io.Reader, fn memberFunc) error {
+	archive := tar.NewReader(reader)
+	for {
+		entry, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		memberName := path.Base(entry.Name)
+		if entry.Typeflag != tar.TypeReg || skipMember(memberName) {
+			continue
+		}
+		logger.Info("Reading %s from archive %s", memberName, name)
+		if err := fn(memberName, archive); err != nil {
+			return err
+		}
+	}
+}
+
+// trimCompressionExtension returns the name of the decompressed file.
+func trimCompressionExtension(name string) string {
+	switch {
+	case strings.HasSuffix(name, ".tgz"):
+		return strings.TrimSuffix(name, ".tgz") + ".tar"
+	case strings.HasSuffix(name, ".gz"):
+		return strings.TrimSuffix(name, ".gz")
+	case strings.HasSuffix(name, ".zst"):
+		return strings.TrimSuffix(name, ".zst")
+	}
+	return name
+}
+
+// skipMember reports whether an archive member is hidden, such as the "._"
+// resource files macOS adds to archives.
+func skipMember(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+// batchWriter collects parsed records into batches and writes them on a
+// separate goroutine while parsing continues. Only one batch can wait while
+// another is being written, so a slow database slows down parsing instead of
+// letting records pile up in memory.
+type batchWriter[T any] struct {
+	size    int
+	batch   []T
+	queue   chan []T
+	failed  chan struct{}
+	done    chan struct{}
+	written int
+	err     error
+}
+
+func newBatchWriter[T any](size int, write func([]T) error) *batchWriter[T] {
+	if size <= 0 {
+		size = 100
+	}
+
+	b := &batchWriter[T]{
+		size:   size,
+		batch:  make([]T, 0, size),
+		queue:  make(chan []T, 1),
+		failed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(b.done)
+		for batch := range b.queue {
+			if b.err != nil {
+				// Drain the queue so Add and Close never block
+				continue
+			}
+			if err := write(batch); err != nil {
+				b.err = err
+				close(b.failed)
+				continue
+			}
+			b.written += len(batch)
+		}
+	}()
+
+	return b
+}
+
+// Add appends a record, handing the batch to the writer once it is full. It
+// returns an error as soon as a previous batch failed to write.
+func (b *batchWriter[T]) Add(record T) error {
+	b.batch = append(b.batch, record)
+	if len(b.batch) < b.size {
+		return nil
+	}
+	return b.flush()
+}
+
+func (b *batchWriter[T]) flush() error {
+	if len(b.batch) == 0 {
+		return nil
+	}
+	select {
+	case b.queue <- b.batch:
+		b.batch = make([]T, 0, b.size)
+		return nil
+	case <-b.failed:
+		return b.err
+	}
+}
+
+// Close writes the remaining records and waits for the writer. It returns the
+// number of records written and the first write error.
+func (b *batchWriter[T]) Close() (int, error) {
+	b.flush()
+	close(b.queue)
+	<-b.done
+	return b.written, b.err
+}
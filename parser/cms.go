@@ -0,0 +1,237 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// CMSRecords is the body of a CDR post of Cisco Meeting Server.
+type CMSRecords struct {
+	XMLName xml.Name `xml:"records"`
+	// Session changes when the CDR session of the node restarts
+	Session string      `xml:"session,attr"`
+	Batch   string      `xml:"batch,attr"`
+	Records []CMSRecord `xml:"record"`
+}
+
+// CMSRecord is a single record. Call records carry a call element, call leg
+// records a callLeg element.
+type CMSRecord struct {
+	Type    string             `xml:"type,attr"`
+	Time    string             `xml:"time,attr"`
+	Call    *CMSCallElement    `xml:"call"`
+	CallLeg *CMSCallLegElement `xml:"callLeg"`
+}
+
+type CMSCallElement struct {
+	ID                string  `xml:"id,attr"`
+	Name              *string `xml:"name"`
+	OwnerName         *string `xml:"ownerName"`
+	CoSpace           *string `xml:"coSpace"`
+	CallCorrelator    *string `xml:"callCorrelator"`
+	CallType          *string `xml:"callType"`
+	CdrTag            *string `xml:"cdrTag"`
+	DurationSeconds   *int64  `xml:"durationSeconds"`
+	CallLegsCompleted *int64  `xml:"callLegsCompleted"`
+	CallLegsMaxActive *int64  `xml:"callLegsMaxActive"`
+}
+
+type CMSCallLegElement struct {
+	ID                string            `xml:"id,attr"`
+	Call              *string           `xml:"call"`
+	DisplayName       *string           `xml:"displayName"`
+	LocalAddress      *string           `xml:"localAddress"`
+	RemoteAddress     *string           `xml:"remoteAddress"`
+	RemoteParty       *string           `xml:"remoteParty"`
+	Type              *string           `xml:"type"`
+	SubType           *string           `xml:"subType"`
+	Direction         *string           `xml:"direction"`
+	SipCallId         *string           `xml:"sipCallId"`
+	GroupId           *string           `xml:"groupId"`
+	OwnerId           *string           `xml:"ownerId"`
+	CdrTag            *string           `xml:"cdrTag"`
+	GuestConnection   *bool             `xml:"guestConnection"`
+	Recording         *bool             `xml:"recording"`
+	Streaming         *bool             `xml:"streaming"`
+	State             *string           `xml:"state"`
+	Reason            *string           `xml:"reason"`
+	RemoteTeardown    *bool             `xml:"remoteTeardown"`
+	DurationSeconds   *int64            `xml:"durationSeconds"`
+	ActivatedDuration *int64            `xml:"activatedDuration"`
+	EncryptedMedia    *bool             `xml:"encryptedMedia"`
+	UnencryptedMedia  *bool             `xml:"unencryptedMedia"`
+	RxAudio           *cmsMediaElement  `xml:"rxAudio"`
+	TxAudio           *cmsMediaElement  `xml:"txAudio"`
+	RxVideo           *cmsMediaElement  `xml:"rxVideo"`
+	TxVideo           *cmsMediaElement  `xml:"txVideo"`
+	Alarms            []cmsAlarmElement `xml:"alarm"`
+}
+
+type cmsMediaElement struct {
+	Codec         *string `xml:"codec"`
+	MaxSizeWidth  *int64  `xml:"maxSizeWidth"`
+	MaxSizeHeight *int64  `xml:"maxSizeHeight"`
+}
+
+type cmsAlarmElement struct {
+	Type               string `xml:"type,attr"`
+	DurationPercentage string `xml:"durationPercentage,attr"`
+}
+
+// ParseCMSRecords decodes the body of a CDR post.
+func ParseCMSRecords(reader io.Reader) (*CMSRecords, error) {
+	var records CMSRecords
+	if err := xml.NewDecoder(reader).Decode(&records); err != nil {
+		return nil, err
+	}
+	for _, record := range records.Records {
+		if record.Call != nil && record.Call.ID == "" || record.CallLeg != nil && record.CallLeg.ID == "" {
+			return nil, fmt.Errorf("%s record without an id", record.Type)
+		}
+	}
+	return &records, nil
+}
+
+// Timestamp returns the time of the record as Unix seconds.
+func (r *CMSRecord) Timestamp() *int64 {
+	t, err := time.Parse(time.RFC3339Nano, r.Time)
+	if err != nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+// ApplyCall adds a callStart or callEnd record to its call.
+func (r *CMSRecord) ApplyCall(call *models.CMSCall, session string) {
+	element := r.Call
+	call.ID = element.ID
+	call.CdrSession = &session
+
+	switch r.Type {
+	case models.CMSCallStart:
+		call.StartTime = r.Timestamp()
+	case models.CMSCallEnd:
+		call.EndTime = r.Timestamp()
+	}
+
+	setString(&call.Name, element.Name)
+	setString(&call.OwnerName, element.OwnerName)
+	setString(&call.CoSpace, element.CoSpace)
+	setString(&call.CallCorrelator, element.CallCorrelator)
+	setString(&call.CallType, element.CallType)
+	setString(&call.CdrTag, element.CdrTag)
+	setValue(&call.DurationSeconds, element.DurationSeconds)
+	setValue(&call.CallLegsCompleted, element.CallLegsCompleted)
+	setValue(&call.CallLegsMaxActive, element.CallLegsMaxActive)
+}
+
+// ApplyCallLeg adds a callLegStart, callLegUpdate or callLegEnd record to its
+// call leg. Only the elements present in the record are changed.
+func (r *CMSRecord) ApplyCallLeg(leg *models.CMSCallLeg, session string) {
+	element := r.CallLeg
+	leg.ID = element.ID
+	leg.CdrSession = &session
+
+	switch r.Type {
+	case models.CMSCallLegStart:
+		leg.StartTime = r.Timestamp()
+	case models.CMSCallLegUpdate:
+		if leg.ConnectTime == nil && element.State != nil && *element.State == "connected" {
+			leg.ConnectTime = r.Timestamp()
+		}
+	case models.CMSCallLegEnd:
+		leg.EndTime = r.Timestamp()
+	}
+
+	setString(&leg.CallId, element.Call)
+	setString(&leg.DisplayName, element.DisplayName)
+	setString(&leg.LocalAddress, element.LocalAddress)
+	setString(&leg.RemoteAddress, element.RemoteAddress)
+	setString(&leg.RemoteParty, element.RemoteParty)
+	setString(&leg.Type, element.Type)
+	setString(&leg.SubType, element.SubType)
+	setString(&leg.Direction, element.Direction)
+	setString(&leg.SipCallId, element.SipCallId)
+	setString(&leg.GroupId, element.GroupId)
+	setString(&leg.OwnerId, element.OwnerId)
+	setString(&leg.CdrTag, element.CdrTag)
+	setValue(&leg.GuestConnection, element.GuestConnection)
+	setValue(&leg.Recording, element.Recording)
+	setValue(&leg.Streaming, element.Streaming)
+	setString(&leg.State, element.State)
+	setString(&leg.Reason, element.Reason)
+	setValue(&leg.RemoteTeardown, element.RemoteTeardown)
+	setValue(&leg.DurationSeconds, element.DurationSeconds)
+	setValue(&leg.ActivatedDuration, element.ActivatedDuration)
+	setValue(&leg.EncryptedMedia, element.EncryptedMedia)
+	setValue(&leg.UnencryptedMedia, element.UnencryptedMedia)
+
+	if media := element.RxAudio; media != nil {
+		setString(&leg.RxAudioCodec, media.Codec)
+	}
+	if media := element.TxAudio; media != nil {
+		setString(&leg.TxAudioCodec, media.Codec)
+	}
+	if media := element.RxVideo; media != nil {
+		setString(&leg.RxVideoCodec, media.Codec)
+		setValue(&leg.RxVideoMaxWidth, media.MaxSizeWidth)
+		setValue(&leg.RxVideoMaxHeight, media.MaxSizeHeight)
+	}
+	if media := element.TxVideo; media != nil {
+		setString(&leg.TxVideoCodec, media.Codec)
+		setValue(&leg.TxVideoMaxWidth, media.MaxSizeWidth)
+		setValue(&leg.TxVideoMaxHeight, media.MaxSizeHeight)
+	}
+
+	if len(element.Alarms) > 0 {
+		alarms := make([]string, 0, len(element.Alarms))
+		for _, alarm := range element.Alarms {
+			percentage, err := strconv.ParseFloat(alarm.DurationPercentage, 64)
+			if err != nil {
+				alarms = append(alarms, alarm.Type)
+				continue
+			}
+			alarms = append(alarms, alarm.Type+":"+strconv.FormatFloat(percentage, 'f', -1, 64))
+		}
+		joined := strings.Join(alarms, ",")
+		leg.Alarms = &joined
+	}
+}
+
+// setString sets a field to a value that is present in a record. Empty
+// elements are kept as empty strings, they clear the field.
+func setString(field **string, value *string) {
+	if value == nil {
+		return
+	}
+	trimmed := strings.TrimSpace(*value)
+	*field = &trimmed
+}
+
+func setValue[T any](field **T, value *T) {
+	if value != nil {
+		*field = value
+	}
+}
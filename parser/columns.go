@@ -0,0 +1,117 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// column is a named column of a CSV file and the field of the raw record it
+// is read into.
+type column[T any] struct {
+	name string
+	set  func(raw *T, value *string)
+}
+
+// columnMap holds the setter of every column of a file in file order. Columns
+// that are not known have no setter and are not read.
+type columnMap[T any] []func(raw *T, value *string)
+
+// newColumnMap maps the header of a file onto the known columns by name,
+// ignoring case, spaces, underscores and dashes. Unknown columns are logged and skipped, and known columns
+// missing from the file are left nil in every record.
+func newColumnMap[T any](columns []column[T], header []string, fileName string) columnMap[T] {
+	known := make(map[string]func(raw *T, value *string), len(columns))
+	for _, c := range columns {
+		known[columnKey(c.name)] = c.set
+	}
+
+	mapped := make(columnMap[T], len(header))
+	found := make(map[string]bool, len(header))
+	var unknown []string
+	for i, name := range header {
+		key := columnKey(name)
+		set, ok := known[key]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		mapped[i] = set
+		found[key] = true
+	}
+
+	if len(unknown) > 0 {
+		logger.Warn("Ignoring unknown columns in file: %s Columns: %s", fileName, strings.Join(unknown, ", "))
+	}
+	for _, c := range columns {
+		if !found[columnKey(c.name)] {
+			logger.Debug("Column %s is missing in file: %s", c.name, fileName)
+		}
+	}
+
+	return mapped
+}
+
+// positionalColumnMap maps the columns in their default order, for files
+// without a header.
+func positionalColumnMap[T any](columns []column[T]) columnMap[T] {
+	mapped := make(columnMap[T], len(columns))
+	for i, c := range columns {
+		mapped[i] = c.set
+	}
+	return mapped
+}
+
+// isHeader reports whether a row is the header of a file whose first column
+// is firstColumn.
+func isHeader(record []string, firstColumn string) bool {
+	return len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), firstColumn)
+}
+
+// fill sets the fields of raw from the columns of a record.
+func (m columnMap[T]) fill(raw *T, record []string) {
+	for i := range record {
+		if i < len(m) && m[i] != nil {
+			m[i](raw, &record[i])
+		}
+	}
+}
+
+// containsColumn reports whether a header has a column, compared like in
+// newColumnMap.
+func containsColumn(header []string, name string) bool {
+	for _, column := range header {
+		if columnKey(column) == columnKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKey returns the name a column is matched by, so "Serial number",
+// "serial_number" and "SerialNumber" are the same column.
+func columnKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+}
@@ -16,61 +16,107 @@
 package parser
 
 import (
+	"io"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/eds-ch/Go-CDR-V/database"
-	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
 )
 
-func ParseCUBECDRs(inputFile string, db *database.DataService, outputDirectory string, deleteOriginal bool) {
+func ParseCUBECDRs(inputFile string, db *database.DataService, ingestion *Ingestion) FileResult {
 
 	baseFileName := filepath.Base(inputFile)
 
-	logger.Info("Found CDR file: %s", baseFileName)
-	cdrs, err := ParseCubeCDRFile(inputFile)
-	if err != nil {
-		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-		helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
-	}
+	result := FileResult{File: inputFile}
 
-	if len(cdrs) > 0 && err == nil {
-
-		err := db.CreateCubeCDRs(cdrs)
-		if err != nil {
-			logger.Error("Error while writing to database: %s", err.Error())
-			err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to failed directory: %s", inputFile)
+	logger.Info("Found CDR file: %s", baseFileName)
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		batches := newBatchWriter(db.BatchSize(), func(cdrs []*models.CubeCDR) error {
+			for _, cdr := range cdrs {
+				cdr.IngestedFileId = &ingestion.ID
 			}
-		} else {
-			logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(len(cdrs)), inputFile)
-			err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to completed directory: %s", inputFile)
+			return db.CreateCubeCDRs(cdrs)
+		})
+		rejects := newRejectWriter(db, ingestion)
+		calls := make(map[models.CubeCallKey]bool)
+		rejected, err := ParseCubeCDRFile(reader, name, func(cdr *models.CubeCDR) error {
+			// Calls are paired even if the records were written by an
+			// earlier attempt
+			if key, ok := endedCubeCall(cdr); ok {
+				calls[key] = true
+			}
+			if ingestion.AlreadyWritten() {
+				return nil
 			}
+			return batches.Add(cdr)
+		}, rejects.Add)
+		written, writeErr := batches.Close()
+		_, rejectErr := rejects.Close()
+		result.Rejected += rejected
+		result.Records += written
+		if writeErr != nil {
+			logger.Error("Error while writing to database: %s", writeErr.Error())
+			return writeErr
 		}
-	} else if len(cdrs) == 0 && err == nil {
-		logger.Info("No CDRs found in file: %s", inputFile)
-		err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-		if err != nil {
-			logger.Error("Error while moving file: %s", err.Error())
-		} else {
-			logger.Info("Successfully moved file to completed directory: %s", inputFile)
+		if rejectErr != nil {
+			logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+			return rejectErr
 		}
-	} else {
-		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-		err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
 		if err != nil {
-			logger.Error("Error while moving file: %s", err.Error())
+			return err
+		}
+
+		if written == 0 {
+			logger.Info("No CDRs found in file: %s", name)
 		} else {
-			logger.Info("Successfully moved file to failed directory: %s", inputFile)
+			logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(written), name)
 		}
+
+		// The CDRs are safely written, a failed pairing is repaired by the
+		// next record of the call or by the rebuild command
+		keys := make([]models.CubeCallKey, 0, len(calls))
+		for key := range calls {
+			keys = append(keys, key)
+		}
+		if err := PairCubeCalls(db, keys); err != nil {
+			logger.Error("Error while pairing CUBE calls from %s: %s", name, err.Error())
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Err = err
+	}
+
+	return result
+}
+
+// cubeFilename is the metadata CUBE encodes in the names of gw-accounting
+// files, <prefix>.<hostname>.<date>.<time>.
+type cubeFilename struct {
+	Filename  *string
+	Hostname  *string
+	Timestamp *string
+}
+
+func parseCubeFilename(fileName string) cubeFilename {
+	var parsed cubeFilename
+
+	parts := strings.Split(fileName, ".")
+	if parts[0] != "" {
+		parsed.Filename = &parts[0]
+	}
+	if len(parts) < 4 {
+		logger.Error("Unexpected CUBE file name: %s", fileName)
+		return parsed
 	}
 
+	timestamp := strings.ReplaceAll(parts[2]+"."+parts[3], "_", " ")
+	parsed.Hostname = &parts[1]
+	parsed.Timestamp = &timestamp
+	return parsed
 }
@@ -0,0 +1,290 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// cubeCallMutex serializes pairing, the files and receivers can write legs of
+// the same call at the same time.
+var cubeCallMutex sync.Mutex
+
+// CubeCallKeys returns the keys of the calls of CDRs that have ended.
+func CubeCallKeys(cdrs []*models.CubeCDR) []models.CubeCallKey {
+	seen := make(map[models.CubeCallKey]bool)
+	var keys []models.CubeCallKey
+	for _, cdr := range cdrs {
+		if key, ok := endedCubeCall(cdr); ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// endedCubeCall returns the key of the call of a CDR, and false for start
+// records, whose call is paired when the stop record arrives.
+func endedCubeCall(cdr *models.CubeCDR) (models.CubeCallKey, bool) {
+	key, ok := cdr.CallKey()
+	return key, ok && int64Value(cdr.H323DisconnectTime) > 0
+}
+
+// PairCubeCalls rebuilds the cube_calls of the given keys from every CDR of
+// the calls in the database.
+func PairCubeCalls(db *database.DataService, keys []models.CubeCallKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cubeCallMutex.Lock()
+	defer cubeCallMutex.Unlock()
+
+	wanted := make(map[models.CubeCallKey]bool, len(keys))
+	var confIDs []string
+	var ids []string
+	for _, key := range keys {
+		if !wanted[key] {
+			wanted[key] = true
+			confIDs = append(confIDs, key.H323ConfId)
+			ids = append(ids, key.ID())
+		}
+	}
+
+	cdrs, err := db.GetCubeCDRsByConfID(confIDs)
+	if err != nil {
+		return err
+	}
+	legs := make(map[models.CubeCallKey][]*models.CubeCDR)
+	for _, cdr := range cdrs {
+		if key, ok := cdr.CallKey(); ok && wanted[key] {
+			legs[key] = append(legs[key], cdr)
+		}
+	}
+
+	calls := make([]*models.CubeCall, 0, len(legs))
+	for _, key := range keys {
+		if group, ok := legs[key]; ok {
+			calls = append(calls, buildCubeCall(key, group))
+			delete(legs, key)
+		}
+	}
+
+	if err := db.ReplaceCubeCalls(ids, calls); err != nil {
+		return err
+	}
+	logger.Info("Paired %d CUBE calls from %d CDRs", len(calls), len(cdrs))
+
+	return CorrelateCubeCalls(db, calls)
+}
+
+// cubeCallLegs keeps the last record of every leg of a call, the stop record
+// if there is one.
+func cubeCallLegs(cdrs []*models.CubeCDR) []*models.CubeCDR {
+	byLeg := make(map[int64]*models.CubeCDR)
+	var order []int64
+	for _, cdr := range cdrs {
+		leg := int64Value(cdr.CallId)
+		other, ok := byLeg[leg]
+		if !ok {
+			order = append(order, leg)
+		}
+		if !ok || cubeRecordNewer(cdr, other) {
+			byLeg[leg] = cdr
+		}
+	}
+
+	legs := make([]*models.CubeCDR, 0, len(order))
+	for _, leg := range order {
+		legs = append(legs, byLeg[leg])
+	}
+	sort.SliceStable(legs, func(i, j int) bool {
+		if int64Value(legs[i].H323SetupTime) != int64Value(legs[j].H323SetupTime) {
+			return int64Value(legs[i].H323SetupTime) < int64Value(legs[j].H323SetupTime)
+		}
+		return int64Value(legs[i].CallId) < int64Value(legs[j].CallId)
+	})
+	return legs
+}
+
+func cubeRecordNewer(cdr *models.CubeCDR, other *models.CubeCDR) bool {
+	ended, otherEnded := int64Value(cdr.H323DisconnectTime) > 0, int64Value(other.H323DisconnectTime) > 0
+	if ended != otherEnded {
+		return ended
+	}
+	return int64Value(cdr.RecordTimestamp) > int64Value(other.RecordTimestamp)
+}
+
+// cubeCallSides picks the ingress and egress leg of a call. The ingress leg
+// is the first leg the gateway answered, and the egress leg the leg it
+// originated for it: the one whose backward call ID points back to the
+// ingress leg, else the one that connected, else the last one. Without the
+// call origin the first leg is the ingress leg.
+func cubeCallSides(legs []*models.CubeCDR) (*models.CubeCDR, *models.CubeCDR) {
+	var ingress *models.CubeCDR
+	var originated []*models.CubeCDR
+	for _, leg := range legs {
+		switch strings.ToLower(stringValue(leg.H323CallOrigin)) {
+		case "answer":
+			if ingress == nil {
+				ingress = leg
+			}
+		case "originate":
+			originated = append(originated, leg)
+		}
+	}
+	if ingress == nil && len(originated) == 0 {
+		ingress, originated = legs[0], legs[1:]
+	}
+	if len(originated) == 0 {
+		return ingress, nil
+	}
+
+	egress := originated[len(originated)-1]
+	for _, leg := range originated {
+		if int64Value(leg.H323ConnectTime) > 0 {
+			egress = leg
+			break
+		}
+	}
+	if ingress != nil {
+		ingressID := strconv.FormatInt(int64Value(ingress.CallId), 10)
+		for _, leg := range originated {
+			if stringValue(leg.BackwardCallId) == ingressID {
+				egress = leg
+				break
+			}
+		}
+	}
+	return ingress, egress
+}
+
+// buildCubeCall summarizes the legs of a call. Values of a side fall back to
+// the other side when the preferred leg does not have them.
+func buildCubeCall(key models.CubeCallKey, cdrs []*models.CubeCDR) *models.CubeCall {
+	legs := cubeCallLegs(cdrs)
+	ingress, egress := cubeCallSides(legs)
+
+	call := &models.CubeCall{
+		ID:         key.ID(),
+		Hostname:   legs[0].Hostname,
+		H323ConfId: legs[0].H323ConfId,
+		Legs:       int64(len(legs)),
+		UpdatedAt:  time.Now().Unix(),
+	}
+
+	// first is the ingress leg, else the egress leg, and last the reverse
+	first, last := ingress, egress
+	if first == nil {
+		first = egress
+	}
+	if last == nil {
+		last = ingress
+	}
+
+	if ingress != nil {
+		call.IngressCdrId = &ingress.ID
+		call.IngressCallId = ingress.CallId
+		call.IngressLegType = ingress.LegType
+		call.IngressPeerId = ingress.PeerId
+		call.IngressTrunkgroup = firstString(ingress.InTrunkgroupLabel, ingress.OutTrunkgroupLabel)
+		call.IngressPeerAddress = ingress.PeerAddress
+		call.IngressCodec = ingress.CodecTypeRate
+		call.IngressPaksIn = ingress.PaksIn
+		call.IngressPaksOut = ingress.PaksOut
+		call.IngressLostPackets = ingress.LostPackets
+		call.IngressLatePackets = ingress.LatePackets
+		call.IngressEarlyPackets = ingress.EarlyPackets
+		call.IngressRoundTripDelay = ingress.RoundTripDelay
+		call.IngressRemoteMediaAddress = ingress.RemoteMediaAddress
+	}
+	if egress != nil {
+		call.EgressCdrId = &egress.ID
+		call.EgressCallId = egress.CallId
+		call.EgressLegType = egress.LegType
+		call.EgressPeerId = egress.PeerId
+		call.EgressTrunkgroup = firstString(egress.OutTrunkgroupLabel, egress.InTrunkgroupLabel)
+		call.EgressPeerAddress = egress.PeerAddress
+		call.EgressCodec = egress.CodecTypeRate
+		call.EgressPaksIn = egress.PaksIn
+		call.EgressPaksOut = egress.PaksOut
+		call.EgressLostPackets = egress.LostPackets
+		call.EgressLatePackets = egress.LatePackets
+		call.EgressEarlyPackets = egress.EarlyPackets
+		call.EgressRoundTripDelay = egress.RoundTripDelay
+		call.EgressRemoteMediaAddress = egress.RemoteMediaAddress
+	}
+
+	call.GwRxdCgn = firstString(first.GwRxdCgn, last.GwRxdCgn)
+	call.GwRxdCdn = firstString(first.GwRxdCdn, last.GwRxdCdn)
+	call.GwRxdRdn = firstString(first.GwRxdRdn, last.GwRxdRdn)
+	call.GwCollectedCdn = firstString(first.GwCollectedCdn, last.GwCollectedCdn)
+	call.GkXlatedCgn = firstString(last.GkXlatedCgn, first.GkXlatedCgn)
+	call.GkXlatedCdn = firstString(last.GkXlatedCdn, first.GkXlatedCdn)
+	call.GwFinalXlatedCgn = firstString(last.GwFinalXlatedCgn, first.GwFinalXlatedCgn)
+	call.GwFinalXlatedCdn = firstString(last.GwFinalXlatedCdn, first.GwFinalXlatedCdn)
+	call.GwFinalXlatedRdn = firstString(last.GwFinalXlatedRdn, first.GwFinalXlatedRdn)
+
+	call.SetupTime = firstTime(first.H323SetupTime, last.H323SetupTime)
+	call.AlertTime = firstTime(last.AlertTime, first.AlertTime)
+	call.ConnectTime = firstTime(first.H323ConnectTime, last.H323ConnectTime)
+	call.DisconnectCause = firstString(first.H323DisconnectCause, last.H323DisconnectCause)
+	call.DisconnectText = firstString(first.DisconnectText, last.DisconnectText)
+	for _, leg := range legs {
+		if int64Value(leg.H323DisconnectTime) > int64Value(call.DisconnectTime) {
+			call.DisconnectTime = leg.H323DisconnectTime
+		}
+	}
+	if call.ConnectTime != nil && call.DisconnectTime != nil && *call.DisconnectTime >= *call.ConnectTime {
+		duration := *call.DisconnectTime - *call.ConnectTime
+		call.Duration = &duration
+	}
+
+	return call
+}
+
+func firstString(values ...*string) *string {
+	for _, value := range values {
+		if value != nil && *value != "" {
+			return value
+		}
+	}
+	return nil
+}
+
+func firstTime(values ...*int64) *int64 {
+	for _, value := range values {
+		if value != nil && *value > 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// cubeTestCDR returns a record of a CUBE call leg. Zero times are left out.
+func cubeTestCDR(id string, callID int64, setup int64, disconnect int64, recorded int64) *models.CubeCDR {
+	cdr := &models.CubeCDR{ID: id, CallId: testPtr(callID), RecordTimestamp: testPtr(recorded)}
+	if setup != 0 {
+		cdr.H323SetupTime = testPtr(setup)
+	}
+	if disconnect != 0 {
+		cdr.H323DisconnectTime = testPtr(disconnect)
+	}
+	return cdr
+}
+
+func TestCubeCallLegs(t *testing.T) {
+	tests := []struct {
+		name string
+		cdrs []*models.CubeCDR
+		want []string
+	}{
+		{
+			name: "stop record wins over a later start record",
+			cdrs: []*models.CubeCDR{cubeTestCDR("stop", 1, 1000, 1060, 1060), cubeTestCDR("start", 1, 1000, 0, 1070)},
+			want: []string{"stop"},
+		},
+		{
+			name: "latest of two start records",
+			cdrs: []*models.CubeCDR{cubeTestCDR("first", 1, 1000, 0, 1000), cubeTestCDR("second", 1, 1000, 0, 1010)},
+			want: []string{"second"},
+		},
+		{
+			name: "legs in setup order",
+			cdrs: []*models.CubeCDR{
+				cubeTestCDR("egress", 2, 1001, 1060, 1060),
+				cubeTestCDR("ingress", 1, 1000, 1060, 1060),
+			},
+			want: []string{"ingress", "egress"},
+		},
+		{
+			name: "same setup time in call id order",
+			cdrs: []*models.CubeCDR{
+				cubeTestCDR("b", 8, 1000, 1060, 1060),
+				cubeTestCDR("a", 7, 1000, 1060, 1060),
+			},
+			want: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legs := cubeCallLegs(tt.cdrs)
+			if len(legs) != len(tt.want) {
+				t.Fatalf("got %d legs, want %d", len(legs), len(tt.want))
+			}
+			for i, leg := range legs {
+				if leg.ID != tt.want[i] {
+					t.Errorf("leg %d is %s, want %s", i, leg.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCubeCallSides(t *testing.T) {
+	leg := func(id string, callID int64, origin string, connected bool, backwardCallID string) *models.CubeCDR {
+		cdr := cubeTestCDR(id, callID, 1000, 1060, 1060)
+		cdr.H323CallOrigin = testPtr(origin)
+		if connected {
+			cdr.H323ConnectTime = testPtr(int64(1010))
+		}
+		if backwardCallID != "" {
+			cdr.BackwardCallId = testPtr(backwardCallID)
+		}
+		return cdr
+	}
+
+	tests := []struct {
+		name    string
+		legs    []*models.CubeCDR
+		ingress string
+		egress  string
+	}{
+		{
+			name:    "answer and originate",
+			legs:    []*models.CubeCDR{leg("in", 1, "answer", true, ""), leg("out", 2, "originate", true, "")},
+			ingress: "in",
+			egress:  "out",
+		},
+		{
+			name: "connected leg after a failed attempt",
+			legs: []*models.CubeCDR{
+				leg("in", 1, "answer", true, ""),
+				leg("failed", 2, "originate", false, ""),
+				leg("out", 3, "originate", true, ""),
+				leg("later", 4, "originate", false, ""),
+			},
+			ingress: "in",
+			egress:  "out",
+		},
+		{
+			name: "backward call id",
+			legs: []*models.CubeCDR{
+				leg("in", 1, "answer", true, ""),
+				leg("other", 2, "originate", true, ""),
+				leg("out", 3, "originate", false, "1"),
+			},
+			ingress: "in",
+			egress:  "out",
+		},
+		{
+			name:    "without call origin",
+			legs:    []*models.CubeCDR{leg("first", 1, "", true, ""), leg("second", 2, "", true, "")},
+			ingress: "first",
+			egress:  "second",
+		},
+		{
+			name:    "single leg",
+			legs:    []*models.CubeCDR{leg("in", 1, "answer", false, "")},
+			ingress: "in",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingress, egress := cubeCallSides(tt.legs)
+			if id := cubeTestID(ingress); id != tt.ingress {
+				t.Errorf("ingress leg is %q, want %q", id, tt.ingress)
+			}
+			if id := cubeTestID(egress); id != tt.egress {
+				t.Errorf("egress leg is %q, want %q", id, tt.egress)
+			}
+		})
+	}
+}
+
+func cubeTestID(cdr *models.CubeCDR) string {
+	if cdr == nil {
+		return ""
+	}
+	return cdr.ID
+}
@@ -17,60 +17,44 @@
 package parser
 
 import (
-	"encoding/csv"
+	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
 
 	"github.com/eds-ch/Go-CDR-V/logger"
 	"github.com/eds-ch/Go-CDR-V/models"
 )
 
-func ParseCubeCDRFile(inputFile string) ([]*models.CubeCDR, error) {
+func ParseCubeCDRFile(reader io.Reader, fileName string, add func(*models.CubeCDR) error, reject func(*models.RejectedRecord) error) (int, error) {
 
-	logger.Info("Parsing file: %s", inputFile)
+	logger.Info("Parsing file: %s", fileName)
 
-	readFile, err := os.OpenFile(inputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
-	if err != nil {
-		logger.Error("Error opening file: %s Error: %s", inputFile, err)
-	}
-	defer readFile.Close()
-
-	var ParsedFilename *string
-	baseFileName := filepath.Base(inputFile)
+	filename := parseCubeFilename(fileName)
 
-	Filename := strings.Split(baseFileName, ".")[0]
-	if Filename == "" {
-		ParsedFilename = nil
-	} else {
-		ParsedFilename = &Filename
+	if filename.Hostname != nil {
+		logger.Info("Parsing Gateway %s CDR file", *filename.Hostname)
 	}
-	Hostname := strings.Split(baseFileName, ".")[1]
-	FileTimestamp := strings.ReplaceAll(strings.Split(baseFileName, ".")[2]+"."+strings.Split(baseFileName, ".")[3], "_", " ")
 
-	logger.Info("Parsing Gateway %s CDR file", Hostname)
+	rejected := 0
 
-	rawcdrs := []*models.RawCubeCDR{}
-	parsedCDRs := []*models.CubeCDR{}
-
-	reader := csv.NewReader(readFile)
+	csvReader := newRowReader(reader, fileName, "cube_cdr")
 	for {
-		record, err := reader.Read()
+		record, err := csvReader.Read()
 		if err == io.EOF {
-			logger.Info("Finished parsing file: %s", inputFile)
+			logger.Info("Finished parsing file: %s", fileName)
 			break
 		}
 		if err != nil {
-			if perr, ok := err.(*csv.ParseError); ok && perr.Err == csv.ErrFieldCount {
-				continue
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
 			}
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+			continue
 		}
 
-		if len(record) >= 129 {
-			rawcdrs = append(rawcdrs, &models.RawCubeCDR{
+		if len(record) >= 130 {
+			raw := &models.RawCubeCDR{
 				RecordTimestamp:          &record[0],
 				CallId:                   &record[1],
 				CdrType:                  &record[2],
@@ -201,22 +185,30 @@ func ParseCubeCDRFile(inputFile string) ([]*models.CubeCDR, error) {
 				OutLpcorGroup:            &record[127],
 				FacDigit:                 &record[128],
 				FacStatus:                &record[129],
-				Hostname:                 &Hostname,
-				Filename:                 ParsedFilename,
-				FileTimestamp:            &FileTimestamp,
-			})
-		} else if len(record) != 1 {
-			logger.Error("Error parsing CDR: %s Found %s fields instead of 129", inputFile, strconv.Itoa(len(record)))
-		}
-	}
+				Hostname:                 filename.Hostname,
+				Filename:                 filename.Filename,
+				FileTimestamp:            filename.Timestamp,
+			}
 
-	for _, cdr := range rawcdrs {
-		pCDR, err := cdr.Parse(inputFile)
-		if err == nil {
-			parsedCDRs = append(parsedCDRs, pCDR)
-			continue
+			cdr, err := raw.Parse(fileName)
+			if err != nil {
+				rejected++
+				if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+					return rejected, err
+				}
+				continue
+			}
+			if err := add(cdr); err != nil {
+				return rejected, err
+			}
+		} else if len(record) != 1 {
+			logger.Error("Error parsing CDR: %s Found %s fields instead of 130", fileName, strconv.Itoa(len(record)))
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectFieldCount, fmt.Errorf("found %d fields", len(record)))); err != nil {
+				return rejected, err
+			}
 		}
 	}
 
-	return parsedCDRs, nil
+	return rejected, nil
 }
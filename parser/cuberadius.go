@@ -0,0 +1,202 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// Attribute names the RADIUS receiver gives the standard accounting
+// attributes, next to the names of the Cisco AVPairs.
+const (
+	AttributeCallId          = "call-id"
+	AttributeCdrType         = "cdr-type"
+	AttributeRecordTimestamp = "record-timestamp"
+	AttributeFeatureVsa      = "feature-vsa"
+)
+
+// cubeAttributeColumns are the fields of a CUBE CDR by the name of the Cisco
+// AVPair that carries them in RADIUS accounting records, the same names the
+// gw-accounting file format uses.
+var cubeAttributeColumns = []column[models.RawCubeCDR]{
+	{AttributeRecordTimestamp, func(raw *models.RawCubeCDR, value *string) { raw.RecordTimestamp = value }},
+	{AttributeCallId, func(raw *models.RawCubeCDR, value *string) { raw.CallId = value }},
+	{AttributeCdrType, func(raw *models.RawCubeCDR, value *string) { raw.CdrType = value }},
+	{"leg-type", func(raw *models.RawCubeCDR, value *string) { raw.LegType = value }},
+	{"h323-conf-id", func(raw *models.RawCubeCDR, value *string) { raw.H323ConfId = value }},
+	{"peer-address", func(raw *models.RawCubeCDR, value *string) { raw.PeerAddress = value }},
+	{"peer-sub-address", func(raw *models.RawCubeCDR, value *string) { raw.PeerSubAddress = value }},
+	{"h323-setup-time", func(raw *models.RawCubeCDR, value *string) { raw.H323SetupTime = value }},
+	{"alert-time", func(raw *models.RawCubeCDR, value *string) { raw.AlertTime = value }},
+	{"h323-connect-time", func(raw *models.RawCubeCDR, value *string) { raw.H323ConnectTime = value }},
+	{"h323-disconnect-time", func(raw *models.RawCubeCDR, value *string) { raw.H323DisconnectTime = value }},
+	{"h323-disconnect-cause", func(raw *models.RawCubeCDR, value *string) { raw.H323DisconnectCause = value }},
+	{"disconnect-text", func(raw *models.RawCubeCDR, value *string) { raw.DisconnectText = value }},
+	{"h323-call-origin", func(raw *models.RawCubeCDR, value *string) { raw.H323CallOrigin = value }},
+	{"charged-units", func(raw *models.RawCubeCDR, value *string) { raw.ChargedUnits = value }},
+	{"info-type", func(raw *models.RawCubeCDR, value *string) { raw.InfoType = value }},
+	{"paks-out", func(raw *models.RawCubeCDR, value *string) { raw.PaksOut = value }},
+	{"bytes-out", func(raw *models.RawCubeCDR, value *string) { raw.BytesOut = value }},
+	{"paks-in", func(raw *models.RawCubeCDR, value *string) { raw.PaksIn = value }},
+	{"bytes-in", func(raw *models.RawCubeCDR, value *string) { raw.BytesIn = value }},
+	{"username", func(raw *models.RawCubeCDR, value *string) { raw.Username = value }},
+	{"clid", func(raw *models.RawCubeCDR, value *string) { raw.Clid = value }},
+	{"dnis", func(raw *models.RawCubeCDR, value *string) { raw.Dnis = value }},
+	{"gtd-orig-cic", func(raw *models.RawCubeCDR, value *string) { raw.GtdOrigCic = value }},
+	{"gtd-term-cic", func(raw *models.RawCubeCDR, value *string) { raw.GtdTermCic = value }},
+	{"tx-duration", func(raw *models.RawCubeCDR, value *string) { raw.TxDuration = value }},
+	{"peer-id", func(raw *models.RawCubeCDR, value *string) { raw.PeerId = value }},
+	{"peer-if-index", func(raw *models.RawCubeCDR, value *string) { raw.PeerIfIndex = value }},
+	{"logical-if-index", func(raw *models.RawCubeCDR, value *string) { raw.LogicalIfIndex = value }},
+	{"acom-level", func(raw *models.RawCubeCDR, value *string) { raw.AcomLevel = value }},
+	{"noise-level", func(raw *models.RawCubeCDR, value *string) { raw.NoiseLevel = value }},
+	{"voice-tx-duration", func(raw *models.RawCubeCDR, value *string) { raw.VoiceTxDuration = value }},
+	{"account-code", func(raw *models.RawCubeCDR, value *string) { raw.AccountCode = value }},
+	{"codec-bytes", func(raw *models.RawCubeCDR, value *string) { raw.CodecBytes = value }},
+	{"codec-type-rate", func(raw *models.RawCubeCDR, value *string) { raw.CodecTypeRate = value }},
+	{"ontime-rv-playout", func(raw *models.RawCubeCDR, value *string) { raw.OntimeRvPlayout = value }},
+	{"remote-udp-port", func(raw *models.RawCubeCDR, value *string) { raw.RemoteUdpPort = value }},
+	{"remote-media-udp-port", func(raw *models.RawCubeCDR, value *string) { raw.RemoteMediaUdpPort = value }},
+	{"vad-enable", func(raw *models.RawCubeCDR, value *string) { raw.VadEnable = value }},
+	{"receive-delay", func(raw *models.RawCubeCDR, value *string) { raw.ReceiveDelay = value }},
+	{"round-trip-delay", func(raw *models.RawCubeCDR, value *string) { raw.RoundTripDelay = value }},
+	{"hiwater-playout-delay", func(raw *models.RawCubeCDR, value *string) { raw.HiwaterPlayoutDelay = value }},
+	{"lowater-playout-delay", func(raw *models.RawCubeCDR, value *string) { raw.LowaterPlayoutDelay = value }},
+	{"gapfill-with-interpolation", func(raw *models.RawCubeCDR, value *string) { raw.GapfillWithInterpolation = value }},
+	{"gapfill-with-redundancy", func(raw *models.RawCubeCDR, value *string) { raw.GapfillWithRedundancy = value }},
+	{"gapfill-with-silence", func(raw *models.RawCubeCDR, value *string) { raw.GapfillWithSilence = value }},
+	{"gapfill-with-prediction", func(raw *models.RawCubeCDR, value *string) { raw.GapfillWithPrediction = value }},
+	{"early-packets", func(raw *models.RawCubeCDR, value *string) { raw.EarlyPackets = value }},
+	{"late-packets", func(raw *models.RawCubeCDR, value *string) { raw.LatePackets = value }},
+	{"lost-packets", func(raw *models.RawCubeCDR, value *string) { raw.LostPackets = value }},
+	{"max-bitrate", func(raw *models.RawCubeCDR, value *string) { raw.MaxBitrate = value }},
+	{"faxrelay-start-time", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayStartTime = value }},
+	{"faxrelay-stop-time", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayStopTime = value }},
+	{"faxrelay-max-jit-buf-depth", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayMaxJitBufDepth = value }},
+	{"faxrelay-jit-buf-ovflow", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayJitBufOvflow = value }},
+	{"faxrelay-init-hs-mod", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayInitHsMod = value }},
+	{"faxrelay-mr-hs-mod", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayMrHsMod = value }},
+	{"faxrelay-num-pages", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayNumPages = value }},
+	{"faxrelay-tx-packets", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayTxPackets = value }},
+	{"faxrelay-rx-packets", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayRxPackets = value }},
+	{"faxrelay-direction", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayDirection = value }},
+	{"faxrelay-pkt-conceal", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayPktConceal = value }},
+	{"faxrelay-ecm-status", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayEcmStatus = value }},
+	{"faxrelay-encap-protocol", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayEncapProtocol = value }},
+	{"faxrelay-nsf-country-code", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayNsfCountryCode = value }},
+	{"faxrelay-nsf-manuf-code", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayNsfManufCode = value }},
+	{"faxrelay-fax-success", func(raw *models.RawCubeCDR, value *string) { raw.FaxrelayFaxSuccess = value }},
+	{"override-session-time", func(raw *models.RawCubeCDR, value *string) { raw.OverrideSessionTime = value }},
+	{"h323-ivr-out", func(raw *models.RawCubeCDR, value *string) { raw.H323IvrOut = value }},
+	{"internal-error-code", func(raw *models.RawCubeCDR, value *string) { raw.InternalErrorCode = value }},
+	{"h323-voice-quality", func(raw *models.RawCubeCDR, value *string) { raw.H323VoiceQuality = value }},
+	{"remote-media-address", func(raw *models.RawCubeCDR, value *string) { raw.RemoteMediaAddress = value }},
+	{"remote-media-id", func(raw *models.RawCubeCDR, value *string) { raw.RemoteMediaId = value }},
+	{"carrier-id", func(raw *models.RawCubeCDR, value *string) { raw.CarrierId = value }},
+	{"calling-party-category", func(raw *models.RawCubeCDR, value *string) { raw.CallingPartyCategory = value }},
+	{"originating-line-info", func(raw *models.RawCubeCDR, value *string) { raw.OriginatingLineInfo = value }},
+	{"charge-number", func(raw *models.RawCubeCDR, value *string) { raw.ChargeNumber = value }},
+	{"transmission-medium-req", func(raw *models.RawCubeCDR, value *string) { raw.TransmissionMediumReq = value }},
+	{"service-descriptor", func(raw *models.RawCubeCDR, value *string) { raw.ServiceDescriptor = value }},
+	{"outgoing-area", func(raw *models.RawCubeCDR, value *string) { raw.OutgoingArea = value }},
+	{"incoming-area", func(raw *models.RawCubeCDR, value *string) { raw.IncomingArea = value }},
+	{"out-trunkgroup-label", func(raw *models.RawCubeCDR, value *string) { raw.OutTrunkgroupLabel = value }},
+	{"out-carrier-id", func(raw *models.RawCubeCDR, value *string) { raw.OutCarrierId = value }},
+	{"dsp-id", func(raw *models.RawCubeCDR, value *string) { raw.DspId = value }},
+	{"in-trunkgroup-label", func(raw *models.RawCubeCDR, value *string) { raw.InTrunkgroupLabel = value }},
+	{"in-carrier-id", func(raw *models.RawCubeCDR, value *string) { raw.InCarrierId = value }},
+	{"cust-biz-grp-id", func(raw *models.RawCubeCDR, value *string) { raw.CustBizGrpId = value }},
+	{"supp-svc-xfer-by", func(raw *models.RawCubeCDR, value *string) { raw.SuppSvcXferBy = value }},
+	{"voice-feature", func(raw *models.RawCubeCDR, value *string) { raw.VoiceFeature = value }},
+	{"feature-operation", func(raw *models.RawCubeCDR, value *string) { raw.FeatureOperation = value }},
+	{"feature-op-status", func(raw *models.RawCubeCDR, value *string) { raw.FeatureOpStatus = value }},
+	{"feature-op-time", func(raw *models.RawCubeCDR, value *string) { raw.FeatureOpTime = value }},
+	{"gw-rxd-cdn", func(raw *models.RawCubeCDR, value *string) { raw.GwRxdCdn = value }},
+	{"gw-rxd-cgn", func(raw *models.RawCubeCDR, value *string) { raw.GwRxdCgn = value }},
+	{"gtd-gw-rxd-ocn", func(raw *models.RawCubeCDR, value *string) { raw.GtdGwRxdOcn = value }},
+	{"gtd-gw-rxd-cnn", func(raw *models.RawCubeCDR, value *string) { raw.GtdGwRxdCnn = value }},
+	{"gw-rxd-rdn", func(raw *models.RawCubeCDR, value *string) { raw.GwRxdRdn = value }},
+	{"gw-final-xlated-cdn", func(raw *models.RawCubeCDR, value *string) { raw.GwFinalXlatedCdn = value }},
+	{"gw-final-xlated-cgn", func(raw *models.RawCubeCDR, value *string) { raw.GwFinalXlatedCgn = value }},
+	{"gw-final-xlated-rdn", func(raw *models.RawCubeCDR, value *string) { raw.GwFinalXlatedRdn = value }},
+	{"gk-xlated-cdn", func(raw *models.RawCubeCDR, value *string) { raw.GkXlatedCdn = value }},
+	{"gk-xlated-cgn", func(raw *models.RawCubeCDR, value *string) { raw.GkXlatedCgn = value }},
+	{"gw-collected-cdn", func(raw *models.RawCubeCDR, value *string) { raw.GwCollectedCdn = value }},
+	{"ip-hop", func(raw *models.RawCubeCDR, value *string) { raw.IPHop = value }},
+	{"redirected-station", func(raw *models.RawCubeCDR, value *string) { raw.RedirectedStation = value }},
+	{"subscriber", func(raw *models.RawCubeCDR, value *string) { raw.Subscriber = value }},
+	{"in-intrfc-desc", func(raw *models.RawCubeCDR, value *string) { raw.InIntrfcDesc = value }},
+	{"out-intrfc-desc", func(raw *models.RawCubeCDR, value *string) { raw.OutIntrfcDesc = value }},
+	{"session-protocol", func(raw *models.RawCubeCDR, value *string) { raw.SessionProtocol = value }},
+	{"local-hostname", func(raw *models.RawCubeCDR, value *string) { raw.LocalHostname = value }},
+	{"backward-call-id", func(raw *models.RawCubeCDR, value *string) { raw.BackwardCallId = value }},
+	{"ip-phone-info", func(raw *models.RawCubeCDR, value *string) { raw.IpPhoneInfo = value }},
+	{"ip-pbx-mode", func(raw *models.RawCubeCDR, value *string) { raw.IpPbxMode = value }},
+	{"in-lpcor-group", func(raw *models.RawCubeCDR, value *string) { raw.InLpcorGroup = value }},
+	{"out-lpcor-group", func(raw *models.RawCubeCDR, value *string) { raw.OutLpcorGroup = value }},
+	{"fac-digit", func(raw *models.RawCubeCDR, value *string) { raw.FacDigit = value }},
+	{"fac-status", func(raw *models.RawCubeCDR, value *string) { raw.FacStatus = value }},
+}
+
+// featureIdFields are the fields the parts of a feature-vsa are stored in.
+var featureIdFields = []func(raw *models.RawCubeCDR, value *string){
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField1 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField2 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField3 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField4 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField5 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField6 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField7 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField8 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField9 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField10 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField11 = value },
+	func(raw *models.RawCubeCDR, value *string) { raw.FeatureIdField12 = value },
+}
+
+// ParseCubeAttributes builds a CUBE CDR from the attributes of a RADIUS
+// accounting record, keyed by AVPair name. The hostname identifies the
+// gateway the way the file name does for gw-accounting files.
+func ParseCubeAttributes(attributes map[string]string, hostname string) (*models.CubeCDR, error) {
+	raw := &models.RawCubeCDR{
+		Hostname: &hostname,
+	}
+
+	for _, c := range cubeAttributeColumns {
+		if value, ok := attributes[c.name]; ok {
+			c.set(raw, &value)
+		}
+	}
+
+	// A feature-vsa such as fn:TWC,ft:...,frs:0 is split into the feature
+	// fields like in gw-accounting files, without the keys
+	if featureVsa, ok := attributes[AttributeFeatureVsa]; ok {
+		for i, part := range strings.Split(featureVsa, ",") {
+			if i >= len(featureIdFields) {
+				break
+			}
+			value := part
+			if _, after, found := strings.Cut(part, ":"); found {
+				value = after
+			}
+			featureIdFields[i](raw, &value)
+		}
+	}
+
+	return raw.Parse(hostname)
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// callHistoryTag marks the syslog messages of gw-accounting syslog that hold
+// a call record.
+const callHistoryTag = "%VOIPAAA-5-VOIP_CALL_HISTORY:"
+
+// callHistoryKeys are the keys of the basic call history fields, by the
+// AVPair name of the CDR field they hold. Detailed templates use the AVPair
+// names themselves.
+var callHistoryKeys = map[string]string{
+	"calllegtype":     "leg-type",
+	"connectionid":    "h323-conf-id",
+	"setuptime":       "h323-setup-time",
+	"peeraddress":     "peer-address",
+	"peersubaddress":  "peer-sub-address",
+	"disconnectcause": "h323-disconnect-cause",
+	"disconnecttext":  "disconnect-text",
+	"connecttime":     "h323-connect-time",
+	"disconnecttime":  "h323-disconnect-time",
+	"callorigin":      "h323-call-origin",
+	"chargedunits":    "charged-units",
+	"infotype":        "info-type",
+	"transmitpackets": "paks-out",
+	"transmitbytes":   "bytes-out",
+	"receivepackets":  "paks-in",
+	"receivebytes":    "bytes-in",
+}
+
+// ParseCubeSyslogMessage parses a %VOIPAAA-5-VOIP_CALL_HISTORY message into a
+// CUBE CDR. It returns false for every other message.
+//
+// The fields are separated by ", " and are either "Key value" as in
+// "SetupTime 13:45:12.123 UTC Mon Jan 1 2024", or "key:value" as in
+// "h323-conf-id:5A9E0F2C 8F1B11E8 8009B0AA 77E2A070".
+func ParseCubeSyslogMessage(message string, hostname string) (*models.CubeCDR, bool, error) {
+	index := strings.Index(message, callHistoryTag)
+	if index < 0 {
+		return nil, false, nil
+	}
+
+	attributes := map[string]string{}
+	for _, field := range strings.Split(message[index+len(callHistoryTag):], ", ") {
+		field = strings.TrimSpace(field)
+		separator := strings.IndexAny(field, " :")
+		if separator <= 0 {
+			continue
+		}
+		key := strings.ToLower(field[:separator])
+		value := strings.TrimSpace(field[separator+1:])
+		if name, ok := callHistoryKeys[key]; ok {
+			key = name
+		}
+		attributes[key] = value
+	}
+
+	// Call history is written when a call leg ends, like a stop record
+	if _, ok := attributes[AttributeCdrType]; !ok {
+		attributes[AttributeCdrType] = "2"
+	}
+
+	cdr, err := ParseCubeAttributes(attributes, hostname)
+	return cdr, true, err
+}
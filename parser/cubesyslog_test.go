@@ -0,0 +1,109 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"go.uber.org/zap"
+)
+
+func TestParseCubeSyslogMessage(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	tests := []struct {
+		name           string
+		message        string
+		wantCallRecord bool
+		confId         string
+		peerAddress    string
+		legType        int64
+		cdrType        int64
+		setupTime      int64
+		bytesOut       int64
+	}{
+		{
+			name: "basic template",
+			message: "<190>123: gw1: Jan  1 13:45:30.000: %VOIPAAA-5-VOIP_CALL_HISTORY: CallLegType 1, " +
+				"ConnectionId 5A9E0F2C 8F1B11E8 8009B0AA 77E2A070, SetupTime 13:45:12.123 UTC Mon Jan 1 2024, " +
+				"PeerAddress 0441234567, TransmitBytes 1200",
+			wantCallRecord: true,
+			confId:         "5A9E0F2C 8F1B11E8 8009B0AA 77E2A070",
+			peerAddress:    "0441234567",
+			legType:        1,
+			cdrType:        2,
+			setupTime:      1704116712,
+			bytesOut:       1200,
+		},
+		{
+			name: "detailed template",
+			message: "%VOIPAAA-5-VOIP_CALL_HISTORY: call-id:26, cdr-type:1, leg-type:2, " +
+				"h323-conf-id:5A9E0F2C 8F1B11E8 8009B0AA 77E2A070, peer-address:0447654321",
+			wantCallRecord: true,
+			confId:         "5A9E0F2C 8F1B11E8 8009B0AA 77E2A070",
+			peerAddress:    "0447654321",
+			legType:        2,
+			cdrType:        1,
+		},
+		{
+			name:    "other message",
+			message: "%SYS-5-CONFIG_I: Configured from console by admin on vty0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdr, ok, err := ParseCubeSyslogMessage(tt.message, "gw1")
+			if ok != tt.wantCallRecord {
+				t.Fatalf("got call record %v, want %v", ok, tt.wantCallRecord)
+			}
+			if !ok {
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if cdr.Hostname == nil || *cdr.Hostname != "gw1" {
+				t.Errorf("hostname is %v, want gw1", cdr.Hostname)
+			}
+			if cdr.H323ConfId == nil || *cdr.H323ConfId != tt.confId {
+				t.Errorf("h323-conf-id is %v, want %q", cdr.H323ConfId, tt.confId)
+			}
+			if cdr.PeerAddress == nil || *cdr.PeerAddress != tt.peerAddress {
+				t.Errorf("peer address is %v, want %q", cdr.PeerAddress, tt.peerAddress)
+			}
+			assertInt64(t, "leg type", cdr.LegType, tt.legType)
+			assertInt64(t, "cdr type", cdr.CdrType, tt.cdrType)
+			assertInt64(t, "setup time", cdr.H323SetupTime, tt.setupTime)
+			assertInt64(t, "bytes out", cdr.BytesOut, tt.bytesOut)
+		})
+	}
+}
+
+// assertInt64 checks an optional number, where 0 stands for no value.
+func assertInt64(t *testing.T, name string, got *int64, want int64) {
+	t.Helper()
+	if want == 0 {
+		if got != nil && *got != 0 {
+			t.Errorf("%s is %d, want no value", name, *got)
+		}
+		return
+	}
+	if got == nil || *got != want {
+		t.Errorf("%s is %v, want %d", name, got, want)
+	}
+}
@@ -16,103 +16,172 @@
 package parser
 
 import (
-	"path/filepath"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/eds-ch/Go-CDR-V/database"
 	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
 )
 
-func ParseCUCMCDRs(inputFile string, db *database.DataService, outputDirectory string, deleteOriginal bool) {
+func ParseCUCMCDRs(inputFile string, db *database.DataService, ingestion *Ingestion) FileResult {
 
-	baseFileName := filepath.Base(inputFile)
+	result := FileResult{File: inputFile, Skipped: true}
 
-	if helpers.CMRReg.MatchString(baseFileName) {
-		logger.Info("Found CMR file: %s", baseFileName)
-		cdrs, err := ParseCucmCMRFile(inputFile)
-		if err != nil {
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-		}
-
-		if len(cdrs) > 0 {
-
-			err := db.CreateCucmCMRs(cdrs)
-			if err != nil {
-				logger.Error("Error while writing to database: %s", err.Error())
-			} else {
-				logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(len(cdrs)), inputFile)
-				err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-				if err != nil {
-					logger.Error("Error while moving file: %s", err.Error())
-				} else {
-					logger.Info("Successfully moved file to completed directory: %s", inputFile)
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		if helpers.CMRReg.MatchString(name) {
+			logger.Info("Found CMR file: %s", name)
+			result.Skipped = false
+			batches := newBatchWriter(db.BatchSize(), func(cdrs []*models.CucmCmr) error {
+				for _, cdr := range cdrs {
+					cdr.IngestedFileId = &ingestion.ID
 				}
+				return db.CreateCucmCMRs(cdrs)
+			})
+			rejects := newRejectWriter(db, ingestion)
+			calls := make(map[string]models.CucmGlobalCallID)
+			rejected, err := ParseCucmCMRFile(reader, name, func(cdr *models.CucmCmr) error {
+				id := cdr.GlobalCallID()
+				calls[id.Key()] = id
+				if ingestion.AlreadyWritten() {
+					return nil
+				}
+				return batches.Add(cdr)
+			}, rejects.Add)
+			written, writeErr := batches.Close()
+			_, rejectErr := rejects.Close()
+			result.Rejected += rejected
+			result.Records += written
+			if writeErr != nil {
+				logger.Error("Error while writing to database: %s", writeErr.Error())
+				return writeErr
 			}
-		} else if len(cdrs) == 0 && err == nil {
-			logger.Info("No CDRs found in file: %s", inputFile)
-			err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to completed directory: %s", inputFile)
+			if rejectErr != nil {
+				logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+				return rejectErr
 			}
-		} else {
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-			err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
 			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
+				return err
+			}
+
+			if written == 0 {
+				logger.Info("No CDRs found in file: %s", name)
 			} else {
-				logger.Info("Successfully moved file to failed directory: %s", inputFile)
+				logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(written), name)
 			}
-		}
-	}
 
-	if helpers.CDRReg.MatchString(baseFileName) {
-		logger.Info("Found CDR file: %s", baseFileName)
-		cdrs, err := ParseCucmCDRFile(inputFile)
-		if err != nil {
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-			helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
+			// CMRs of calls whose CDRs are written are matched to their legs
+			// now, the others when their CDR file arrives
+			if err := RebuildCucmCalls(db, cucmGlobalCallIDs(calls)); err != nil {
+				logger.Error("Error while rebuilding CUCM calls from %s: %s", name, err.Error())
+			}
 		}
 
-		if len(cdrs) > 0 && err == nil {
-
-			err := db.CreateCucmCDRs(cdrs)
-			if err != nil {
-				logger.Error("Error while writing to database: %s", err.Error())
-				err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
-				if err != nil {
-					logger.Error("Error while moving file: %s", err.Error())
-				} else {
-					logger.Info("Successfully moved file to failed directory: %s", inputFile)
+		if helpers.CDRReg.MatchString(name) {
+			logger.Info("Found CDR file: %s", name)
+			result.Skipped = false
+			batches := newBatchWriter(db.BatchSize(), func(cdrs []*models.CucmCdr) error {
+				for _, cdr := range cdrs {
+					cdr.IngestedFileId = &ingestion.ID
 				}
-			} else {
-				logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(len(cdrs)), inputFile)
-				err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-				if err != nil {
-					logger.Error("Error while moving file: %s", err.Error())
-				} else {
-					logger.Info("Successfully moved file to completed directory: %s", inputFile)
+				return db.CreateCucmCDRs(cdrs)
+			})
+			rejects := newRejectWriter(db, ingestion)
+			calls := make(map[string]models.CucmGlobalCallID)
+			rejected, err := ParseCucmCDRFile(reader, name, func(cdr *models.CucmCdr) error {
+				// Calls are rebuilt even if the records were written by an
+				// earlier attempt
+				id := cdr.GlobalCallID()
+				calls[id.Key()] = id
+				if ingestion.AlreadyWritten() {
+					return nil
 				}
+				return batches.Add(cdr)
+			}, rejects.Add)
+			written, writeErr := batches.Close()
+			_, rejectErr := rejects.Close()
+			result.Rejected += rejected
+			result.Records += written
+			if writeErr != nil {
+				logger.Error("Error while writing to database: %s", writeErr.Error())
+				return writeErr
 			}
-		} else if len(cdrs) == 0 && err == nil {
-			logger.Info("No CDRs found in file: %s", inputFile)
-			err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to completed directory: %s", inputFile)
+			if rejectErr != nil {
+				logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+				return rejectErr
 			}
-		} else {
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-			err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
 			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
+				return err
+			}
+
+			if written == 0 {
+				logger.Info("No CDRs found in file: %s", name)
 			} else {
-				logger.Info("Successfully moved file to failed directory: %s", inputFile)
+				logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(written), name)
+			}
+
+			// The CDRs are safely written, a failed rebuild is repaired by the
+			// next file of the call or by the rebuild command
+			if err := RebuildCucmCalls(db, cucmGlobalCallIDs(calls)); err != nil {
+				logger.Error("Error while rebuilding CUCM calls from %s: %s", name, err.Error())
 			}
 		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Skipped = false
+		result.Err = err
+	}
+
+	return result
+}
+
+func cucmGlobalCallIDs(calls map[string]models.CucmGlobalCallID) []models.CucmGlobalCallID {
+	ids := make([]models.CucmGlobalCallID, 0, len(calls))
+	for _, id := range calls {
+		ids = append(ids, id)
 	}
+	return ids
+}
+
+// cucmFilename is the metadata CUCM encodes in the names of CDR and CMR
+// files, e.g. cdr_StandAloneCluster_01_202401011200_1.
+type cucmFilename struct {
+	ClusterID *string
+	NodeID    *string
+	DateTime  *int64
+	Sequence  *int64
+}
 
+func parseCucmFilename(fileName string) cucmFilename {
+	var parsed cucmFilename
+
+	parts := strings.Split(fileName, "_")
+	if len(parts) < 5 {
+		logger.Error("Unexpected CUCM file name: %s", fileName)
+		return parsed
+	}
+
+	// The cluster ID may itself contain underscores, so the other fields are
+	// taken from the end of the name
+	last := len(parts) - 1
+	clusterID := strings.Join(parts[1:last-2], "_")
+	parsed.ClusterID = &clusterID
+	parsed.NodeID = &parts[last-2]
+
+	var err error
+	parsed.DateTime, err = helpers.ParseCUCMFilenameTimestamp(parts[last-1])
+	if err != nil {
+		logger.Error("Error parsing file date time: %s Error: %s", fileName, err)
+	}
+	parsed.Sequence, err = helpers.ConvertStringToInt64(&parts[last])
+	if err != nil {
+		logger.Error("Error parsing file sequence: %s Error: %s", fileName, err)
+	}
+	return parsed
 }
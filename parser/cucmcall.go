@@ -0,0 +1,589 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/google/uuid"
+)
+
+// maxCucmCallGlobalCallIDs stops following links between Global Call IDs, so
+// recycled leg identifiers can never pull in the whole table.
+const maxCucmCallGlobalCallIDs = 500
+
+// cucmCallLinkWindow is how far apart, in seconds, two CDRs can start and
+// still be linked by their leg identifiers or conversation IDs.
+const cucmCallLinkWindow = 24 * 60 * 60
+
+// cucmCallMutex serializes rebuilds, two directories can write legs of the
+// same call at the same time.
+var cucmCallMutex sync.Mutex
+
+// Codes of the CUCM on behalf of and redirect reason fields that mark a
+// transfer, forward or conference, see models.CucmOnBehalfOfCodes and
+// models.CucmRedirectReasons.
+var (
+	cucmTransferOnBehalfOf   = map[int64]bool{10: true}
+	cucmForwardOnBehalfOf    = map[int64]bool{5: true, 14: true}
+	cucmConferenceOnBehalfOf = map[int64]bool{4: true}
+	cucmTransferReasons      = map[int64]bool{4: true, 34: true}
+	cucmForwardReasons       = map[int64]bool{1: true, 2: true, 10: true, 15: true, 18: true, 50: true, 66: true, 82: true}
+	cucmConferenceReasons    = map[int64]bool{98: true}
+)
+
+// cucmCallStep is one entry of the chain of a CucmCall.
+type cucmCallStep struct {
+	Number string `json:"number"`
+	Reason string `json:"reason,omitempty"`
+	Time   int64  `json:"time"`
+}
+
+// RebuildCucmCalls rebuilds the calls that contain the CDRs with the given
+// Global Call IDs. CDRs already in the database are included, so legs that
+// arrive in different files end up in the same call.
+func RebuildCucmCalls(db *database.DataService, ids []models.CucmGlobalCallID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	cucmCallMutex.Lock()
+	defer cucmCallMutex.Unlock()
+
+	cdrs, oldCallIDs, complete, err := loadCucmCallCDRs(db, ids)
+	if err != nil {
+		return err
+	}
+
+	if len(cdrs) == 0 && len(oldCallIDs) == 0 {
+		return nil
+	}
+
+	// Without every CDR of the existing calls, replacing them would delete
+	// legs that are not rebuilt
+	if !complete {
+		logger.Warn("Not rebuilding CUCM calls of %d CDRs, they link more than %d Global Call IDs", len(cdrs), maxCucmCallGlobalCallIDs)
+		return CorrelateCucmCDRs(db, cdrs)
+	}
+
+	cmrs, err := loadCucmCallCMRs(db, cdrs)
+	if err != nil {
+		return err
+	}
+
+	var calls []*models.CucmCall
+	var legs []*models.CucmCallLeg
+	for _, group := range groupCucmCallCDRs(cdrs) {
+		call, callLegs := buildCucmCall(group, cmrs)
+		calls = append(calls, call)
+		legs = append(legs, callLegs...)
+	}
+
+	if err := db.ReplaceCucmCalls(oldCallIDs, calls, legs); err != nil {
+		return err
+	}
+	logger.Info("Rebuilt %d CUCM calls from %d CDRs", len(calls), len(cdrs))
+
+	return CorrelateCucmCDRs(db, cdrs)
+}
+
+// loadCucmCallCDRs reads the CDRs of the given Global Call IDs, and of every
+// Global Call ID linked to them, either by an existing call or by a shared leg
+// identifier or conversation ID. It returns the CDRs, the IDs of the existing
+// calls they belong to, and false if it stopped at maxCucmCallGlobalCallIDs.
+func loadCucmCallCDRs(db *database.DataService, ids []models.CucmGlobalCallID) ([]*models.CucmCdr, []string, bool, error) {
+	cdrs := make(map[string]*models.CucmCdr)
+	clusters := make(map[string]bool)
+	seen := make(map[string]bool)
+	seenLinks := make(map[int64]bool)
+	oldCalls := make(map[string]bool)
+	var oldCallIDs []string
+
+	pending := make(map[string]models.CucmGlobalCallID)
+	for _, id := range ids {
+		pending[id.Key()] = id
+	}
+
+	for len(pending) > 0 {
+		if len(seen)+len(pending) > maxCucmCallGlobalCallIDs {
+			logger.Warn("CUCM call links more than %d Global Call IDs, not following %d of them", maxCucmCallGlobalCallIDs, len(pending))
+			return cucmCallCDRList(cdrs), oldCallIDs, false, nil
+		}
+
+		var callIDs []int64
+		var keys []string
+		for key, id := range pending {
+			seen[key] = true
+			clusters[id.ClusterID] = true
+			callIDs = append(callIDs, id.CallID)
+			keys = append(keys, key)
+		}
+
+		found, err := db.GetCucmCDRsByCallID(callIDs)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		var added []*models.CucmCdr
+		for _, cdr := range found {
+			if _, ok := pending[cdr.GlobalCallID().Key()]; ok && cdrs[cdr.ID] == nil {
+				cdrs[cdr.ID] = cdr
+				added = append(added, cdr)
+			}
+		}
+		pending = make(map[string]models.CucmGlobalCallID)
+
+		// Every Global Call ID of an existing call is rebuilt with it,
+		// otherwise its other legs would be left without a call
+		legs, err := db.GetCucmCallLegs(keys)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for _, leg := range legs {
+			if !oldCalls[leg.CallId] {
+				oldCalls[leg.CallId] = true
+				oldCallIDs = append(oldCallIDs, leg.CallId)
+			}
+			if id, ok := models.ParseCucmGlobalCallID(leg.Globalcallid); ok && !seen[leg.Globalcallid] {
+				pending[leg.Globalcallid] = id
+			}
+		}
+
+		var links []int64
+		for _, cdr := range added {
+			for _, link := range cucmCallLinks(cdr) {
+				if !seenLinks[link] {
+					seenLinks[link] = true
+					links = append(links, link)
+				}
+			}
+		}
+		if len(links) == 0 {
+			continue
+		}
+		linked, err := db.GetCucmCDRsByConversation(links)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for _, cdr := range linked {
+			id := cdr.GlobalCallID()
+			if seen[id.Key()] || !clusters[id.ClusterID] {
+				continue
+			}
+			for _, other := range added {
+				if cucmCallsLinked(cdr, other) {
+					pending[id.Key()] = id
+					break
+				}
+			}
+		}
+	}
+
+	return cucmCallCDRList(cdrs), oldCallIDs, true, nil
+}
+
+func cucmCallCDRList(cdrs map[string]*models.CucmCdr) []*models.CucmCdr {
+	result := make([]*models.CucmCdr, 0, len(cdrs))
+	for _, cdr := range cdrs {
+		result = append(result, cdr)
+	}
+	return result
+}
+
+// cucmCallCMRs are CMRs by the Global Call ID key and call identifier of the
+// leg they measured.
+type cucmCallCMRs map[string]map[int64]*models.CucmCmr
+
+// loadCucmCallCMRs reads the CMRs of the Global Call IDs of the CDRs. CMRs
+// that arrive before their CDR are matched when the CDR is written.
+func loadCucmCallCMRs(db *database.DataService, cdrs []*models.CucmCdr) (cucmCallCMRs, error) {
+	keys := make(map[string]bool)
+	seen := make(map[int64]bool)
+	var callIDs []int64
+	for _, cdr := range cdrs {
+		id := cdr.GlobalCallID()
+		keys[id.Key()] = true
+		if !seen[id.CallID] {
+			seen[id.CallID] = true
+			callIDs = append(callIDs, id.CallID)
+		}
+	}
+
+	found, err := db.GetCucmCMRsByCallID(callIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cmrs := make(cucmCallCMRs)
+	for _, cmr := range found {
+		key := cmr.GlobalCallID().Key()
+		if !keys[key] || cmr.Callidentifier == nil {
+			continue
+		}
+		if cmrs[key] == nil {
+			cmrs[key] = make(map[int64]*models.CucmCmr)
+		}
+		// A leg has one CMR per device, the latest one wins if it has more
+		if other := cmrs[key][*cmr.Callidentifier]; other == nil || int64Value(cmr.Datetimestamp) > int64Value(other.Datetimestamp) {
+			cmrs[key][*cmr.Callidentifier] = cmr
+		}
+	}
+	return cmrs, nil
+}
+
+// leg returns the CMR of a side of a CDR, or nil.
+func (cmrs cucmCallCMRs) leg(cdr *models.CucmCdr, callIdentifier *int64) *models.CucmCmr {
+	if callIdentifier == nil || *callIdentifier == 0 {
+		return nil
+	}
+	return cmrs[cdr.GlobalCallID().Key()][*callIdentifier]
+}
+
+// cucmCallLinks returns the nonzero leg identifiers and conversation IDs of a
+// CDR. A conference leg carries the leg identifier of the conference bridge as
+// its conversation ID, and a transferred party keeps its leg identifier.
+func cucmCallLinks(cdr *models.CucmCdr) []int64 {
+	var links []int64
+	for _, value := range []*int64{cdr.Origlegcallidentifier, cdr.Destlegcallidentifier, cdr.Origconversationid, cdr.Destconversationid} {
+		if value != nil && *value != 0 {
+			links = append(links, *value)
+		}
+	}
+	return links
+}
+
+// cucmCallsLinked reports whether two CDRs of the same cluster share a leg
+// identifier or conversation ID and started close enough to be one call.
+func cucmCallsLinked(a *models.CucmCdr, b *models.CucmCdr) bool {
+	if a.GlobalCallID().ClusterID != b.GlobalCallID().ClusterID {
+		return false
+	}
+	start := int64Value(a.Datetimeorigination) - int64Value(b.Datetimeorigination)
+	if start < -cucmCallLinkWindow || start > cucmCallLinkWindow {
+		return false
+	}
+	for _, link := range cucmCallLinks(a) {
+		for _, other := range cucmCallLinks(b) {
+			if link == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupCucmCallCDRs splits CDRs into calls. CDRs with the same Global Call ID
+// are one call, and so are CDRs linked by cucmCallsLinked.
+func groupCucmCallCDRs(cdrs []*models.CucmCdr) [][]*models.CucmCdr {
+	parent := make([]int, len(cdrs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a int, b int) {
+		parent[find(a)] = find(b)
+	}
+
+	byGlobalCallID := make(map[string]int)
+	byLink := make(map[int64][]int)
+	for i, cdr := range cdrs {
+		key := cdr.GlobalCallID().Key()
+		if first, ok := byGlobalCallID[key]; ok {
+			union(i, first)
+		} else {
+			byGlobalCallID[key] = i
+		}
+		for _, link := range cucmCallLinks(cdr) {
+			for _, other := range byLink[link] {
+				if find(i) != find(other) && cucmCallsLinked(cdr, cdrs[other]) {
+					union(i, other)
+				}
+			}
+			byLink[link] = append(byLink[link], i)
+		}
+	}
+
+	groups := make(map[int][]*models.CucmCdr)
+	var roots []int
+	for i, cdr := range cdrs {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], cdr)
+	}
+
+	result := make([][]*models.CucmCdr, 0, len(roots))
+	for _, root := range roots {
+		result = append(result, groups[root])
+	}
+	return result
+}
+
+// buildCucmCall summarizes the CDRs of one call. The first leg is the one
+// that started first, and its Global Call ID is the ID of the call.
+func buildCucmCall(cdrs []*models.CucmCdr, cmrs cucmCallCMRs) (*models.CucmCall, []*models.CucmCallLeg) {
+	sort.SliceStable(cdrs, func(i, j int) bool {
+		a, b := cdrs[i], cdrs[j]
+		if int64Value(a.Datetimeorigination) != int64Value(b.Datetimeorigination) {
+			return int64Value(a.Datetimeorigination) < int64Value(b.Datetimeorigination)
+		}
+		if int64Value(a.Globalcallid_Callid) != int64Value(b.Globalcallid_Callid) {
+			return int64Value(a.Globalcallid_Callid) < int64Value(b.Globalcallid_Callid)
+		}
+		return int64Value(a.Origlegcallidentifier) < int64Value(b.Origlegcallidentifier)
+	})
+
+	first := cdrs[0]
+	id := first.GlobalCallID()
+	call := &models.CucmCall{
+		ID:                         id.Key(),
+		Globalcallid_Clusterid:     &id.ClusterID,
+		Globalcallid_Callmanagerid: first.Globalcallid_Callmanagerid,
+		Globalcallid_Callid:        first.Globalcallid_Callid,
+		Legs:                       int64(len(cdrs)),
+		StartTime:                  first.Datetimeorigination,
+		CallingNumber:              first.Callingpartynumber,
+		OriginalCalledNumber:       first.Originalcalledpartynumber,
+		UpdatedAt:                  time.Now().Unix(),
+	}
+
+	legs := make([]*models.CucmCallLeg, 0, len(cdrs))
+	var chain []cucmCallStep
+	addStep := func(number *string, reason string, stepTime int64) {
+		if number == nil || *number == "" {
+			return
+		}
+		if len(chain) > 0 && chain[len(chain)-1].Number == *number {
+			return
+		}
+		chain = append(chain, cucmCallStep{Number: *number, Reason: reason, Time: stepTime})
+	}
+
+	var answered *models.CucmCdr
+	// A party that stays in the call, e.g. after a transfer, is on several
+	// legs with one CMR
+	measured := make(map[string]bool)
+	for i, cdr := range cdrs {
+		leg := &models.CucmCallLeg{
+			ID:           uuid.New().String(),
+			CallId:       call.ID,
+			CucmCdrId:    cdr.ID,
+			Globalcallid: cdr.GlobalCallID().Key(),
+			Sequence:     int64(i + 1),
+		}
+		if cmr := cmrs.leg(cdr, cdr.Origlegcallidentifier); cmr != nil {
+			leg.OrigCmrId = &cmr.ID
+			leg.OrigMlqk = cucmCmrMlqk(cmr)
+			leg.OrigMlqkMin = cmr.Vqmlqkmn
+			leg.OrigJitter = cmr.Jitter
+			leg.OrigLatency = cmr.Latency
+			leg.OrigPacketsLost = cmr.Numberpacketslost
+			leg.OrigCcr = cmr.VQCCR
+			if !measured[cmr.ID] {
+				measured[cmr.ID] = true
+				addCucmCallQuality(call, cmr)
+			}
+		}
+		if cmr := cmrs.leg(cdr, cdr.Destlegcallidentifier); cmr != nil {
+			leg.DestCmrId = &cmr.ID
+			leg.DestMlqk = cucmCmrMlqk(cmr)
+			leg.DestMlqkMin = cmr.Vqmlqkmn
+			leg.DestJitter = cmr.Jitter
+			leg.DestLatency = cmr.Latency
+			leg.DestPacketsLost = cmr.Numberpacketslost
+			leg.DestCcr = cmr.VQCCR
+			if !measured[cmr.ID] {
+				measured[cmr.ID] = true
+				addCucmCallQuality(call, cmr)
+			}
+		}
+		legs = append(legs, leg)
+
+		if call.HuntPilot == nil && cdr.Huntpilotdn != nil && *cdr.Huntpilotdn != "" {
+			call.HuntPilot = cdr.Huntpilotdn
+		}
+		if end := int64Value(cdr.Datetimedisconnect); end > int64Value(call.EndTime) {
+			call.EndTime = cdr.Datetimedisconnect
+		}
+		if connect := int64Value(cdr.Datetimeconnect); connect > 0 {
+			if call.AnswerTime == nil || connect < *call.AnswerTime {
+				call.AnswerTime = cdr.Datetimeconnect
+			}
+			if answered == nil || connect >= int64Value(answered.Datetimeconnect) {
+				answered = cdr
+			}
+		}
+		call.TalkTime += int64Value(cdr.Duration)
+
+		onBehalfOf := []*int64{cdr.Origcallterminationonbehalfof, cdr.Destcallterminationonbehalfof, cdr.Joinonbehalfof,
+			cdr.Origcalledpartyredirectonbehalfof, cdr.Lastredirectredirectonbehalfof}
+		reasons := []*int64{cdr.Origcalledpartyredirectreason, cdr.Lastredirectredirectreason}
+		call.Transferred = call.Transferred || anyCode(onBehalfOf, cucmTransferOnBehalfOf) || anyCode(reasons, cucmTransferReasons)
+		call.Forwarded = call.Forwarded || anyCode(onBehalfOf, cucmForwardOnBehalfOf) || anyCode(reasons, cucmForwardReasons)
+		call.Conferenced = call.Conferenced || anyCode(onBehalfOf, cucmConferenceOnBehalfOf) || anyCode(reasons, cucmConferenceReasons)
+
+		// A later leg that starts at a new number was transferred or
+		// conferenced there
+		start := int64Value(cdr.Datetimeorigination)
+		var joinReason string
+		if i > 0 {
+			switch {
+			case anyCode(onBehalfOf, cucmTransferOnBehalfOf):
+				joinReason = "Transfer"
+			case anyCode(onBehalfOf, cucmConferenceOnBehalfOf):
+				joinReason = "Conference"
+			}
+		}
+		addStep(cdr.Originalcalledpartynumber, joinReason, start)
+		if !sameString(cdr.Lastredirectdn, cdr.Originalcalledpartynumber) && !sameString(cdr.Lastredirectdn, cdr.Finalcalledpartynumber) {
+			addStep(cdr.Lastredirectdn, cucmRedirectReason(cdr.Origcalledpartyredirectreason), start)
+		}
+		finalReason := cucmRedirectReason(cdr.Lastredirectredirectreason)
+		if finalReason == "" {
+			finalReason = joinReason
+		}
+		addStep(cdr.Finalcalledpartynumber, finalReason, start)
+	}
+
+	if call.StartTime != nil {
+		ringEnd := call.AnswerTime
+		if ringEnd == nil {
+			ringEnd = call.EndTime
+		}
+		if ringEnd != nil && *ringEnd >= *call.StartTime {
+			ringTime := *ringEnd - *call.StartTime
+			call.RingTime = &ringTime
+		}
+	}
+
+	if len(chain) > 0 {
+		if encoded, err := json.Marshal(chain); err == nil {
+			value := string(encoded)
+			call.Chain = &value
+		}
+	}
+
+	if answered != nil {
+		call.AnsweringNumber = answered.Finalcalledpartynumber
+		call.AnsweringDevice = answered.Destdevicename
+		call.Disposition = models.CucmCallAnswered
+	} else {
+		call.Disposition, call.DispositionCause = cucmCallDisposition(cdrs[len(cdrs)-1])
+	}
+
+	return call, legs
+}
+
+// cucmCmrMlqk returns the average MOS of a CMR, or the MOS of the last
+// interval for phones that do not report the average.
+func cucmCmrMlqk(cmr *models.CucmCmr) *float64 {
+	if cmr.Vqmlqkav != nil && *cmr.Vqmlqkav > 0 {
+		return cmr.Vqmlqkav
+	}
+	if cmr.VQMLQK != nil && *cmr.VQMLQK > 0 {
+		return cmr.VQMLQK
+	}
+	return nil
+}
+
+// addCucmCallQuality adds a CMR to the quality summary of a call.
+func addCucmCallQuality(call *models.CucmCall, cmr *models.CucmCmr) {
+	call.Cmrs++
+	if mlqk := cucmCmrMlqk(cmr); mlqk != nil && (call.MinMlqk == nil || *mlqk < *call.MinMlqk) {
+		value := *mlqk
+		call.MinMlqk = &value
+	}
+	if cmr.Jitter != nil && (call.MaxJitter == nil || *cmr.Jitter > *call.MaxJitter) {
+		value := *cmr.Jitter
+		call.MaxJitter = &value
+	}
+	if cmr.Latency != nil && (call.MaxLatency == nil || *cmr.Latency > *call.MaxLatency) {
+		value := *cmr.Latency
+		call.MaxLatency = &value
+	}
+	if cmr.Numberpacketslost != nil {
+		value := int64Value(call.PacketsLost) + *cmr.Numberpacketslost
+		call.PacketsLost = &value
+	}
+	if cmr.VQCCR != nil && (call.MaxCcr == nil || *cmr.VQCCR > *call.MaxCcr) {
+		value := *cmr.VQCCR
+		call.MaxCcr = &value
+	}
+}
+
+// cucmCallDisposition classifies an unanswered call by the release cause of
+// its last leg, the destination cause if there is one.
+func cucmCallDisposition(cdr *models.CucmCdr) (string, *int64) {
+	cause := cdr.Destcause_Value
+	if int64Value(cause) == 0 && int64Value(cdr.Origcause_Value) != 0 {
+		cause = cdr.Origcause_Value
+	}
+	switch int64Value(cause) {
+	case 17:
+		return models.CucmCallBusy, cause
+	case 18, 19:
+		return models.CucmCallNoAnswer, cause
+	case 0, 16, 31:
+		// The call was cleared normally before anyone answered
+		return models.CucmCallAbandoned, cause
+	default:
+		return models.CucmCallFailed, cause
+	}
+}
+
+func cucmRedirectReason(code *int64) string {
+	if code == nil || *code == 0 {
+		return ""
+	}
+	for _, reason := range models.CucmRedirectReasons {
+		if reason.Code == *code {
+			return reason.Description
+		}
+	}
+	return ""
+}
+
+func anyCode(values []*int64, codes map[int64]bool) bool {
+	for _, value := range values {
+		if value != nil && codes[*value] {
+			return true
+		}
+	}
+	return false
+}
+
+func sameString(a *string, b *string) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func int64Value(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
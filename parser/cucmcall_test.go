@@ -0,0 +1,227 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func testPtr[T any](value T) *T {
+	return &value
+}
+
+// cucmTestCDR returns a CDR of cluster c1 with a Global Call ID, leg
+// identifiers and a start time.
+func cucmTestCDR(id string, callID int64, origLeg int64, destLeg int64, start int64) *models.CucmCdr {
+	return &models.CucmCdr{
+		ID:                         id,
+		Globalcallid_Clusterid:     testPtr("c1"),
+		Globalcallid_Callmanagerid: testPtr(int64(1)),
+		Globalcallid_Callid:        testPtr(callID),
+		Origlegcallidentifier:      testPtr(origLeg),
+		Destlegcallidentifier:      testPtr(destLeg),
+		Datetimeorigination:        testPtr(start),
+	}
+}
+
+func TestCucmCallsLinked(t *testing.T) {
+	otherCluster := cucmTestCDR("b", 2, 12, 13, 1000)
+	otherCluster.Globalcallid_Clusterid = testPtr("c2")
+	conference := cucmTestCDR("b", 2, 20, 21, 1000)
+	conference.Destconversationid = testPtr(int64(11))
+	zero := cucmTestCDR("b", 2, 0, 21, 1000)
+
+	tests := []struct {
+		name string
+		a    *models.CucmCdr
+		b    *models.CucmCdr
+		want bool
+	}{
+		{"shared leg identifier", cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 2, 11, 12, 1060), true},
+		{"no shared identifier", cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 2, 12, 13, 1000), false},
+		{"conversation ID", cucmTestCDR("a", 1, 10, 11, 1000), conference, true},
+		{"other cluster", cucmTestCDR("a", 1, 10, 12, 1000), otherCluster, false},
+		{"too far apart", cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 2, 11, 12, 1000+cucmCallLinkWindow+1), false},
+		{"zero identifiers", cucmTestCDR("a", 1, 0, 22, 1000), zero, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cucmCallsLinked(tt.a, tt.b); got != tt.want {
+				t.Errorf("cucmCallsLinked = %v, want %v", got, tt.want)
+			}
+			if got := cucmCallsLinked(tt.b, tt.a); got != tt.want {
+				t.Errorf("cucmCallsLinked reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupCucmCallCDRs(t *testing.T) {
+	tests := []struct {
+		name string
+		cdrs []*models.CucmCdr
+		want [][]string
+	}{
+		{
+			name: "legs of one Global Call ID",
+			cdrs: []*models.CucmCdr{cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 1, 12, 13, 1010)},
+			want: [][]string{{"a", "b"}},
+		},
+		{
+			name: "transfer chain",
+			cdrs: []*models.CucmCdr{
+				cucmTestCDR("a", 1, 10, 11, 1000),
+				cucmTestCDR("b", 2, 12, 13, 1010),
+				cucmTestCDR("c", 3, 11, 13, 1020),
+			},
+			want: [][]string{{"a", "b", "c"}},
+		},
+		{
+			name: "separate calls",
+			cdrs: []*models.CucmCdr{cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 2, 12, 13, 1000)},
+			want: [][]string{{"a"}, {"b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := groupCucmCallCDRs(tt.cdrs)
+			if len(groups) != len(tt.want) {
+				t.Fatalf("got %d calls, want %d", len(groups), len(tt.want))
+			}
+			for i, group := range groups {
+				if len(group) != len(tt.want[i]) {
+					t.Fatalf("call %d has %d legs, want %v", i, len(group), tt.want[i])
+				}
+				for j, cdr := range group {
+					if cdr.ID != tt.want[i][j] {
+						t.Errorf("call %d leg %d is %s, want %s", i, j, cdr.ID, tt.want[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCucmCallDisposition(t *testing.T) {
+	tests := []struct {
+		name      string
+		origCause int64
+		destCause int64
+		want      string
+		wantCause int64
+	}{
+		{"busy", 0, 17, models.CucmCallBusy, 17},
+		{"no answer", 0, 19, models.CucmCallNoAnswer, 19},
+		{"cleared by the caller", 16, 0, models.CucmCallAbandoned, 16},
+		{"destination cause first", 41, 17, models.CucmCallBusy, 17},
+		{"origination cause without destination cause", 41, 0, models.CucmCallFailed, 41},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdr := &models.CucmCdr{Origcause_Value: testPtr(tt.origCause), Destcause_Value: testPtr(tt.destCause)}
+			got, cause := cucmCallDisposition(cdr)
+			if got != tt.want || cause == nil || *cause != tt.wantCause {
+				t.Errorf("got %s with cause %v, want %s with cause %d", got, cause, tt.want, tt.wantCause)
+			}
+		})
+	}
+}
+
+func testString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func TestBuildCucmCallMatchesCMRs(t *testing.T) {
+	key := cucmTestCDR("a", 1, 10, 11, 1000).GlobalCallID().Key()
+	caller := &models.CucmCmr{ID: "caller", Vqmlqkav: testPtr(4.1), Jitter: testPtr(int64(5)), Numberpacketslost: testPtr(int64(2))}
+	callee := &models.CucmCmr{ID: "callee", Vqmlqkav: testPtr(0.0), VQMLQK: testPtr(3.2), Jitter: testPtr(int64(9)), Numberpacketslost: testPtr(int64(3))}
+
+	tests := []struct {
+		name        string
+		cdrs        []*models.CucmCdr
+		cmrs        cucmCallCMRs
+		origCmrs    []string
+		destCmrs    []string
+		count       int64
+		minMlqk     float64
+		packetsLost int64
+	}{
+		{
+			name:        "both sides",
+			cdrs:        []*models.CucmCdr{cucmTestCDR("a", 1, 10, 11, 1000)},
+			cmrs:        cucmCallCMRs{key: {10: caller, 11: callee}},
+			origCmrs:    []string{"caller"},
+			destCmrs:    []string{"callee"},
+			count:       2,
+			minMlqk:     3.2,
+			packetsLost: 5,
+		},
+		{
+			name:        "party on two legs is counted once",
+			cdrs:        []*models.CucmCdr{cucmTestCDR("a", 1, 10, 11, 1000), cucmTestCDR("b", 1, 10, 12, 1010)},
+			cmrs:        cucmCallCMRs{key: {10: caller}},
+			origCmrs:    []string{"caller", "caller"},
+			destCmrs:    []string{"", ""},
+			count:       1,
+			minMlqk:     4.1,
+			packetsLost: 2,
+		},
+		{
+			name:     "no CMRs",
+			cdrs:     []*models.CucmCdr{cucmTestCDR("a", 1, 10, 11, 1000)},
+			cmrs:     cucmCallCMRs{},
+			origCmrs: []string{""},
+			destCmrs: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, legs := buildCucmCall(tt.cdrs, tt.cmrs)
+			for i, leg := range legs {
+				if got := testString(leg.OrigCmrId); got != tt.origCmrs[i] {
+					t.Errorf("leg %d has origin CMR %q, want %q", i, got, tt.origCmrs[i])
+				}
+				if got := testString(leg.DestCmrId); got != tt.destCmrs[i] {
+					t.Errorf("leg %d has destination CMR %q, want %q", i, got, tt.destCmrs[i])
+				}
+			}
+			if call.Cmrs != tt.count {
+				t.Errorf("call has %d CMRs, want %d", call.Cmrs, tt.count)
+			}
+			if tt.count == 0 {
+				if call.MinMlqk != nil || call.PacketsLost != nil {
+					t.Errorf("call without CMRs has MinMlqk %v and PacketsLost %v", call.MinMlqk, call.PacketsLost)
+				}
+				return
+			}
+			if call.MinMlqk == nil || *call.MinMlqk != tt.minMlqk {
+				t.Errorf("MinMlqk is %v, want %v", call.MinMlqk, tt.minMlqk)
+			}
+			if int64Value(call.PacketsLost) != tt.packetsLost {
+				t.Errorf("PacketsLost is %d, want %d", int64Value(call.PacketsLost), tt.packetsLost)
+			}
+		})
+	}
+}
@@ -17,126 +17,73 @@
 package parser
 
 import (
-	"encoding/csv"
 	"io"
-	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 
-	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
 	"github.com/eds-ch/Go-CDR-V/models"
 )
 
-func ParseCucmCMRFile(inputFile string) ([]*models.CucmCmr, error) {
+func ParseCucmCMRFile(reader io.Reader, fileName string, add func(*models.CucmCmr) error, reject func(*models.RejectedRecord) error) (int, error) {
 
-	logger.Info("Parsing file: %s", inputFile)
+	logger.Info("Parsing file: %s", fileName)
 
-	readFile, err := os.OpenFile(inputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
-	if err != nil {
-		logger.Error("Error opening file: %s Error: %s", inputFile, err)
-	}
-	defer readFile.Close()
-
-	baseFileName := filepath.Base(inputFile)
-
-	ParsedFilename := strings.Split(baseFileName, "_")
+	filename := parseCucmFilename(fileName)
 
-	FilenameClusterID := ParsedFilename[1]
-	FilenameNodeID := ParsedFilename[2]
-	FilenameDateTime, err := helpers.ParseCUCMFilenameTimestamp(ParsedFilename[3])
-	if err != nil {
-		logger.Error("Error parsing file date time: %s Error: %s", inputFile, err)
-	}
-	FilenameSequence, err := helpers.ConvertStringToInt64(&ParsedFilename[4])
-	if err != nil {
-		logger.Error("Error parsing file sequence: %s Error: %s", inputFile, err)
-	}
+	typeLine := false
+	var columns columnMap[models.RawCucmCmr]
+	rejected := 0
 
-	rawcdrs := []*models.RawCucmCmr{}
-	parsedCDRs := []*models.CucmCmr{}
-
-	lineCount := 0
-
-	reader := csv.NewReader(readFile)
+	csvReader := newRowReader(reader, fileName, "cucm_cmr")
 	for {
-		lineCount++
-		record, err := reader.Read()
+		record, err := csvReader.Read()
 		if err == io.EOF {
-			logger.Info("Finished parsing file: %s", inputFile)
+			logger.Info("Finished parsing file: %s", fileName)
 			break
 		}
 		if err != nil {
-			if perr, ok := err.(*csv.ParseError); ok && perr.Err == csv.ErrFieldCount {
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if columns == nil {
+			if isHeader(record, "cdrRecordType") {
+				typeLine = true
+				columns = newColumnMap(cucmCmrColumns, record, fileName)
 				continue
 			}
-			logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+			logger.Warn("No header found, reading columns in the default order: %s", fileName)
+			columns = positionalColumnMap(cucmCmrColumns)
+		}
+		if typeLine {
+			// The line after the header has the column types
+			typeLine = false
+			continue
 		}
 
-		if len(record) >= 44 && lineCount > 2 {
-			rawcdrs = append(rawcdrs, &models.RawCucmCmr{
-				Cdrrecordtype:                       &record[0],
-				Globalcallid_Callmanagerid:          &record[1],
-				Globalcallid_Callid:                 &record[2],
-				Nodeid:                              &record[3],
-				Directorynum:                        &record[4],
-				Callidentifier:                      &record[5],
-				Datetimestamp:                       &record[6],
-				Numberpacketssent:                   &record[7],
-				Numberoctetssent:                    &record[8],
-				Numberpacketsreceived:               &record[9],
-				Numberoctetsreceived:                &record[10],
-				Numberpacketslost:                   &record[11],
-				Jitter:                              &record[12],
-				Latency:                             &record[13],
-				Pkid:                                &record[14],
-				Directorynumpartition:               &record[15],
-				Globalcallid_Clusterid:              &record[16],
-				Devicename:                          &record[17],
-				Varvqmetrics:                        &record[18],
-				Duration:                            &record[19],
-				Videocontenttype:                    &record[20],
-				Videoduration:                       &record[21],
-				Numbervideopacketssent:              &record[22],
-				Numbervideooctetssent:               &record[23],
-				Numbervideopacketsreceived:          &record[24],
-				Numbervideooctetsreceived:           &record[25],
-				Numbervideopacketslost:              &record[26],
-				Videoaveragejitter:                  &record[27],
-				Videoroundtriptime:                  &record[28],
-				Videoonewaydelay:                    &record[29],
-				Videoreceptionmetrics:               &record[30],
-				Videotransmissionmetrics:            &record[31],
-				Videocontenttype_Channel2:           &record[32],
-				Videoduration_Channel2:              &record[33],
-				Numbervideopacketssent_Channel2:     &record[34],
-				Numbervideooctetssent_Channel2:      &record[35],
-				Numbervideopacketsreceived_Channel2: &record[36],
-				Numbervideooctetsreceived_Channel2:  &record[37],
-				Numbervideopacketslost_Channel2:     &record[38],
-				Videoaveragejitter_Channel2:         &record[39],
-				Videoroundtriptime_Channel2:         &record[40],
-				Videoonewaydelay_Channel2:           &record[41],
-				Videoreceptionmetrics_Channel2:      &record[42],
-				Videotransmissionmetrics_Channel2:   &record[43],
-				FileClusterId:                       &FilenameClusterID,
-				FileNodeId:                          &FilenameNodeID,
-				FileDateTime:                        FilenameDateTime,
-				FileSequenceNumber:                  FilenameSequence,
-			})
-		} else if len(record) != 1 && lineCount > 2 {
-			logger.Error("Error parsing CDR: %s Found %s fields instead of equal to or greater than 44", inputFile, strconv.Itoa(len(record)))
+		raw := &models.RawCucmCmr{
+			FileClusterId:      filename.ClusterID,
+			FileNodeId:         filename.NodeID,
+			FileDateTime:       filename.DateTime,
+			FileSequenceNumber: filename.Sequence,
 		}
-	}
+		columns.fill(raw, record)
 
-	for _, cdr := range rawcdrs {
-		pCDR, err := cdr.Parse(inputFile)
-		if err == nil {
-			parsedCDRs = append(parsedCDRs, pCDR)
+		cdr, err := raw.Parse(fileName)
+		if err != nil {
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+				return rejected, err
+			}
 			continue
 		}
+		if err := add(cdr); err != nil {
+			return rejected, err
+		}
 	}
 
-	return parsedCDRs, nil
+	return rejected, nil
 }
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import "github.com/eds-ch/Go-CDR-V/models"
+
+// cucmCdrColumns are the columns of CUCM CDR files by their header name, in
+// the order of files without a header.
+var cucmCdrColumns = []column[models.RawCucmCdr]{
+	{"cdrRecordType", func(raw *models.RawCucmCdr, value *string) { raw.Cdrrecordtype = value }},
+	{"globalCallID_callManagerId", func(raw *models.RawCucmCdr, value *string) { raw.Globalcallid_Callmanagerid = value }},
+	{"globalCallID_callId", func(raw *models.RawCucmCdr, value *string) { raw.Globalcallid_Callid = value }},
+	{"origLegCallIdentifier", func(raw *models.RawCucmCdr, value *string) { raw.Origlegcallidentifier = value }},
+	{"dateTimeOrigination", func(raw *models.RawCucmCdr, value *string) { raw.Datetimeorigination = value }},
+	{"origNodeId", func(raw *models.RawCucmCdr, value *string) { raw.Orignodeid = value }},
+	{"origSpan", func(raw *models.RawCucmCdr, value *string) { raw.Origspan = value }},
+	{"origIpAddr", func(raw *models.RawCucmCdr, value *string) { raw.Origipaddr = value }},
+	{"callingPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Callingpartynumber = value }},
+	{"callingPartyUnicodeLoginUserID", func(raw *models.RawCucmCdr, value *string) { raw.Callingpartyunicodeloginuserid = value }},
+	{"origCause_location", func(raw *models.RawCucmCdr, value *string) { raw.Origcause_Location = value }},
+	{"origCause_value", func(raw *models.RawCucmCdr, value *string) { raw.Origcause_Value = value }},
+	{"origPrecedenceLevel", func(raw *models.RawCucmCdr, value *string) { raw.Origprecedencelevel = value }},
+	{"origMediaTransportAddress_IP", func(raw *models.RawCucmCdr, value *string) { raw.Origmediatransportaddress_IP = value }},
+	{"origMediaTransportAddress_Port", func(raw *models.RawCucmCdr, value *string) { raw.Origmediatransportaddress_Port = value }},
+	{"origMediaCap_payloadCapability", func(raw *models.RawCucmCdr, value *string) { raw.Origmediacap_Payloadcapability = value }},
+	{"origMediaCap_maxFramesPerPacket", func(raw *models.RawCucmCdr, value *string) { raw.Origmediacap_Maxframesperpacket = value }},
+	{"origMediaCap_g723BitRate", func(raw *models.RawCucmCdr, value *string) { raw.Origmediacap_G723bitrate = value }},
+	{"origVideoCap_Codec", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Codec = value }},
+	{"origVideoCap_Bandwidth", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Bandwidth = value }},
+	{"origVideoCap_Resolution", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Resolution = value }},
+	{"origVideoTransportAddress_IP", func(raw *models.RawCucmCdr, value *string) { raw.Origvideotransportaddress_IP = value }},
+	{"origVideoTransportAddress_Port", func(raw *models.RawCucmCdr, value *string) { raw.Origvideotransportaddress_Port = value }},
+	{"origRSVPAudioStat", func(raw *models.RawCucmCdr, value *string) { raw.Origrsvpaudiostat = value }},
+	{"origRSVPVideoStat", func(raw *models.RawCucmCdr, value *string) { raw.Origrsvpvideostat = value }},
+	{"destLegIdentifier", func(raw *models.RawCucmCdr, value *string) { raw.Destlegcallidentifier = value }},
+	{"destNodeId", func(raw *models.RawCucmCdr, value *string) { raw.Destnodeid = value }},
+	{"destSpan", func(raw *models.RawCucmCdr, value *string) { raw.Destspan = value }},
+	{"destIpAddr", func(raw *models.RawCucmCdr, value *string) { raw.Destipaddr = value }},
+	{"originalCalledPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Originalcalledpartynumber = value }},
+	{"finalCalledPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Finalcalledpartynumber = value }},
+	{"finalCalledPartyUnicodeLoginUserID", func(raw *models.RawCucmCdr, value *string) { raw.Finalcalledpartyunicodeloginuserid = value }},
+	{"destCause_location", func(raw *models.RawCucmCdr, value *string) { raw.Destcause_Location = value }},
+	{"destCause_value", func(raw *models.RawCucmCdr, value *string) { raw.Destcause_Value = value }},
+	{"destPrecedenceLevel", func(raw *models.RawCucmCdr, value *string) { raw.Destprecedencelevel = value }},
+	{"destMediaTransportAddress_IP", func(raw *models.RawCucmCdr, value *string) { raw.Destmediatransportaddress_IP = value }},
+	{"destMediaTransportAddress_Port", func(raw *models.RawCucmCdr, value *string) { raw.Destmediatransportaddress_Port = value }},
+	{"destMediaCap_payloadCapability", func(raw *models.RawCucmCdr, value *string) { raw.Destmediacap_Payloadcapability = value }},
+	{"destMediaCap_maxFramesPerPacket", func(raw *models.RawCucmCdr, value *string) { raw.Destmediacap_Maxframesperpacket = value }},
+	{"destMediaCap_g723BitRate", func(raw *models.RawCucmCdr, value *string) { raw.Destmediacap_G723bitrate = value }},
+	{"destVideoCap_Codec", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Codec = value }},
+	{"destVideoCap_Bandwidth", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Bandwidth = value }},
+	{"destVideoCap_Resolution", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Resolution = value }},
+	{"destVideoTransportAddress_IP", func(raw *models.RawCucmCdr, value *string) { raw.Destvideotransportaddress_IP = value }},
+	{"destVideoTransportAddress_Port", func(raw *models.RawCucmCdr, value *string) { raw.Destvideotransportaddress_Port = value }},
+	{"destRSVPAudioStat", func(raw *models.RawCucmCdr, value *string) { raw.Destrsvpaudiostat = value }},
+	{"destRSVPVideoStat", func(raw *models.RawCucmCdr, value *string) { raw.Destrsvpvideostat = value }},
+	{"dateTimeConnect", func(raw *models.RawCucmCdr, value *string) { raw.Datetimeconnect = value }},
+	{"dateTimeDisconnect", func(raw *models.RawCucmCdr, value *string) { raw.Datetimedisconnect = value }},
+	{"lastRedirectDn", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectdn = value }},
+	{"pkid", func(raw *models.RawCucmCdr, value *string) { raw.Pkid = value }},
+	{"originalCalledPartyNumberPartition", func(raw *models.RawCucmCdr, value *string) { raw.Originalcalledpartynumberpartition = value }},
+	{"callingPartyNumberPartition", func(raw *models.RawCucmCdr, value *string) { raw.Callingpartynumberpartition = value }},
+	{"finalCalledPartyNumberPartition", func(raw *models.RawCucmCdr, value *string) { raw.Finalcalledpartynumberpartition = value }},
+	{"lastRedirectDnPartition", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectdnpartition = value }},
+	{"duration", func(raw *models.RawCucmCdr, value *string) { raw.Duration = value }},
+	{"origDeviceName", func(raw *models.RawCucmCdr, value *string) { raw.Origdevicename = value }},
+	{"destDeviceName", func(raw *models.RawCucmCdr, value *string) { raw.Destdevicename = value }},
+	{"origCallTerminationOnBehalfOf", func(raw *models.RawCucmCdr, value *string) { raw.Origcallterminationonbehalfof = value }},
+	{"destCallTerminationOnBehalfOf", func(raw *models.RawCucmCdr, value *string) { raw.Destcallterminationonbehalfof = value }},
+	{"origCalledPartyRedirectOnBehalfOf", func(raw *models.RawCucmCdr, value *string) { raw.Origcalledpartyredirectonbehalfof = value }},
+	{"lastRedirectRedirectOnBehalfOf", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectredirectonbehalfof = value }},
+	{"origCalledPartyRedirectReason", func(raw *models.RawCucmCdr, value *string) { raw.Origcalledpartyredirectreason = value }},
+	{"lastRedirectRedirectReason", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectredirectreason = value }},
+	{"destConversationId", func(raw *models.RawCucmCdr, value *string) { raw.Destconversationid = value }},
+	{"globalCallId_ClusterID", func(raw *models.RawCucmCdr, value *string) { raw.Globalcallid_Clusterid = value }},
+	{"joinOnBehalfOf", func(raw *models.RawCucmCdr, value *string) { raw.Joinonbehalfof = value }},
+	{"comment", func(raw *models.RawCucmCdr, value *string) { raw.Comment = value }},
+	{"authCodeDescription", func(raw *models.RawCucmCdr, value *string) { raw.Authcodedescription = value }},
+	{"authorizationLevel", func(raw *models.RawCucmCdr, value *string) { raw.Authorizationlevel = value }},
+	{"clientMatterCode", func(raw *models.RawCucmCdr, value *string) { raw.Clientmattercode = value }},
+	{"origDTMFMethod", func(raw *models.RawCucmCdr, value *string) { raw.Origdtmfmethod = value }},
+	{"destDTMFMethod", func(raw *models.RawCucmCdr, value *string) { raw.Destdtmfmethod = value }},
+	{"callSecuredStatus", func(raw *models.RawCucmCdr, value *string) { raw.Callsecuredstatus = value }},
+	{"origConversationId", func(raw *models.RawCucmCdr, value *string) { raw.Origconversationid = value }},
+	{"origMediaCap_Bandwidth", func(raw *models.RawCucmCdr, value *string) { raw.Origmediacap_Bandwidth = value }},
+	{"destMediaCap_Bandwidth", func(raw *models.RawCucmCdr, value *string) { raw.Destmediacap_Bandwidth = value }},
+	{"authorizationCodeValue", func(raw *models.RawCucmCdr, value *string) { raw.Authorizationcodevalue = value }},
+	{"outpulsedCallingPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Outpulsedcallingpartynumber = value }},
+	{"outpulsedCalledPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Outpulsedcalledpartynumber = value }},
+	{"origIpv4v6Addr", func(raw *models.RawCucmCdr, value *string) { raw.Origipv4v6addr = value }},
+	{"destIpv4v6Addr", func(raw *models.RawCucmCdr, value *string) { raw.Destipv4v6addr = value }},
+	{"origVideoCap_Codec_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Codec_Channel2 = value }},
+	{"origVideoCap_Bandwidth_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Bandwidth_Channel2 = value }},
+	{"origVideoCap_Resolution_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideocap_Resolution_Channel2 = value }},
+	{"origVideoTransportAddress_IP_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideotransportaddress_IP_Channel2 = value }},
+	{"origVideoTransportAddress_Port_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideotransportaddress_Port_Channel2 = value }},
+	{"origVideoChannel_Role_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Origvideochannel_Role_Channel2 = value }},
+	{"destVideoCap_Codec_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Codec_Channel2 = value }},
+	{"destVideoCap_Bandwidth_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Bandwidth_Channel2 = value }},
+	{"destVideoCap_Resolution_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideocap_Resolution_Channel2 = value }},
+	{"destVideoTransportAddress_IP_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideotransportaddress_IP_Channel2 = value }},
+	{"destVideoTransportAddress_Port_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideotransportaddress_Port_Channel2 = value }},
+	{"destVideoChannel_Role_Channel2", func(raw *models.RawCucmCdr, value *string) { raw.Destvideochannel_Role_Channel2 = value }},
+	{"IncomingProtocolID", func(raw *models.RawCucmCdr, value *string) { raw.Incomingprotocolid = value }},
+	{"IncomingProtocolCallRef", func(raw *models.RawCucmCdr, value *string) { raw.Incomingprotocolcallref = value }},
+	{"OutgoingProtocolID", func(raw *models.RawCucmCdr, value *string) { raw.Outgoingprotocolid = value }},
+	{"OutgoingProtocolCallRef", func(raw *models.RawCucmCdr, value *string) { raw.Outgoingprotocolcallref = value }},
+	{"currentRoutingReason", func(raw *models.RawCucmCdr, value *string) { raw.Currentroutingreason = value }},
+	{"origRoutingReason", func(raw *models.RawCucmCdr, value *string) { raw.Origroutingreason = value }},
+	{"lastRedirectingRoutingReason", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectingroutingreason = value }},
+	{"huntPilotPartition", func(raw *models.RawCucmCdr, value *string) { raw.Huntpilotpartition = value }},
+	{"huntPilotDN", func(raw *models.RawCucmCdr, value *string) { raw.Huntpilotdn = value }},
+	{"calledPartyPatternUsage", func(raw *models.RawCucmCdr, value *string) { raw.Calledpartypatternusage = value }},
+	{"IncomingICID", func(raw *models.RawCucmCdr, value *string) { raw.Incomingicid = value }},
+	{"IncomingOrigIOI", func(raw *models.RawCucmCdr, value *string) { raw.Incomingorigioi = value }},
+	{"IncomingTermIOI", func(raw *models.RawCucmCdr, value *string) { raw.Incomingtermioi = value }},
+	{"OutgoingICID", func(raw *models.RawCucmCdr, value *string) { raw.Outgoingicid = value }},
+	{"OutgoingOrigIOI", func(raw *models.RawCucmCdr, value *string) { raw.Outgoingorigioi = value }},
+	{"OutgoingTermIOI", func(raw *models.RawCucmCdr, value *string) { raw.Outgoingtermioi = value }},
+	{"outpulsedOriginalCalledPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Outpulsedoriginalcalledpartynumber = value }},
+	{"outpulsedLastRedirectingNumber", func(raw *models.RawCucmCdr, value *string) { raw.Outpulsedlastredirectingnumber = value }},
+	{"wasCallQueued", func(raw *models.RawCucmCdr, value *string) { raw.Wascallqueued = value }},
+	{"totalWaitTimeInQueue", func(raw *models.RawCucmCdr, value *string) { raw.Totalwaittimeinqueue = value }},
+	{"callingPartyNumber_uri", func(raw *models.RawCucmCdr, value *string) { raw.Callingpartynumber_Uri = value }},
+	{"originalCalledPartyNumber_uri", func(raw *models.RawCucmCdr, value *string) { raw.Originalcalledpartynumber_Uri = value }},
+	{"finalCalledPartyNumber_uri", func(raw *models.RawCucmCdr, value *string) { raw.Finalcalledpartynumber_Uri = value }},
+	{"lastRedirectDn_uri", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectdn_Uri = value }},
+	{"mobileCallingPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Mobilecallingpartynumber = value }},
+	{"finalMobileCalledPartyNumber", func(raw *models.RawCucmCdr, value *string) { raw.Finalmobilecalledpartynumber = value }},
+	{"origMobileDeviceName", func(raw *models.RawCucmCdr, value *string) { raw.Origmobiledevicename = value }},
+	{"destMobileDeviceName", func(raw *models.RawCucmCdr, value *string) { raw.Destmobiledevicename = value }},
+	{"origMobileCallDuration", func(raw *models.RawCucmCdr, value *string) { raw.Origmobilecallduration = value }},
+	{"destMobileCallDuration", func(raw *models.RawCucmCdr, value *string) { raw.Destmobilecallduration = value }},
+	{"mobileCallType", func(raw *models.RawCucmCdr, value *string) { raw.Mobilecalltype = value }},
+	{"originalCalledPartyPattern", func(raw *models.RawCucmCdr, value *string) { raw.Originalcalledpartypattern = value }},
+	{"finalCalledPartyPattern", func(raw *models.RawCucmCdr, value *string) { raw.Finalcalledpartypattern = value }},
+	{"lastRedirectingPartyPattern", func(raw *models.RawCucmCdr, value *string) { raw.Lastredirectingpartypattern = value }},
+	{"huntPilotPattern", func(raw *models.RawCucmCdr, value *string) { raw.Huntpilotpattern = value }},
+	{"origDeviceType", func(raw *models.RawCucmCdr, value *string) { raw.Origdevicetype = value }},
+	{"destDeviceType", func(raw *models.RawCucmCdr, value *string) { raw.Destdevicetype = value }},
+	{"origDeviceSessionID", func(raw *models.RawCucmCdr, value *string) { raw.Origdevicesessionid = value }},
+	{"destDeviceSessionID", func(raw *models.RawCucmCdr, value *string) { raw.Destdevicesessionid = value }},
+}
+
+// cucmCmrColumns are the columns of CUCM CMR files by their header name, in
+// the order of files without a header.
+var cucmCmrColumns = []column[models.RawCucmCmr]{
+	{"cdrRecordType", func(raw *models.RawCucmCmr, value *string) { raw.Cdrrecordtype = value }},
+	{"globalCallID_callManagerId", func(raw *models.RawCucmCmr, value *string) { raw.Globalcallid_Callmanagerid = value }},
+	{"globalCallID_callId", func(raw *models.RawCucmCmr, value *string) { raw.Globalcallid_Callid = value }},
+	{"nodeId", func(raw *models.RawCucmCmr, value *string) { raw.Nodeid = value }},
+	{"directoryNum", func(raw *models.RawCucmCmr, value *string) { raw.Directorynum = value }},
+	{"callIdentifier", func(raw *models.RawCucmCmr, value *string) { raw.Callidentifier = value }},
+	{"dateTimeStamp", func(raw *models.RawCucmCmr, value *string) { raw.Datetimestamp = value }},
+	{"numberPacketsSent", func(raw *models.RawCucmCmr, value *string) { raw.Numberpacketssent = value }},
+	{"numberOctetsSent", func(raw *models.RawCucmCmr, value *string) { raw.Numberoctetssent = value }},
+	{"numberPacketsReceived", func(raw *models.RawCucmCmr, value *string) { raw.Numberpacketsreceived = value }},
+	{"numberOctetsReceived", func(raw *models.RawCucmCmr, value *string) { raw.Numberoctetsreceived = value }},
+	{"numberPacketsLost", func(raw *models.RawCucmCmr, value *string) { raw.Numberpacketslost = value }},
+	{"jitter", func(raw *models.RawCucmCmr, value *string) { raw.Jitter = value }},
+	{"latency", func(raw *models.RawCucmCmr, value *string) { raw.Latency = value }},
+	{"pkid", func(raw *models.RawCucmCmr, value *string) { raw.Pkid = value }},
+	{"directoryNumPartition", func(raw *models.RawCucmCmr, value *string) { raw.Directorynumpartition = value }},
+	{"globalCallId_ClusterID", func(raw *models.RawCucmCmr, value *string) { raw.Globalcallid_Clusterid = value }},
+	{"deviceName", func(raw *models.RawCucmCmr, value *string) { raw.Devicename = value }},
+	{"varVQMetrics", func(raw *models.RawCucmCmr, value *string) { raw.Varvqmetrics = value }},
+	{"duration", func(raw *models.RawCucmCmr, value *string) { raw.Duration = value }},
+	{"videoContentType", func(raw *models.RawCucmCmr, value *string) { raw.Videocontenttype = value }},
+	{"videoDuration", func(raw *models.RawCucmCmr, value *string) { raw.Videoduration = value }},
+	{"numberVideoPacketsSent", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketssent = value }},
+	{"numberVideoOctetsSent", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideooctetssent = value }},
+	{"numberVideoPacketsReceived", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketsreceived = value }},
+	{"numberVideoOctetsReceived", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideooctetsreceived = value }},
+	{"numberVideoPacketsLost", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketslost = value }},
+	{"videoAverageJitter", func(raw *models.RawCucmCmr, value *string) { raw.Videoaveragejitter = value }},
+	{"videoRoundTripTime", func(raw *models.RawCucmCmr, value *string) { raw.Videoroundtriptime = value }},
+	{"videoOneWayDelay", func(raw *models.RawCucmCmr, value *string) { raw.Videoonewaydelay = value }},
+	{"videoReceptionMetrics", func(raw *models.RawCucmCmr, value *string) { raw.Videoreceptionmetrics = value }},
+	{"videoTransmissionMetrics", func(raw *models.RawCucmCmr, value *string) { raw.Videotransmissionmetrics = value }},
+	{"videoContentType_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videocontenttype_Channel2 = value }},
+	{"videoDuration_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videoduration_Channel2 = value }},
+	{"numberVideoPacketsSent_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketssent_Channel2 = value }},
+	{"numberVideoOctetsSent_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideooctetssent_Channel2 = value }},
+	{"numberVideoPacketsReceived_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketsreceived_Channel2 = value }},
+	{"numberVideoOctetsReceived_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideooctetsreceived_Channel2 = value }},
+	{"numberVideoPacketsLost_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Numbervideopacketslost_Channel2 = value }},
+	{"videoAverageJitter_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videoaveragejitter_Channel2 = value }},
+	{"videoRoundTripTime_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videoroundtriptime_Channel2 = value }},
+	{"videoOneWayDelay_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videoonewaydelay_Channel2 = value }},
+	{"videoReceptionMetrics_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videoreceptionmetrics_Channel2 = value }},
+	{"videoTransmissionMetrics_channel2", func(raw *models.RawCucmCmr, value *string) { raw.Videotransmissionmetrics_Channel2 = value }},
+	{"localSessionID", func(raw *models.RawCucmCmr, value *string) { raw.Localsessionid = value }},
+	{"remoteSessionID", func(raw *models.RawCucmCmr, value *string) { raw.Remotesessionid = value }},
+	{"headsetSN", func(raw *models.RawCucmCmr, value *string) { raw.Headsetsn = value }},
+	{"headsetMetrics", func(raw *models.RawCucmCmr, value *string) { raw.Headsetmetrics = value }},
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/google/uuid"
+)
+
+// cucmCubeWindow is how far apart, in seconds, a CUCM CDR and a CUBE call can
+// end and still be matched by their numbers.
+const cucmCubeWindow = 5
+
+// cucmCubeMinDigits is the number of digits two numbers must at least share
+// at their end to match, so an extension matches the full number CUBE sent.
+const cucmCubeMinDigits = 4
+
+// cucmTrunkProtocols are the CUCM protocol IDs of SIP and H.323 trunks, the
+// only legs that can reach a CUBE.
+var cucmTrunkProtocols = map[int64]bool{1: true, 2: true}
+
+// cucmCubeMutex serializes correlation, CUCM and CUBE records of the same call
+// can be written at the same time.
+var cucmCubeMutex sync.Mutex
+
+// cucmCubeSide is the leg of a CUCM CDR towards a trunk, in one direction.
+type cucmCubeSide struct {
+	direction string
+	ref       *string
+	protocol  *int64
+}
+
+func cucmCubeSides(cdr *models.CucmCdr) []cucmCubeSide {
+	return []cucmCubeSide{
+		{models.CucmCubeOutbound, cdr.Outgoingprotocolcallref, cdr.Outgoingprotocolid},
+		{models.CucmCubeInbound, cdr.Incomingprotocolcallref, cdr.Incomingprotocolid},
+	}
+}
+
+// CorrelateCubeCalls links the CUBE calls to the CUCM CDRs of the same calls
+// that are already in the database.
+func CorrelateCubeCalls(db *database.DataService, calls []*models.CubeCall) error {
+	var refs []string
+	var times []int64
+	for _, call := range calls {
+		if call.H323ConfId != nil {
+			// CUCM keeps the case the reference was sent in
+			ref := models.NormalizeCallRef(*call.H323ConfId)
+			refs = append(refs, ref, strings.ToLower(ref))
+		}
+		if call.DisconnectTime != nil {
+			times = append(times, *call.DisconnectTime)
+		}
+	}
+
+	cdrs, err := db.GetCucmCDRsByProtocolCallRef(refs)
+	if err != nil {
+		return err
+	}
+	for _, window := range cucmCubeWindows(times) {
+		found, err := db.GetCucmCDRsByDisconnectTime(window[0], window[1])
+		if err != nil {
+			return err
+		}
+		cdrs = append(cdrs, found...)
+	}
+
+	seen := make(map[string]bool, len(cdrs))
+	unique := cdrs[:0]
+	for _, cdr := range cdrs {
+		if !seen[cdr.ID] {
+			seen[cdr.ID] = true
+			unique = append(unique, cdr)
+		}
+	}
+	return CorrelateCucmCDRs(db, unique)
+}
+
+// CorrelateCucmCDRs links the CUCM CDRs to the CUBE calls of the same calls
+// that are already in the database, and replaces their earlier links. A
+// trunk leg is linked to the CUBE calls whose H323ConfId is its protocol call
+// reference. A trunk leg without one is linked to the CUBE call that ended
+// within cucmCubeWindow seconds with the same called number, preferring the
+// one that also has the same calling number and ended closest.
+func CorrelateCucmCDRs(db *database.DataService, cdrs []*models.CucmCdr) error {
+	if len(cdrs) == 0 {
+		return nil
+	}
+
+	cucmCubeMutex.Lock()
+	defer cucmCubeMutex.Unlock()
+
+	var confIDs []string
+	var times []int64
+	for _, cdr := range cdrs {
+		for _, side := range cucmCubeSides(cdr) {
+			if side.ref != nil {
+				confIDs = append(confIDs, models.CubeConfIDs(*side.ref)...)
+			}
+			if cucmTrunkProtocols[int64Value(side.protocol)] && cdr.Datetimedisconnect != nil {
+				times = append(times, *cdr.Datetimedisconnect)
+			}
+		}
+	}
+
+	byRef := make(map[string][]*models.CubeCall)
+	found, err := db.GetCubeCallsByConfID(confIDs)
+	if err != nil {
+		return err
+	}
+	for _, call := range found {
+		ref := models.NormalizeCallRef(stringValue(call.H323ConfId))
+		byRef[ref] = append(byRef[ref], call)
+	}
+
+	var nearby []*models.CubeCall
+	for _, window := range cucmCubeWindows(times) {
+		found, err := db.GetCubeCallsByDisconnectTime(window[0], window[1])
+		if err != nil {
+			return err
+		}
+		nearby = append(nearby, found...)
+	}
+
+	now := time.Now().Unix()
+	ids := make([]string, 0, len(cdrs))
+	var links []*models.CucmCubeLink
+	for _, cdr := range cdrs {
+		ids = append(ids, cdr.ID)
+		for _, side := range cucmCubeSides(cdr) {
+			method := models.CucmCubeCallRef
+			var matches []*models.CubeCall
+			if side.ref != nil && *side.ref != "" {
+				matches = byRef[models.NormalizeCallRef(*side.ref)]
+			}
+			if len(matches) == 0 && cucmTrunkProtocols[int64Value(side.protocol)] {
+				method = models.CucmCubeTimeNumber
+				if call := matchCubeCallByNumber(cdr, side.direction, nearby); call != nil {
+					matches = []*models.CubeCall{call}
+				}
+			}
+			for _, call := range matches {
+				links = append(links, &models.CucmCubeLink{
+					ID:            uuid.New().String(),
+					CucmCdrId:     cdr.ID,
+					CubeCallId:    call.ID,
+					Direction:     side.direction,
+					Method:        method,
+					OffsetSeconds: int64Value(call.DisconnectTime) - int64Value(cdr.Datetimedisconnect),
+					UpdatedAt:     now,
+				})
+			}
+		}
+	}
+
+	if err := db.ReplaceCucmCubeLinks(ids, links); err != nil {
+		return fmt.Errorf("failed to link CUCM CDRs to CUBE calls: %w", err)
+	}
+	logger.Info("Linked %d CUCM CDRs to CUBE calls with %d links", len(cdrs), len(links))
+	return nil
+}
+
+// matchCubeCallByNumber returns the CUBE call that best matches a trunk leg
+// of a CDR by disconnect time and numbers, or nil. An outbound leg is
+// compared with the numbers CUBE received, an inbound leg with the numbers
+// CUBE sent.
+func matchCubeCallByNumber(cdr *models.CucmCdr, direction string, calls []*models.CubeCall) *models.CubeCall {
+	var best *models.CubeCall
+	bestScore, bestOffset := 0, int64(0)
+	for _, call := range calls {
+		offset := int64Value(call.DisconnectTime) - int64Value(cdr.Datetimedisconnect)
+		if offset < 0 {
+			offset = -offset
+		}
+		if call.DisconnectTime == nil || offset > cucmCubeWindow {
+			continue
+		}
+
+		var called, calling bool
+		if direction == models.CucmCubeOutbound {
+			called = numbersMatch([]*string{cdr.Outpulsedcalledpartynumber, cdr.Finalcalledpartynumber}, []*string{call.GwRxdCdn})
+			calling = numbersMatch([]*string{cdr.Outpulsedcallingpartynumber, cdr.Callingpartynumber}, []*string{call.GwRxdCgn})
+		} else {
+			called = numbersMatch([]*string{cdr.Originalcalledpartynumber, cdr.Finalcalledpartynumber}, []*string{call.GwFinalXlatedCdn})
+			calling = numbersMatch([]*string{cdr.Callingpartynumber}, []*string{call.GwFinalXlatedCgn})
+		}
+		if !called {
+			continue
+		}
+
+		score := 1
+		if calling {
+			score = 2
+		}
+		if score > bestScore || score == bestScore && offset < bestOffset {
+			best, bestScore, bestOffset = call, score, offset
+		}
+	}
+	return best
+}
+
+// numbersMatch reports whether any CUCM number ends with any CUBE number, or
+// the other way round, comparing only digits.
+func numbersMatch(cucm []*string, cube []*string) bool {
+	for _, a := range cucm {
+		for _, b := range cube {
+			x, y := numberDigits(a), numberDigits(b)
+			if len(x) < cucmCubeMinDigits || len(y) < cucmCubeMinDigits {
+				continue
+			}
+			if strings.HasSuffix(x, y) || strings.HasSuffix(y, x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func numberDigits(number *string) string {
+	if number == nil {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, *number)
+}
+
+// cucmCubeWindows returns the time ranges within cucmCubeWindow seconds of
+// the given times, merged where they overlap.
+func cucmCubeWindows(times []int64) [][2]int64 {
+	sorted := append([]int64(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var windows [][2]int64
+	for _, value := range sorted {
+		if value == 0 {
+			continue
+		}
+		if n := len(windows); n > 0 && value-cucmCubeWindow <= windows[n-1][1] {
+			windows[n-1][1] = value + cucmCubeWindow
+			continue
+		}
+		windows = append(windows, [2]int64{value - cucmCubeWindow, value + cucmCubeWindow})
+	}
+	return windows
+}
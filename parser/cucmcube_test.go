@@ -0,0 +1,164 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func TestNumbersMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		cucm []*string
+		cube []*string
+		want bool
+	}{
+		{"same number", []*string{testPtr("0441234567")}, []*string{testPtr("0441234567")}, true},
+		{"extension of the full number", []*string{testPtr("4567")}, []*string{testPtr("+41441234567")}, true},
+		{"prefix digit on the CUCM side", []*string{testPtr("90441234567")}, []*string{testPtr("0441234567")}, true},
+		{"formatting ignored", []*string{testPtr("+41 44 123 45 67")}, []*string{testPtr("41441234567")}, true},
+		{"too short", []*string{testPtr("567")}, []*string{testPtr("0441234567")}, false},
+		{"different number", []*string{testPtr("0441234568")}, []*string{testPtr("0441234567")}, false},
+		{"any of several numbers", []*string{nil, testPtr("0449999999"), testPtr("0441234567")}, []*string{testPtr("0441234567")}, true},
+		{"no numbers", []*string{nil}, []*string{testPtr("")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := numbersMatch(tt.cucm, tt.cube); got != tt.want {
+				t.Errorf("numbersMatch = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCucmCubeWindows(t *testing.T) {
+	tests := []struct {
+		name  string
+		times []int64
+		want  [][2]int64
+	}{
+		{"no times", nil, nil},
+		{"single time", []int64{1000}, [][2]int64{{995, 1005}}},
+		{"overlapping times are merged", []int64{1008, 1000}, [][2]int64{{995, 1013}}},
+		{"touching times are merged", []int64{1000, 1010}, [][2]int64{{995, 1015}}},
+		{"separate times", []int64{1000, 1011}, [][2]int64{{995, 1005}, {1006, 1016}}},
+		{"zero times are left out", []int64{0, 1000}, [][2]int64{{995, 1005}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cucmCubeWindows(tt.times)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got windows %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got windows %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchCubeCallByNumber(t *testing.T) {
+	cdr := &models.CucmCdr{
+		Callingpartynumber:          testPtr("1001"),
+		Originalcalledpartynumber:   testPtr("2002"),
+		Finalcalledpartynumber:      testPtr("2002"),
+		Outpulsedcallingpartynumber: testPtr("0441231001"),
+		Outpulsedcalledpartynumber:  testPtr("0449876543"),
+		Datetimedisconnect:          testPtr(int64(1000)),
+	}
+	// received is a call CUBE received from CUCM, sent one CUBE sent to it
+	received := func(id string, disconnect int64, called string, calling string) *models.CubeCall {
+		return &models.CubeCall{ID: id, DisconnectTime: testPtr(disconnect), GwRxdCdn: testPtr(called), GwRxdCgn: testPtr(calling)}
+	}
+	sent := func(id string, disconnect int64, called string, calling string) *models.CubeCall {
+		return &models.CubeCall{ID: id, DisconnectTime: testPtr(disconnect), GwFinalXlatedCdn: testPtr(called), GwFinalXlatedCgn: testPtr(calling)}
+	}
+
+	tests := []struct {
+		name      string
+		direction string
+		calls     []*models.CubeCall
+		want      string
+	}{
+		{
+			name:      "outbound by called number",
+			direction: models.CucmCubeOutbound,
+			calls:     []*models.CubeCall{received("a", 1002, "0449876543", "")},
+			want:      "a",
+		},
+		{
+			name:      "outbound prefers the calling number",
+			direction: models.CucmCubeOutbound,
+			calls: []*models.CubeCall{
+				received("closer", 1000, "0449876543", "0440000000"),
+				received("calling", 1003, "0449876543", "0441231001"),
+			},
+			want: "calling",
+		},
+		{
+			name:      "outbound prefers the closest",
+			direction: models.CucmCubeOutbound,
+			calls: []*models.CubeCall{
+				received("far", 996, "0449876543", ""),
+				received("near", 1001, "0449876543", ""),
+			},
+			want: "near",
+		},
+		{
+			name:      "outside the window",
+			direction: models.CucmCubeOutbound,
+			calls:     []*models.CubeCall{received("a", 1000+cucmCubeWindow+1, "0449876543", "")},
+		},
+		{
+			name:      "other called number",
+			direction: models.CucmCubeOutbound,
+			calls:     []*models.CubeCall{received("a", 1000, "0441111111", "0441231001")},
+		},
+		{
+			name:      "inbound by the numbers CUBE sent",
+			direction: models.CucmCubeInbound,
+			calls: []*models.CubeCall{
+				received("received", 1000, "2002", "1001"),
+				sent("sent", 1000, "+41442002", "+41441001"),
+			},
+			want: "sent",
+		},
+		{
+			name:      "no disconnect time",
+			direction: models.CucmCubeOutbound,
+			calls:     []*models.CubeCall{{ID: "a", GwRxdCdn: testPtr("0449876543")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := matchCubeCallByNumber(cdr, tt.direction, tt.calls)
+			var got string
+			if call != nil {
+				got = call.ID
+			}
+			if got != tt.want {
+				t.Errorf("matched %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func ParseExpresswayCalls(inputFile string, db *database.DataService, ingestion *Ingestion) FileResult {
+
+	baseFileName := filepath.Base(inputFile)
+
+	result := FileResult{File: inputFile}
+
+	logger.Info("Found Expressway file: %s", baseFileName)
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		batches := newBatchWriter(db.BatchSize(), func(calls []*models.ExpresswayCall) error {
+			for _, call := range calls {
+				call.IngestedFileId = &ingestion.ID
+			}
+			return db.CreateExpresswayCalls(calls)
+		})
+		rejects := newRejectWriter(db, ingestion)
+		rejected, err := ParseExpresswayCallFile(reader, name, func(call *models.ExpresswayCall) error {
+			if ingestion.AlreadyWritten() {
+				return nil
+			}
+			return batches.Add(call)
+		}, rejects.Add)
+		written, writeErr := batches.Close()
+		_, rejectErr := rejects.Close()
+		result.Rejected += rejected
+		result.Records += written
+		if writeErr != nil {
+			logger.Error("Error while writing to database: %s", writeErr.Error())
+			return writeErr
+		}
+		if rejectErr != nil {
+			logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+			return rejectErr
+		}
+		if err != nil {
+			return err
+		}
+
+		if written == 0 {
+			logger.Info("No calls found in file: %s", name)
+		} else {
+			logger.Info("Successfully wrote %s calls to database from %s", strconv.Itoa(written), name)
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Err = err
+	}
+
+	return result
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// ParseExpresswayCallFile reads an Expressway call history export. JSON from
+// the status API is told apart from a CSV export by its first character.
+func ParseExpresswayCallFile(reader io.Reader, fileName string, add func(*models.ExpresswayCall) error, reject func(*models.RejectedRecord) error) (int, error) {
+
+	logger.Info("Parsing file: %s", fileName)
+
+	buffered := bufio.NewReader(reader)
+	first, _ := buffered.Peek(64)
+	first = bytes.TrimLeft(bytes.TrimPrefix(first, []byte("\ufeff")), " \t\r\n")
+	if len(first) > 0 && (first[0] == '{' || first[0] == '[') {
+		return parseExpresswayJSON(buffered, fileName, add, reject)
+	}
+	return parseExpresswayCSV(buffered, fileName, add, reject)
+}
+
+func parseExpresswayCSV(reader io.Reader, fileName string, add func(*models.ExpresswayCall) error, reject func(*models.RejectedRecord) error) (int, error) {
+	var columns columnMap[models.RawExpresswayCall]
+	rejected := 0
+
+	csvReader := newRowReader(reader, fileName, "expressway_call")
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			logger.Info("Finished parsing file: %s", fileName)
+			break
+		}
+		if err != nil {
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if columns == nil {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if !containsColumn(record, "Start time") {
+				return rejected, fmt.Errorf("no Start time column in the header")
+			}
+			columns = newColumnMap(expresswayColumns, record, fileName)
+			continue
+		}
+
+		raw := &models.RawExpresswayCall{
+			Filename: &fileName,
+		}
+		columns.fill(raw, record)
+
+		call, err := raw.Parse(fileName)
+		if err != nil {
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+		if err := add(call); err != nil {
+			return rejected, err
+		}
+	}
+
+	return rejected, nil
+}
+
+func parseExpresswayJSON(reader io.Reader, fileName string, add func(*models.ExpresswayCall) error, reject func(*models.RejectedRecord) error) (int, error) {
+	var document interface{}
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	items, ok := expresswayJSONCalls(document)
+	if !ok {
+		return 0, fmt.Errorf("no list of calls found in JSON")
+	}
+
+	known := make(map[string]func(raw *models.RawExpresswayCall, value *string), len(expresswayColumns))
+	for _, c := range expresswayColumns {
+		known[columnKey(c.name)] = c.set
+	}
+	unknown := map[string]bool{}
+	rejected := 0
+
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			rejected++
+			encoded, _ := json.Marshal(item)
+			if err := reject(newRejectedRecord(fileName, "expressway_call", int64(i+1), string(encoded), models.RejectMalformedRow, fmt.Errorf("call is not an object"))); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		raw := &models.RawExpresswayCall{
+			Filename: &fileName,
+		}
+		for key, value := range object {
+			set, ok := known[columnKey(key)]
+			if !ok {
+				unknown[key] = true
+				continue
+			}
+			if text, ok := jsonScalar(value); ok {
+				set(raw, &text)
+			}
+		}
+
+		call, err := raw.Parse(fileName)
+		if err != nil {
+			rejected++
+			encoded, _ := json.Marshal(object)
+			if err := reject(newRejectedRecord(fileName, "expressway_call", int64(i+1), string(encoded), models.RejectInvalidValue, err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+		if err := add(call); err != nil {
+			return rejected, err
+		}
+	}
+
+	if len(unknown) > 0 {
+		keys := make([]string, 0, len(unknown))
+		for key := range unknown {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		logger.Warn("Ignoring unknown keys in file: %s Keys: %s", fileName, strings.Join(keys, ", "))
+	}
+	logger.Info("Finished parsing file: %s", fileName)
+
+	return rejected, nil
+}
+
+// expresswayJSONCalls finds the list of calls in a JSON document: the
+// document itself, or the first list in an object, looking into nested
+// objects.
+func expresswayJSONCalls(document interface{}) ([]interface{}, bool) {
+	switch value := document.(type) {
+	case []interface{}:
+		return value, true
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if items, ok := expresswayJSONCalls(value[key]); ok {
+				return items, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// jsonScalar returns a string, number or boolean value as text. Lists,
+// objects and null are skipped.
+func jsonScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import "github.com/eds-ch/Go-CDR-V/models"
+
+// expresswayColumns are the columns of the Expressway call history, by the
+// name of the CSV column or JSON key. Names are matched like CSV headers, so
+// "Source alias" also matches SourceAlias. Some fields have a second name
+// used by older releases.
+var expresswayColumns = []column[models.RawExpresswayCall]{
+	{"Serial number", func(raw *models.RawExpresswayCall, value *string) { raw.SerialNumber = value }},
+	{"Call serial number", func(raw *models.RawExpresswayCall, value *string) { raw.SerialNumber = value }},
+	{"Tag", func(raw *models.RawExpresswayCall, value *string) { raw.Tag = value }},
+	{"State", func(raw *models.RawExpresswayCall, value *string) { raw.State = value }},
+	{"Status", func(raw *models.RawExpresswayCall, value *string) { raw.State = value }},
+	{"Start time", func(raw *models.RawExpresswayCall, value *string) { raw.StartTime = value }},
+	{"End time", func(raw *models.RawExpresswayCall, value *string) { raw.EndTime = value }},
+	{"Duration", func(raw *models.RawExpresswayCall, value *string) { raw.Duration = value }},
+	{"Source alias", func(raw *models.RawExpresswayCall, value *string) { raw.SourceAlias = value }},
+	{"Source", func(raw *models.RawExpresswayCall, value *string) { raw.SourceAlias = value }},
+	{"Destination alias", func(raw *models.RawExpresswayCall, value *string) { raw.DestinationAlias = value }},
+	{"Destination", func(raw *models.RawExpresswayCall, value *string) { raw.DestinationAlias = value }},
+	{"Source address", func(raw *models.RawExpresswayCall, value *string) { raw.SourceAddress = value }},
+	{"Destination address", func(raw *models.RawExpresswayCall, value *string) { raw.DestinationAddress = value }},
+	{"Protocol", func(raw *models.RawExpresswayCall, value *string) { raw.Protocol = value }},
+	{"Call type", func(raw *models.RawExpresswayCall, value *string) { raw.CallType = value }},
+	{"Source zone", func(raw *models.RawExpresswayCall, value *string) { raw.SourceZone = value }},
+	{"Ingress zone", func(raw *models.RawExpresswayCall, value *string) { raw.SourceZone = value }},
+	{"Destination zone", func(raw *models.RawExpresswayCall, value *string) { raw.DestinationZone = value }},
+	{"Egress zone", func(raw *models.RawExpresswayCall, value *string) { raw.DestinationZone = value }},
+	{"Requested bandwidth", func(raw *models.RawExpresswayCall, value *string) { raw.RequestedBandwidth = value }},
+	{"Allocated bandwidth", func(raw *models.RawExpresswayCall, value *string) { raw.AllocatedBandwidth = value }},
+	{"Bandwidth", func(raw *models.RawExpresswayCall, value *string) { raw.AllocatedBandwidth = value }},
+	{"Media routed", func(raw *models.RawExpresswayCall, value *string) { raw.MediaRouted = value }},
+	{"Encryption", func(raw *models.RawExpresswayCall, value *string) { raw.Encryption = value }},
+	{"Disconnect reason", func(raw *models.RawExpresswayCall, value *string) { raw.DisconnectReason = value }},
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// Ingestion tracks a single input file in the ingested_files ledger while it
+// is parsed.
+type Ingestion struct {
+	ID    string
+	db    *database.DataService
+	entry *models.IngestedFile
+	// skip is the number of records already written by an earlier attempt
+	// that crashed, and skipped is how many of them have been passed over.
+	skip    int64
+	skipped int64
+}
+
+// BeginIngestion looks the file up in the ledger and records the start of a
+// new attempt. It returns nil and the result to report when the file must not
+// be parsed: when it was already ingested, or the ledger is unavailable.
+//
+// A file left in the processing state by a crash is resumed after the
+// records that were already written. A file that failed before is rolled
+// back and parsed again from the start.
+func BeginIngestion(fullFilePath string, directory config.DirectoryConfig, db *database.DataService) (*Ingestion, FileResult) {
+	result := FileResult{File: fullFilePath}
+
+	id, size, err := hashFile(fullFilePath)
+	if err != nil {
+		logger.Error("Error hashing file: %s Error: %s", fullFilePath, err)
+		result.Err = err
+		return nil, result
+	}
+
+	// Files with the same content, such as copies in two directories, are
+	// not ingested at the same time. The second one waits for the first and
+	// then finds it in the ledger.
+	lockContent(id)
+	ingestion, result := beginIngestion(fullFilePath, id, size, directory, db)
+	if ingestion == nil {
+		unlockContent(id)
+	}
+	return ingestion, result
+}
+
+func beginIngestion(fullFilePath string, id string, size int64, directory config.DirectoryConfig, db *database.DataService) (*Ingestion, FileResult) {
+	result := FileResult{File: fullFilePath}
+
+	existing, err := db.GetIngestedFile(id)
+	if err != nil {
+		logger.Error("Error reading ledger for file: %s Error: %s", fullFilePath, err)
+		result.Err = err
+		return nil, result
+	}
+
+	ingestion := &Ingestion{ID: id, db: db}
+
+	if existing != nil {
+		switch existing.Status {
+		case models.IngestionComplete:
+			logger.Info("File was already ingested from %s, not parsing it again: %s", existing.Path, fullFilePath)
+			result.Skipped = true
+			result.Duplicate = true
+			return nil, result
+
+		case models.IngestionProcessing:
+			written, err := db.CountIngestedRecords(id)
+			if err != nil {
+				logger.Error("Error counting records of file: %s Error: %s", fullFilePath, err)
+				result.Err = err
+				return nil, result
+			}
+			ingestion.skip = written
+			// Rejected rows are not skipped, the file is read from the start
+			// and they are rejected again
+			if err := db.DeleteRejectedRecords(id); err != nil {
+				logger.Error("Error deleting rejected records of file: %s Error: %s", fullFilePath, err)
+				result.Err = err
+				return nil, result
+			}
+			logger.Info("Resuming interrupted file after %d records: %s", written, fullFilePath)
+
+		default:
+			if err := db.RollbackIngestedFile(id); err != nil {
+				logger.Error("Error rolling back file: %s Error: %s", fullFilePath, err)
+				result.Err = err
+				return nil, result
+			}
+			logger.Info("Rolled back records of previous failed attempt: %s", fullFilePath)
+		}
+	}
+
+	path, err := filepath.Abs(fullFilePath)
+	if err != nil {
+		path = fullFilePath
+	}
+
+	ingestion.entry = &models.IngestedFile{
+		ID:        id,
+		Size:      size,
+		Path:      path,
+		Type:      directory.Type,
+		Records:   ingestion.skip,
+		Status:    models.IngestionProcessing,
+		StartedAt: time.Now().Unix(),
+	}
+	if err := db.SaveIngestedFile(ingestion.entry); err != nil {
+		logger.Error("Error writing ledger for file: %s Error: %s", fullFilePath, err)
+		result.Err = err
+		return nil, result
+	}
+
+	return ingestion, result
+}
+
+// AlreadyWritten reports whether the next record was written by the attempt
+// that is being resumed. Records are always written in file order, so these
+// are the first records of the file.
+func (in *Ingestion) AlreadyWritten() bool {
+	if in.skipped < in.skip {
+		in.skipped++
+		return true
+	}
+	return false
+}
+
+// Finish records the outcome of the attempt. Files that were not recognised
+// are removed from the ledger again.
+func (in *Ingestion) Finish(result FileResult) {
+	defer unlockContent(in.ID)
+
+	var err error
+	if result.Skipped {
+		err = in.db.DeleteIngestedFile(in.ID)
+	} else {
+		finishedAt := time.Now().Unix()
+		in.entry.Records = in.skip + int64(result.Records)
+		in.entry.Rejected = int64(result.Rejected)
+		in.entry.FinishedAt = &finishedAt
+		in.entry.Status = models.IngestionComplete
+		if result.Failed() {
+			message := result.Err.Error()
+			in.entry.Status = models.IngestionFailed
+			in.entry.Error = &message
+		}
+		err = in.db.SaveIngestedFile(in.entry)
+	}
+	if err != nil {
+		logger.Error("Error writing ledger for file: %s Error: %s", result.File, err)
+	}
+}
+
+// contentLock serializes the ingestion of files with the same content hash.
+type contentLock struct {
+	sync.Mutex
+	users int
+}
+
+var (
+	contentLocksMu sync.Mutex
+	contentLocks   = map[string]*contentLock{}
+)
+
+func lockContent(id string) {
+	contentLocksMu.Lock()
+	lock := contentLocks[id]
+	if lock == nil {
+		lock = &contentLock{}
+		contentLocks[id] = lock
+	}
+	lock.users++
+	contentLocksMu.Unlock()
+
+	lock.Lock()
+}
+
+func unlockContent(id string) {
+	contentLocksMu.Lock()
+	lock := contentLocks[id]
+	lock.users--
+	if lock.users == 0 {
+		delete(contentLocks, id)
+	}
+	contentLocksMu.Unlock()
+
+	lock.Unlock()
+}
+
+// hashFile returns the SHA-256 of the file content and its size.
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
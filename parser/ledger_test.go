@@ -0,0 +1,74 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"go.uber.org/zap"
+)
+
+func TestBeginIngestionWaitsForSameContent(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	dir := t.TempDir()
+	db := database.InitDB(config.DatabaseConfig{
+		Driver:      "sqlite",
+		Path:        filepath.Join(dir, "go-cdr.sqlite"),
+		AutoMigrate: true,
+	})
+	directory := config.DirectoryConfig{Input: dir, Type: "cucm"}
+
+	// The same file delivered to two directories
+	first := filepath.Join(dir, "cdr_1")
+	second := filepath.Join(dir, "cdr_2")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte("cdrRecordType\n1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ingestion, result := BeginIngestion(first, directory, db)
+	if ingestion == nil {
+		t.Fatalf("first file was not ingested: %+v", result)
+	}
+
+	done := make(chan FileResult)
+	go func() {
+		ingestion, result := BeginIngestion(second, directory, db)
+		if ingestion != nil {
+			ingestion.Finish(FileResult{File: second, Records: 1})
+		}
+		done <- result
+	}()
+
+	select {
+	case result := <-done:
+		t.Fatalf("second file did not wait for the first: %+v", result)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ingestion.Finish(FileResult{File: first, Records: 1})
+	if result := <-done; !result.Duplicate {
+		t.Errorf("second file was not reported as duplicate: %+v", result)
+	}
+}
@@ -16,61 +16,64 @@
 package parser
 
 import (
+	"io"
 	"path/filepath"
 	"strconv"
 
 	"github.com/eds-ch/Go-CDR-V/database"
-	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
 )
 
-func ParseOracleCDRs(inputFile string, db *database.DataService, outputDirectory string, deleteOriginal bool) {
+func ParseOracleCDRs(inputFile string, db *database.DataService, ingestion *Ingestion) FileResult {
 
 	baseFileName := filepath.Base(inputFile)
 
-	logger.Info("Found CDR file: %s", baseFileName)
-	cdrs, err := ParseCubeCDRFile(inputFile)
-	if err != nil {
-		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-		helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
-	}
+	result := FileResult{File: inputFile}
 
-	if len(cdrs) > 0 && err == nil {
-
-		err := db.CreateCubeCDRs(cdrs)
-		if err != nil {
-			logger.Error("Error while writing to database: %s", err.Error())
-			err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to failed directory: %s", inputFile)
+	logger.Info("Found CDR file: %s", baseFileName)
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		batches := newBatchWriter(db.BatchSize(), func(cdrs []*models.OracleCDR) error {
+			for _, cdr := range cdrs {
+				cdr.IngestedFileId = &ingestion.ID
 			}
-		} else {
-			logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(len(cdrs)), inputFile)
-			err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-			if err != nil {
-				logger.Error("Error while moving file: %s", err.Error())
-			} else {
-				logger.Info("Successfully moved file to completed directory: %s", inputFile)
+			return db.CreateOracleCDRs(cdrs)
+		})
+		rejects := newRejectWriter(db, ingestion)
+		rejected, err := ParseOracleCDRFile(reader, name, func(cdr *models.OracleCDR) error {
+			if ingestion.AlreadyWritten() {
+				return nil
 			}
+			return batches.Add(cdr)
+		}, rejects.Add)
+		written, writeErr := batches.Close()
+		_, rejectErr := rejects.Close()
+		result.Rejected += rejected
+		result.Records += written
+		if writeErr != nil {
+			logger.Error("Error while writing to database: %s", writeErr.Error())
+			return writeErr
 		}
-	} else if len(cdrs) == 0 && err == nil {
-		logger.Info("No CDRs found in file: %s", inputFile)
-		err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
-		if err != nil {
-			logger.Error("Error while moving file: %s", err.Error())
-		} else {
-			logger.Info("Successfully moved file to completed directory: %s", inputFile)
+		if rejectErr != nil {
+			logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+			return rejectErr
 		}
-	} else {
-		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
-		err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
 		if err != nil {
-			logger.Error("Error while moving file: %s", err.Error())
+			return err
+		}
+
+		if written == 0 {
+			logger.Info("No CDRs found in file: %s", name)
 		} else {
-			logger.Info("Successfully moved file to failed directory: %s", inputFile)
+			logger.Info("Successfully wrote %s CDRs to database from %s", strconv.Itoa(written), name)
 		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Err = err
 	}
 
+	return result
 }
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import "github.com/eds-ch/Go-CDR-V/models"
+
+// oracleColumns are the columns of Oracle SBC CDR files by their RADIUS
+// attribute name, in file order. The files have no header.
+var oracleColumns = []column[models.RawOracleCDR]{
+	{"Acct-Status-Type", func(raw *models.RawOracleCDR, value *string) { raw.Accountingstatus = value }},
+	{"NAS-IP-Address", func(raw *models.RawOracleCDR, value *string) { raw.Nasipaddress = value }},
+	{"NAS-Port", func(raw *models.RawOracleCDR, value *string) { raw.Nasport = value }},
+	{"Acct-Session-Id", func(raw *models.RawOracleCDR, value *string) { raw.Accountingsessionid = value }},
+	{"Acme-Session-Ingress-CallId", func(raw *models.RawOracleCDR, value *string) { raw.Ingresssessionid = value }},
+	{"Acme-Session-Egress-CallId", func(raw *models.RawOracleCDR, value *string) { raw.Egresssessionid = value }},
+	{"Acme-Session-Protocol-Type", func(raw *models.RawOracleCDR, value *string) { raw.Sessionprotocoltype = value }},
+	{"Calling-Station-Id", func(raw *models.RawOracleCDR, value *string) { raw.Callingstationid = value }},
+	{"Called-Station-Id", func(raw *models.RawOracleCDR, value *string) { raw.Calledstationid = value }},
+	{"Acct-Terminate-Cause", func(raw *models.RawOracleCDR, value *string) { raw.Accountingterminationcause = value }},
+	{"Acct-Session-Time", func(raw *models.RawOracleCDR, value *string) { raw.Accountingsessiontime = value }},
+	{"h323-setup-time", func(raw *models.RawOracleCDR, value *string) { raw.Ciscosetuptime = value }},
+	{"h323-connect-time", func(raw *models.RawOracleCDR, value *string) { raw.Ciscoconnecttime = value }},
+	{"h323-disconnect-time", func(raw *models.RawOracleCDR, value *string) { raw.Ciscodisconnecttime = value }},
+	{"h323-disconnect-cause", func(raw *models.RawOracleCDR, value *string) { raw.Ciscodisconnectcause = value }},
+	{"Acme-Egress-Network-Interface-Id", func(raw *models.RawOracleCDR, value *string) { raw.Egressnetworkinterfaceid = value }},
+	{"Acme-Egress-Vlan-Tag-Value", func(raw *models.RawOracleCDR, value *string) { raw.Egressvlantagvalue = value }},
+	{"Acme-Ingress-Network-Interface-Id", func(raw *models.RawOracleCDR, value *string) { raw.Ingressnetworkinterfaceid = value }},
+	{"Acme-Ingress-Vlan-Tag-Value", func(raw *models.RawOracleCDR, value *string) { raw.Ingressvlantagvalue = value }},
+	{"Acme-Session-Egress-Realm", func(raw *models.RawOracleCDR, value *string) { raw.Egressrealm = value }},
+	{"Acme-Session-Ingress-Realm", func(raw *models.RawOracleCDR, value *string) { raw.Ingressrealm = value }},
+	{"Acme-FlowID_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowidentifier = value }},
+	{"Acme-FlowType_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowtype = value }},
+	{"Acme-Flow-In-Realm_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputrealm = value }},
+	{"Acme-Flow-In-Src-Addr_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputsrcaddr = value }},
+	{"Acme-Flow-In-Src-Port_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputsrcport = value }},
+	{"Acme-Flow-In-Dst-Addr_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputdestaddress = value }},
+	{"Acme-Flow-In-Dst-Port_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputdestport = value }},
+	{"Acme-Flow-Out-Realm_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputrealm = value }},
+	{"Acme-Flow-Out-Src-Addr_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputsrcaddress = value }},
+	{"Acme-Flow-Out-Src-Port_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputsrcport = value }},
+	{"Acme-Flow-Out-Dst-Addr_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputdestaddr = value }},
+	{"Acme-Flow-Out-Dst-Port_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputdestport = value }},
+	{"Acme-Calling-RTCP-Packets-Lost_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcallingpacketslost = value }},
+	{"Acme-Calling-RTCP-Avg-Jitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcallingavgjitter = value }},
+	{"Acme-Calling-RTCP-Avg-Latency_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcallingavglatency = value }},
+	{"Acme-Calling-RTCP-MaxJitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcallingmaxjitter = value }},
+	{"Acme-Calling-RTCP-MaxLatency_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcallingmaxlatency = value }},
+	{"Acme-Calling-RTP-Packets-Lost_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingpacketslost = value }},
+	{"Acme-Calling-RTP-Avg-Jitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingavgjitter = value }},
+	{"Acme-Calling-RTP-MaxJitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingmaxjitter = value }},
+	{"Acme-Calling-Octets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingoctets = value }},
+	{"Acme-Calling-Packets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingpackets = value }},
+	{"Acme-Calling-R-Factor", func(raw *models.RawOracleCDR, value *string) { raw.Callingrfactor = value }},
+	{"Acme-Calling-MOS", func(raw *models.RawOracleCDR, value *string) { raw.Callingmos = value }},
+	{"Acme-FlowID_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowidentifier2 = value }},
+	{"Acme-FlowType_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowtype2 = value }},
+	{"Acme-Flow-In-Realm_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputrealm2 = value }},
+	{"Acme-Flow-In-Src-Addr_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputsrcaddr2 = value }},
+	{"Acme-Flow-In-Src-Port_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputsrcport2 = value }},
+	{"Acme-Flow-In-Dst-Addr_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputdestaddress2 = value }},
+	{"Acme-Flow-In-Dst-Port_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowinputdestport2 = value }},
+	{"Acme-Flow-Out-Realm_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputrealm2 = value }},
+	{"Acme-Flow-Out-Src-Addr_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputsrcaddress2 = value }},
+	{"Acme-Flow-Out-Src-Port_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputsrcport2 = value }},
+	{"Acme-Flow-Out-Dst-Addr_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputdestaddr2 = value }},
+	{"Acme-Flow-Out-Dst-Port_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowoutputdestport2 = value }},
+	{"Acme-Called-RTCP-Packets-Lost_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcalledpacketslost = value }},
+	{"Acme-Called-RTCP-Avg-Jitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcalledavgjitter = value }},
+	{"Acme-Called-RTCP-Avg-Latency_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcalledavglatency = value }},
+	{"Acme-Called-RTCP-MaxJitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcalledmaxjitter = value }},
+	{"Acme-Called-RTCP-MaxLatency_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtcpcalledmaxlatency = value }},
+	{"Acme-Called-RTP-Packets-Lost_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledpacketslost = value }},
+	{"Acme-Called-RTP-Avg-Jitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledavgjitter = value }},
+	{"Acme-Called-RTP-MaxJitter_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledmaxjitter = value }},
+	{"Acme-Called-Octets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledoctets = value }},
+	{"Acme-Called-Packets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledpackets = value }},
+	{"Acme-Called-R-Factor", func(raw *models.RawOracleCDR, value *string) { raw.Calledrfactor = value }},
+	{"Acme-Called-MOS", func(raw *models.RawOracleCDR, value *string) { raw.Calledmos = value }},
+	{"Acme-Firmware-Version", func(raw *models.RawOracleCDR, value *string) { raw.Firmwareversion = value }},
+	{"Acme-Local-Time-Zone", func(raw *models.RawOracleCDR, value *string) { raw.Localtimezone = value }},
+	{"Acme-Post-Dial-Delay", func(raw *models.RawOracleCDR, value *string) { raw.Postdialdelay = value }},
+	{"Acme-Primary-Routing-Number", func(raw *models.RawOracleCDR, value *string) { raw.Primaryroutingnumber = value }},
+	{"Acme-Ingress-Local-Addr", func(raw *models.RawOracleCDR, value *string) { raw.Ingresslocaladdress = value }},
+	{"Acme-Ingress-Remote-Addr", func(raw *models.RawOracleCDR, value *string) { raw.Ingressremoteaddress = value }},
+	{"Acme-Egress-Local-Addr", func(raw *models.RawOracleCDR, value *string) { raw.Egresslocaladdress = value }},
+	{"Acme-Egress-Remote-Addr", func(raw *models.RawOracleCDR, value *string) { raw.Egressremoteaddress = value }},
+	{"Acme-Session-Disposition", func(raw *models.RawOracleCDR, value *string) { raw.Sessiondisposition = value }},
+	{"Acme-Disconnect-Initiator", func(raw *models.RawOracleCDR, value *string) { raw.Disconnectinitiator = value }},
+	{"Acme-Disconnect-Cause", func(raw *models.RawOracleCDR, value *string) { raw.Disconnectcause = value }},
+	{"Acme-SIP-Status", func(raw *models.RawOracleCDR, value *string) { raw.Sipstatuscode = value }},
+	{"Acme-Egress-Final-Routing-Number", func(raw *models.RawOracleCDR, value *string) { raw.Egressroutingnumber = value }},
+	{"Acme-Calling-Media-Stop-Time", func(raw *models.RawOracleCDR, value *string) { raw.Callingmediastoptime = value }},
+	{"Acme-Called-Media-Stop-Time", func(raw *models.RawOracleCDR, value *string) { raw.Calledmediastoptime = value }},
+	{"Acme-FlowMediaType_FS1_F", func(raw *models.RawOracleCDR, value *string) { raw.Flowmediatype = value }},
+	{"Acme-FlowMediaType_FS1_R", func(raw *models.RawOracleCDR, value *string) { raw.Flowmediatype2 = value }},
+	{"Acme-Calling-Octets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingoctetstransmitted = value }},
+	{"Acme-Calling-Packets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcallingpacketstransmitted = value }},
+	{"Acme-Called-Octets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledoctetstransmitted = value }},
+	{"Acme-Called-Packets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Rtpcalledpacketstransmitted = value }},
+	{"Acme-Called-MSRP-Octets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcalledoctets = value }},
+	{"Acme-Called-MSRP-Packets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcalledpackets = value }},
+	{"Acme-Called-MSRP-Octets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcalledoctetstransmitted = value }},
+	{"Acme-Called-MSRP-Packets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcalledpacketstransmitted = value }},
+	{"Acme-Calling-MSRP-Octets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcallingoctets = value }},
+	{"Acme-Calling-MSRP-Packets_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcallingpackets = value }},
+	{"Acme-Calling-MSRP-Octets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcallingoctetstransmitted = value }},
+	{"Acme-Calling-MSRP-Packets-Transmitted_FS1", func(raw *models.RawOracleCDR, value *string) { raw.Msrpcallingpacketstransmitted = value }},
+	{"Acme-Session-Node-Functionality", func(raw *models.RawOracleCDR, value *string) { raw.Nodefunctionality = value }},
+	{"Acme-CDR-Sequence-Number", func(raw *models.RawOracleCDR, value *string) { raw.Cdrsequencenumber = value }},
+}
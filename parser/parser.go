@@ -16,32 +16,73 @@
 package parser
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/eds-ch/Go-CDR-V/config"
 	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/helpers"
 	"github.com/eds-ch/Go-CDR-V/logger"
 )
 
-func ParseFiles(inputDirectory string, outputDirectory string, fileType string, deleteOriginal bool, db *database.DataService) {
-	// Get a list of files in the input directory
+// ParseDirectories parses every configured directory, running at most
+// workers directories at the same time.
+func ParseDirectories(directories []config.DirectoryConfig, workers int, db *database.DataService) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for _, directory := range directories {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(directory config.DirectoryConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			ParseFiles(directory, db)
+		}(directory)
+	}
+
+	wg.Wait()
+}
+
+// ValidateDirectories checks that the type of every configured directory can
+// be parsed, before any of them is.
+func ValidateDirectories(directories []config.DirectoryConfig) error {
+	for _, directory := range directories {
+		if !SupportedType(directory.Type) {
+			return fmt.Errorf("directory %s: unsupported type %s", directory.Input, directory.Type)
+		}
+	}
+	return nil
+}
+
+// ParseFiles parses the files in a single directory, running at most
+// directory.Workers files at the same time.
+func ParseFiles(directory config.DirectoryConfig, db *database.DataService) {
+	inputDirectory := directory.Input
+
+	// Get a list of files in the input directory. A directory that cannot be
+	// read is tried again on the next run, the others keep being parsed.
 	files, err := os.ReadDir(inputDirectory)
 	if err != nil {
 		logger.Error("Error reading directory: %s Error: %s", inputDirectory, err)
-		os.Exit(1)
+		return
 	}
 
 	logger.Info("Parsing files in directory: %s", inputDirectory)
 
-	// TimestampedFilename := fileType + "_" + helpers.FilenameFriendlyTimeStamp() + ".csv"
-
-	// OutputFile := filepath.Join(outputDirectory, TimestampedFilename)
+	workers := directory.Workers
+	if workers <= 0 {
+		workers = 1
+	}
 
-	// writeFile, err := os.OpenFile(OutputFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
-	// if err != nil {
-	//	logger.Error("Error opening file: %s Error: %s", OutputFile, err)
-	// }
-	// defer writeFile.Close()
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
 
 	// Loop through the files in the input directory
 	for _, file := range files {
@@ -50,19 +91,112 @@ func ParseFiles(inputDirectory string, outputDirectory string, fileType string,
 		if !file.IsDir() {
 
 			fullFilePath := filepath.Join(inputDirectory, file.Name())
-			switch fileType {
-			case "cube":
-				ParseCUBECDRs(fullFilePath, db, outputDirectory, deleteOriginal)
-			case "cucm":
-				ParseCUCMCDRs(fullFilePath, db, outputDirectory, deleteOriginal)
-			default:
-				// Failed to match a file type
-				logger.Error("Failed to match file type: %s", fileType)
-				os.Exit(1)
-
-			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(fullFilePath string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				ParseFile(fullFilePath, directory, db)
+			}(fullFilePath)
 		}
 	}
 
+	wg.Wait()
+
 	logger.Info("Finished parsing files in directory: %s", inputDirectory)
 }
+
+// inFlight holds the files that are currently being parsed, so that a watch
+// event and a periodic rescan never pick up the same file twice.
+var inFlight sync.Map
+
+// ParseFile parses a single file according to the type of its directory.
+func ParseFile(fullFilePath string, directory config.DirectoryConfig, db *database.DataService) FileResult {
+	if _, busy := inFlight.LoadOrStore(fullFilePath, struct{}{}); busy {
+		logger.Debug("File is already being parsed: %s", fullFilePath)
+		return FileResult{File: fullFilePath, Skipped: true}
+	}
+	defer inFlight.Delete(fullFilePath)
+
+	// The file may have been moved away by another run since it was listed
+	if _, err := os.Stat(fullFilePath); err != nil {
+		logger.Debug("Skipping file: %s Error: %s", fullFilePath, err)
+		return FileResult{File: fullFilePath, Skipped: true}
+	}
+
+	switch checkReady(fullFilePath, directory) {
+	case fileIgnored:
+		return FileResult{File: fullFilePath, Skipped: true}
+	case fileNotReady:
+		return FileResult{File: fullFilePath, Skipped: true, NotReady: true}
+	}
+
+	ingestion, result := BeginIngestion(fullFilePath, directory, db)
+	if ingestion == nil {
+		if result.Duplicate {
+			completeFile(fullFilePath, directory.Output, directory.DeleteOriginal)
+			forgetFile(fullFilePath, directory)
+		}
+		return result
+	}
+
+	switch directory.Type {
+	case "cube":
+		result = ParseCUBECDRs(fullFilePath, db, ingestion)
+	case "cucm":
+		result = ParseCUCMCDRs(fullFilePath, db, ingestion)
+	case "oracle":
+		result = ParseOracleCDRs(fullFilePath, db, ingestion)
+	case "webex":
+		result = ParseWebexCDRs(fullFilePath, db, ingestion)
+	case "expressway":
+		result = ParseExpresswayCalls(fullFilePath, db, ingestion)
+	case "uccx":
+		result = ParseUccxCDRs(fullFilePath, db, ingestion)
+	default:
+		if p, ok := profiles[directory.Type]; ok {
+			result = ParseProfileCDRs(fullFilePath, p, db, ingestion)
+			break
+		}
+		// Failed to match a file type
+		logger.Error("Failed to match file type: %s", directory.Type)
+		result = FileResult{File: fullFilePath, Err: fmt.Errorf("unknown file type: %s", directory.Type)}
+	}
+
+	// The ledger is updated before the file is moved, so a crash in between
+	// does not ingest the file a second time
+	ingestion.Finish(result)
+
+	switch {
+	case result.Skipped:
+		// Files that are neither CDR nor CMR files are left where they are
+	case result.Failed():
+		failFile(fullFilePath, directory.Output)
+		forgetFile(fullFilePath, directory)
+	default:
+		completeFile(fullFilePath, directory.Output, directory.DeleteOriginal)
+		forgetFile(fullFilePath, directory)
+	}
+	return result
+}
+
+// completeFile moves a parsed file to the complete directory, or deletes it.
+func completeFile(inputFile string, outputDirectory string, deleteOriginal bool) {
+	err := helpers.ChangeFileNameToCompleteAndMoveOrDelete(inputFile, outputDirectory, deleteOriginal)
+	if err != nil {
+		logger.Error("Error while moving file: %s", err.Error())
+	} else {
+		logger.Info("Successfully moved file to completed directory: %s", inputFile)
+	}
+}
+
+// failFile moves a file that could not be parsed or written to the failed directory.
+func failFile(inputFile string, outputDirectory string) {
+	err := helpers.ChangeFileNameToFailedAndMove(inputFile, outputDirectory)
+	if err != nil {
+		logger.Error("Error while moving file: %s", err.Error())
+	} else {
+		logger.Info("Successfully moved file to failed directory: %s", inputFile)
+	}
+}
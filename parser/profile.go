@@ -0,0 +1,217 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// builtinTypes are the directory types with a built-in parser. Any other type
+// must be the name of a profile.
+var builtinTypes = []string{"cube", "cucm", "expressway", "oracle", "uccx", "webex"}
+
+// Profile column types.
+const (
+	ProfileString = "string"
+	ProfileInt    = "int"
+	ProfileFloat  = "float"
+	ProfileTime   = "time"
+	ProfileIP     = "ip"
+)
+
+// identifier matches the table and column names a profile may use. They are
+// quoted in SQL, so reserved words can be used too.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedProfileColumns are filled by go-cdr in every profile table.
+var reservedProfileColumns = []string{"id", "ingested_file_id", "filename"}
+
+// profile is a validated CSV profile.
+type profile struct {
+	config.ProfileConfig
+	delimiter rune
+	location  *time.Location
+}
+
+// profiles holds the profiles loaded by LoadProfiles by name.
+var profiles = map[string]*profile{}
+
+// LoadProfiles validates the CSV profiles of the configuration and sets up
+// their destination tables.
+func LoadProfiles(db *database.DataService) error {
+	for name, profileConfig := range config.GetProfilesFromGlobalConfig() {
+		p, err := newProfile(profileConfig)
+		if err != nil {
+			return err
+		}
+		if err := db.SetupProfileTable(p.ProfileConfig); err != nil {
+			return err
+		}
+		profiles[name] = p
+		logger.Info("Loaded profile %s writing to table %s", name, p.Table)
+	}
+	return nil
+}
+
+// SupportedType reports whether files of a directory type can be parsed.
+func SupportedType(fileType string) bool {
+	for _, builtin := range builtinTypes {
+		if fileType == builtin {
+			return true
+		}
+	}
+	_, ok := profiles[fileType]
+	return ok
+}
+
+func newProfile(profileConfig config.ProfileConfig) (*profile, error) {
+	p := &profile{ProfileConfig: profileConfig, delimiter: ',', location: time.UTC}
+	name := profileConfig.Name
+
+	for _, builtin := range builtinTypes {
+		if name == builtin {
+			return nil, fmt.Errorf("profile %s: name is a built-in type", name)
+		}
+	}
+
+	switch profileConfig.Delimiter {
+	case "":
+	case "tab":
+		p.delimiter = '\t'
+	default:
+		delimiter, size := utf8.DecodeRuneInString(profileConfig.Delimiter)
+		if size != len(profileConfig.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+			return nil, fmt.Errorf("profile %s: invalid delimiter %q", name, profileConfig.Delimiter)
+		}
+		p.delimiter = delimiter
+	}
+
+	if profileConfig.Timezone != "" {
+		location, err := time.LoadLocation(profileConfig.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+		p.location = location
+	}
+
+	if !identifier.MatchString(profileConfig.Table) {
+		return nil, fmt.Errorf("profile %s: invalid table name %q", name, profileConfig.Table)
+	}
+	if len(profileConfig.Columns) == 0 {
+		return nil, fmt.Errorf("profile %s: no columns", name)
+	}
+
+	seen := map[string]bool{}
+	for _, reserved := range reservedProfileColumns {
+		seen[reserved] = true
+	}
+	p.Columns = make([]config.ProfileColumn, len(profileConfig.Columns))
+	for i, column := range profileConfig.Columns {
+		if !identifier.MatchString(column.Name) {
+			return nil, fmt.Errorf("profile %s: invalid column name %q", name, column.Name)
+		}
+		if seen[column.Name] {
+			return nil, fmt.Errorf("profile %s: column %s is reserved or used twice", name, column.Name)
+		}
+		seen[column.Name] = true
+
+		switch {
+		case column.Source != "" && column.Index != 0:
+			return nil, fmt.Errorf("profile %s: column %s has both a source and an index", name, column.Name)
+		case column.Source != "" && !profileConfig.Header:
+			return nil, fmt.Errorf("profile %s: column %s has a source but the profile has no header", name, column.Name)
+		case column.Source == "" && column.Index <= 0:
+			return nil, fmt.Errorf("profile %s: column %s needs a source or an index starting at 1", name, column.Name)
+		}
+
+		switch column.Type {
+		case "":
+			column.Type = ProfileString
+		case ProfileString, ProfileInt, ProfileFloat, ProfileIP:
+		case ProfileTime:
+			if column.Layout == "" {
+				return nil, fmt.Errorf("profile %s: time column %s has no layout", name, column.Name)
+			}
+		default:
+			return nil, fmt.Errorf("profile %s: column %s has unknown type %q", name, column.Name, column.Type)
+		}
+		p.Columns[i] = column
+	}
+
+	return p, nil
+}
+
+// ParseProfileCDRs parses a file with the profile of its directory type.
+func ParseProfileCDRs(inputFile string, p *profile, db *database.DataService, ingestion *Ingestion) FileResult {
+
+	result := FileResult{File: inputFile}
+
+	logger.Info("Found %s file: %s", p.Name, filepath.Base(inputFile))
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		batches := newBatchWriter(db.BatchSize(), func(records []map[string]interface{}) error {
+			for _, record := range records {
+				record["ingested_file_id"] = ingestion.ID
+			}
+			return db.CreateProfileRecords(p.Table, records)
+		})
+		rejects := newRejectWriter(db, ingestion)
+		rejected, err := ParseProfileFile(reader, name, p, func(record map[string]interface{}) error {
+			if ingestion.AlreadyWritten() {
+				return nil
+			}
+			return batches.Add(record)
+		}, rejects.Add)
+		written, writeErr := batches.Close()
+		_, rejectErr := rejects.Close()
+		result.Rejected += rejected
+		result.Records += written
+		if writeErr != nil {
+			logger.Error("Error while writing to database: %s", writeErr.Error())
+			return writeErr
+		}
+		if rejectErr != nil {
+			logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+			return rejectErr
+		}
+		if err != nil {
+			return err
+		}
+
+		if written == 0 {
+			logger.Info("No records found in file: %s", name)
+		} else {
+			logger.Info("Successfully wrote %s records to %s from %s", strconv.Itoa(written), p.Table, name)
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Err = err
+	}
+
+	return result
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/google/uuid"
+)
+
+// ParseProfileFile reads the records of a CSV file described by a profile.
+// The keys of a record are the columns of the destination table.
+func ParseProfileFile(reader io.Reader, fileName string, p *profile, add func(map[string]interface{}) error, reject func(*models.RejectedRecord) error) (int, error) {
+
+	logger.Info("Parsing file: %s", fileName)
+
+	rejected := 0
+	skip := p.SkipRows
+	readHeader := p.Header
+	positions, fields := p.positions(nil, fileName)
+
+	csvReader := newRowReader(reader, fileName, p.Name)
+	csvReader.Comma = p.delimiter
+	csvReader.FieldsPerRecord = -1
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			logger.Info("Finished parsing file: %s", fileName)
+			break
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if err != nil {
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if readHeader {
+			readHeader = false
+			positions, fields = p.positions(record, fileName)
+			continue
+		}
+
+		if len(record) < fields {
+			logger.Error("Error parsing record: %s Found %d fields instead of at least %d", fileName, len(record), fields)
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectFieldCount, fmt.Errorf("found %d fields", len(record)))); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		row := map[string]interface{}{
+			"id":       uuid.New().String(),
+			"filename": fileName,
+		}
+		var valueErr error
+		for i, column := range p.Columns {
+			if positions[i] < 0 {
+				row[column.Name] = nil
+				continue
+			}
+			value, err := p.value(column, record[positions[i]])
+			if err != nil {
+				valueErr = err
+				break
+			}
+			row[column.Name] = value
+		}
+		if valueErr != nil {
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectInvalidValue, valueErr)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if err := add(row); err != nil {
+			return rejected, err
+		}
+	}
+
+	return rejected, nil
+}
+
+// positions returns the position of every profile column in a row, -1 for
+// columns missing from the header, and the number of fields a row needs.
+func (p *profile) positions(header []string, fileName string) ([]int, int) {
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := byName[name]; !ok {
+			byName[name] = i
+		}
+	}
+
+	positions := make([]int, len(p.Columns))
+	fields := 0
+	for i, column := range p.Columns {
+		positions[i] = column.Index - 1
+		if column.Source != "" {
+			position, ok := byName[strings.ToLower(column.Source)]
+			if !ok {
+				if header != nil {
+					logger.Warn("Column %s of profile %s is missing in file: %s", column.Source, p.Name, fileName)
+				}
+				position = -1
+			}
+			positions[i] = position
+		}
+		if positions[i] >= fields {
+			fields = positions[i] + 1
+		}
+	}
+	return positions, fields
+}
+
+// value converts a field to the type of its column. Empty fields are NULL.
+func (p *profile) value(column config.ProfileColumn, field string) (interface{}, error) {
+	if column.Type != ProfileString {
+		field = strings.TrimSpace(field)
+	}
+	if field == "" {
+		return nil, nil
+	}
+
+	switch column.Type {
+	case ProfileInt:
+		value, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: invalid integer %q", column.Name, field)
+		}
+		return value, nil
+
+	case ProfileFloat:
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: invalid number %q", column.Name, field)
+		}
+		return value, nil
+
+	case ProfileTime:
+		switch column.Layout {
+		case "unix", "unixms":
+			value, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: invalid timestamp %q", column.Name, field)
+			}
+			if column.Layout == "unixms" {
+				value /= 1000
+			}
+			return value, nil
+		}
+		value, err := time.ParseInLocation(column.Layout, field, p.location)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: invalid time %q, expected layout %s", column.Name, field, column.Layout)
+		}
+		return value.Unix(), nil
+
+	case ProfileIP:
+		ip := net.ParseIP(field)
+		if ip == nil {
+			return nil, fmt.Errorf("column %s: invalid IP address %q", column.Name, field)
+		}
+		return ip.String(), nil
+	}
+
+	return field, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// defaultIgnore are the file names that are never parsed when a directory
+// does not configure its own ignore globs: hidden files and the temporary
+// names FTP and SFTP clients upload to before renaming.
+var defaultIgnore = []string{".*", "*.filepart", "*.part", "*.partial", "*.tmp", "*.temp"}
+
+type readiness int
+
+const (
+	fileReady readiness = iota
+	// fileNotReady files are checked again later
+	fileNotReady
+	// fileIgnored files are never parsed
+	fileIgnored
+)
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// lastScan holds the size of every file at the previous check, for the
+// stable size policy.
+var lastScan sync.Map
+
+// checkReady applies the readiness policy of the directory to a file.
+func checkReady(fullFilePath string, directory config.DirectoryConfig) readiness {
+	name := filepath.Base(fullFilePath)
+
+	patterns := directory.Ignore
+	if len(patterns) == 0 {
+		patterns = defaultIgnore
+	}
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return fileIgnored
+		}
+	}
+
+	if directory.DoneMarker != "" {
+		if strings.HasSuffix(name, directory.DoneMarker) {
+			return fileIgnored
+		}
+		if _, err := os.Stat(fullFilePath + directory.DoneMarker); err != nil {
+			logger.Debug("Waiting for done marker of file: %s", fullFilePath)
+			return fileNotReady
+		}
+	}
+
+	info, err := os.Stat(fullFilePath)
+	if err != nil || info.IsDir() {
+		return fileIgnored
+	}
+
+	if age := time.Since(info.ModTime()); age < time.Duration(directory.MinAge)*time.Second {
+		logger.Debug("File is only %s old, waiting: %s", age.Round(time.Second), fullFilePath)
+		return fileNotReady
+	}
+
+	if directory.StableSize {
+		state := fileState{size: info.Size(), modTime: info.ModTime()}
+		previous, seen := lastScan.Swap(fullFilePath, state)
+		if !seen || previous.(fileState) != state {
+			logger.Debug("File changed since the last scan, waiting: %s", fullFilePath)
+			return fileNotReady
+		}
+	}
+
+	return fileReady
+}
+
+// forgetFile removes the state kept for a file once it has been handled.
+func forgetFile(fullFilePath string, directory config.DirectoryConfig) {
+	lastScan.Delete(fullFilePath)
+
+	if directory.DoneMarker != "" {
+		marker := fullFilePath + directory.DoneMarker
+		if err := os.Remove(marker); err != nil && !os.IsNotExist(err) {
+			logger.Error("Error removing done marker: %s Error: %s", marker, err)
+		}
+	}
+}
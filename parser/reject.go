@@ -0,0 +1,135 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/google/uuid"
+)
+
+// rowReader reads CSV rows like csv.Reader, and keeps the raw text and line
+// number of the last row so a rejected row can be stored as it was read.
+type rowReader struct {
+	*csv.Reader
+	source   *captureReader
+	fileName string
+	fileType string
+	// line is the first line of the last row, next the line after it
+	line int64
+	next int64
+	raw  []byte
+}
+
+// captureReader keeps the bytes read from the input that the CSV reader has
+// not returned as a row yet.
+type captureReader struct {
+	reader io.Reader
+	buffer []byte
+	// offset is the position of the first byte of buffer in the input
+	offset int64
+}
+
+func (c *captureReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.buffer = append(c.buffer, p[:n]...)
+	return n, err
+}
+
+func newRowReader(reader io.Reader, fileName string, fileType string) *rowReader {
+	source := &captureReader{reader: reader}
+	return &rowReader{
+		Reader:   csv.NewReader(source),
+		source:   source,
+		fileName: fileName,
+		fileType: fileType,
+		next:     1,
+	}
+}
+
+// Read returns the next row. Errors are those of csv.Reader.
+func (r *rowReader) Read() ([]string, error) {
+	record, err := r.Reader.Read()
+
+	end := r.Reader.InputOffset() - r.source.offset
+	if end > int64(len(r.source.buffer)) {
+		end = int64(len(r.source.buffer))
+	}
+	r.raw = append(r.raw[:0], r.source.buffer[:end]...)
+	r.source.buffer = append(r.source.buffer[:0], r.source.buffer[end:]...)
+	r.source.offset += end
+
+	// Empty lines before the row are skipped by the CSV reader
+	for len(r.raw) > 0 && (r.raw[0] == '\n' || r.raw[0] == '\r') {
+		if r.raw[0] == '\n' {
+			r.next++
+		}
+		r.raw = r.raw[1:]
+	}
+	r.line = r.next
+	r.next += int64(bytes.Count(r.raw, []byte{'\n'}))
+	r.raw = bytes.TrimRight(r.raw, "\r\n")
+
+	return record, err
+}
+
+// Reject returns the last row as a rejected record.
+func (r *rowReader) Reject(reason models.RejectReason, err error) *models.RejectedRecord {
+	return newRejectedRecord(r.fileName, r.fileType, r.line, string(r.raw), reason, err)
+}
+
+// newRejectedRecord returns a rejected record. line is the position of the
+// record in its file, starting at 1.
+func newRejectedRecord(fileName string, fileType string, line int64, raw string, reason models.RejectReason, err error) *models.RejectedRecord {
+	rejected := &models.RejectedRecord{
+		ID:        uuid.New().String(),
+		File:      fileName,
+		Line:      line,
+		Type:      fileType,
+		Reason:    reason,
+		Raw:       raw,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err != nil {
+		message := err.Error()
+		rejected.Error = &message
+	}
+	return rejected
+}
+
+// csvRejectReason returns the reason for a row the CSV reader failed to read.
+func csvRejectReason(err error) models.RejectReason {
+	if errors.Is(err, csv.ErrFieldCount) {
+		return models.RejectFieldCount
+	}
+	return models.RejectMalformedRow
+}
+
+// newRejectWriter returns a batch writer for the rejected rows of a file.
+func newRejectWriter(db *database.DataService, ingestion *Ingestion) *batchWriter[*models.RejectedRecord] {
+	return newBatchWriter(db.BatchSize(), func(records []*models.RejectedRecord) error {
+		for _, record := range records {
+			record.IngestedFileId = &ingestion.ID
+		}
+		return db.CreateRejectedRecords(records)
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+// FileResult summarises what happened to a single input file.
+type FileResult struct {
+	File     string
+	Records  int
+	Rejected int
+	// Skipped is set when the file was not recognised or was already being
+	// parsed, and was left where it is.
+	Skipped bool
+	// Duplicate is set when the ledger shows the same content was already
+	// ingested.
+	Duplicate bool
+	// NotReady is set when the readiness policy of the directory says the
+	// file may still be written to. It is checked again later.
+	NotReady bool
+	Err      error
+}
+
+// Failed reports whether the file could not be parsed or written.
+func (r FileResult) Failed() bool {
+	return r.Err != nil
+}
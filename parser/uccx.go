@@ -0,0 +1,91 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+func ParseUccxCDRs(inputFile string, db *database.DataService, ingestion *Ingestion) FileResult {
+
+	result := FileResult{File: inputFile}
+
+	err := readMembers(inputFile, func(name string, reader io.Reader) error {
+		logger.Info("Found UCCX file: %s", name)
+		contacts := newBatchWriter(db.BatchSize(), func(details []*models.UccxContactCallDetail) error {
+			for _, detail := range details {
+				detail.IngestedFileId = &ingestion.ID
+			}
+			return db.CreateUccxContactCallDetails(details)
+		})
+		agents := newBatchWriter(db.BatchSize(), func(details []*models.UccxAgentConnectionDetail) error {
+			for _, detail := range details {
+				detail.IngestedFileId = &ingestion.ID
+			}
+			return db.CreateUccxAgentConnectionDetails(details)
+		})
+		rejects := newRejectWriter(db, ingestion)
+		rejected, err := ParseUccxFile(reader, name, func(detail *models.UccxContactCallDetail) error {
+			if ingestion.AlreadyWritten() {
+				return nil
+			}
+			return contacts.Add(detail)
+		}, func(detail *models.UccxAgentConnectionDetail) error {
+			if ingestion.AlreadyWritten() {
+				return nil
+			}
+			return agents.Add(detail)
+		}, rejects.Add)
+		contactsWritten, contactErr := contacts.Close()
+		agentsWritten, agentErr := agents.Close()
+		_, rejectErr := rejects.Close()
+		written := contactsWritten + agentsWritten
+		result.Rejected += rejected
+		result.Records += written
+		for _, writeErr := range []error{contactErr, agentErr} {
+			if writeErr != nil {
+				logger.Error("Error while writing to database: %s", writeErr.Error())
+				return writeErr
+			}
+		}
+		if rejectErr != nil {
+			logger.Error("Error while writing rejected records to database: %s", rejectErr.Error())
+			return rejectErr
+		}
+		if err != nil {
+			return err
+		}
+
+		if written == 0 {
+			logger.Info("No records found in file: %s", name)
+		} else {
+			logger.Info("Successfully wrote %s records to database from %s", strconv.Itoa(written), name)
+		}
+		return nil
+	})
+
+	if err != nil {
+		logger.Error("Error parsing file: %s Error: %s", inputFile, err)
+		result.Err = err
+	}
+
+	return result
+}
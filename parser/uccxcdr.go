@@ -0,0 +1,114 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// errNoUccxHeader is returned for files whose first row is not the header of
+// a ContactCallDetail or AgentConnectionDetail export.
+var errNoUccxHeader = errors.New("no ContactCallDetail or AgentConnectionDetail header")
+
+// ParseUccxFile reads a ContactCallDetail or AgentConnectionDetail export. The
+// table is told from the header: only AgentConnectionDetail has a resourceID
+// column, and only ContactCallDetail a contactDisposition column.
+func ParseUccxFile(reader io.Reader, fileName string, addContact func(*models.UccxContactCallDetail) error, addAgent func(*models.UccxAgentConnectionDetail) error, reject func(*models.RejectedRecord) error) (int, error) {
+
+	logger.Info("Parsing file: %s", fileName)
+
+	var contactColumns columnMap[models.RawUccxContactCallDetail]
+	var agentColumns columnMap[models.RawUccxAgentConnectionDetail]
+	rejected := 0
+
+	csvReader := newRowReader(reader, fileName, "uccx")
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			logger.Info("Finished parsing file: %s", fileName)
+			break
+		}
+		if err != nil {
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if contactColumns == nil && agentColumns == nil {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			switch {
+			case !containsColumn(record, "sessionID"):
+				return rejected, errNoUccxHeader
+			case containsColumn(record, "resourceID"):
+				csvReader.fileType = "uccx_agent_connection_detail"
+				agentColumns = newColumnMap(uccxAgentColumns, record, fileName)
+			case containsColumn(record, "contactDisposition"):
+				csvReader.fileType = "uccx_contact_call_detail"
+				contactColumns = newColumnMap(uccxContactColumns, record, fileName)
+			default:
+				return rejected, errNoUccxHeader
+			}
+			continue
+		}
+
+		if agentColumns != nil {
+			raw := &models.RawUccxAgentConnectionDetail{
+				Filename: &fileName,
+			}
+			agentColumns.fill(raw, record)
+
+			detail, err := raw.Parse(fileName)
+			if err != nil {
+				rejected++
+				if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+					return rejected, err
+				}
+				continue
+			}
+			if err := addAgent(detail); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		raw := &models.RawUccxContactCallDetail{
+			Filename: &fileName,
+		}
+		contactColumns.fill(raw, record)
+
+		detail, err := raw.Parse(fileName)
+		if err != nil {
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+		if err := addContact(detail); err != nil {
+			return rejected, err
+		}
+	}
+
+	return rejected, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import "github.com/eds-ch/Go-CDR-V/models"
+
+// uccxContactColumns are the columns of the ContactCallDetail table, as named in
+// the UCCX database. Exports are read by header name, in any order.
+var uccxContactColumns = []column[models.RawUccxContactCallDetail]{
+	{"sessionID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Sessionid = value }},
+	{"sessionSeqNum", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Sessionseqnum = value }},
+	{"nodeID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Nodeid = value }},
+	{"profileID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Profileid = value }},
+	{"contactType", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Contacttype = value }},
+	{"contactDisposition", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Contactdisposition = value }},
+	{"dispositionReason", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Dispositionreason = value }},
+	{"originatorType", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Originatortype = value }},
+	{"originatorID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Originatorid = value }},
+	{"originatorDN", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Originatordn = value }},
+	{"destinationType", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Destinationtype = value }},
+	{"destinationID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Destinationid = value }},
+	{"destinationDN", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Destinationdn = value }},
+	{"startDateTime", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Startdatetime = value }},
+	{"endDateTime", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Enddatetime = value }},
+	{"gmtOffset", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Gmtoffset = value }},
+	{"calledNumber", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Callednumber = value }},
+	{"origCalledNumber", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Origcallednumber = value }},
+	{"applicationTaskID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Applicationtaskid = value }},
+	{"applicationID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Applicationid = value }},
+	{"applicationName", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Applicationname = value }},
+	{"connectTime", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Connecttime = value }},
+	{"customVariable1", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable1 = value }},
+	{"customVariable2", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable2 = value }},
+	{"customVariable3", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable3 = value }},
+	{"customVariable4", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable4 = value }},
+	{"customVariable5", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable5 = value }},
+	{"customVariable6", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable6 = value }},
+	{"customVariable7", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable7 = value }},
+	{"customVariable8", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable8 = value }},
+	{"customVariable9", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable9 = value }},
+	{"customVariable10", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Customvariable10 = value }},
+	{"accountNumber", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Accountnumber = value }},
+	{"callerEnteredDigits", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Callerentereddigits = value }},
+	{"badCallTag", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Badcalltag = value }},
+	{"transfer", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Transfer = value }},
+	{"redirect", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Redirect = value }},
+	{"conference", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Conference = value }},
+	{"flowout", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Flowout = value }},
+	{"metServiceLevel", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Metservicelevel = value }},
+	{"campaignID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Campaignid = value }},
+	{"origProtocolCallRef", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Origprotocolcallref = value }},
+	{"destProtocolCallRef", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Destprotocolcallref = value }},
+	{"callResult", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Callresult = value }},
+	{"dialingListID", func(raw *models.RawUccxContactCallDetail, value *string) { raw.Dialinglistid = value }},
+}
+
+// uccxAgentColumns are the columns of the AgentConnectionDetail table.
+var uccxAgentColumns = []column[models.RawUccxAgentConnectionDetail]{
+	{"sessionID", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Sessionid = value }},
+	{"sessionSeqNum", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Sessionseqnum = value }},
+	{"nodeID", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Nodeid = value }},
+	{"profileID", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Profileid = value }},
+	{"resourceID", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Resourceid = value }},
+	{"startDateTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Startdatetime = value }},
+	{"endDateTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Enddatetime = value }},
+	{"qIndex", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Qindex = value }},
+	{"gmtOffset", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Gmtoffset = value }},
+	{"ringTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Ringtime = value }},
+	{"talkTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Talktime = value }},
+	{"holdTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Holdtime = value }},
+	{"workTime", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Worktime = value }},
+	{"callWrapupData", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Callwrapupdata = value }},
+	{"callResult", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Callresult = value }},
+	{"dialingListID", func(raw *models.RawUccxAgentConnectionDetail, value *string) { raw.Dialinglistid = value }},
+}
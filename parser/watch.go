@@ -0,0 +1,160 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchMaxRetries is the number of times a file that is not ready is checked
+// again after its last event. After that it is left to the periodic rescan,
+// e.g. when its done marker never arrives.
+const watchMaxRetries = 10
+
+// WatchDirectories watches the input directories and parses every file once
+// it has not been written to for delay. It returns once the watches are set
+// up; events are handled in the background.
+func WatchDirectories(directories []config.DirectoryConfig, delay time.Duration, db *database.DataService) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[string]*watchedDirectory)
+	for _, directory := range directories {
+		input := filepath.Clean(directory.Input)
+		if err := watcher.Add(input); err != nil {
+			// Files in this directory are still picked up by the periodic rescan
+			logger.Error("Error watching directory: %s Error: %s", input, err)
+			continue
+		}
+		watched[input] = newWatchedDirectory(directory)
+		logger.Info("Watching directory: %s", input)
+	}
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+					continue
+				}
+				wd, ok := watched[filepath.Dir(event.Name)]
+				if !ok {
+					continue
+				}
+				fullFilePath := event.Name
+				if marker := wd.directory.DoneMarker; marker != "" && strings.HasSuffix(fullFilePath, marker) {
+					// A done marker makes the file it belongs to ready
+					fullFilePath = strings.TrimSuffix(fullFilePath, marker)
+				}
+				wd.resetRetries(fullFilePath)
+				wd.schedule(fullFilePath, delay, db)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Error("Error watching directories: %s", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchedDirectory debounces the events of a single input directory and
+// limits the number of files parsed at the same time to its Workers.
+type watchedDirectory struct {
+	directory config.DirectoryConfig
+	sem       chan struct{}
+
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	retries map[string]int
+}
+
+func newWatchedDirectory(directory config.DirectoryConfig) *watchedDirectory {
+	workers := directory.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	return &watchedDirectory{
+		directory: directory,
+		sem:       make(chan struct{}, workers),
+		timers:    make(map[string]*time.Timer),
+		retries:   make(map[string]int),
+	}
+}
+
+// schedule (re)starts the quiet period of a file. Every write pushes the
+// deadline back, so a file is only parsed once the writer has finished.
+func (wd *watchedDirectory) schedule(fullFilePath string, delay time.Duration, db *database.DataService) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	if timer, ok := wd.timers[fullFilePath]; ok {
+		timer.Reset(delay)
+		return
+	}
+
+	wd.timers[fullFilePath] = time.AfterFunc(delay, func() {
+		wd.mu.Lock()
+		delete(wd.timers, fullFilePath)
+		wd.mu.Unlock()
+
+		wd.sem <- struct{}{}
+		defer func() { <-wd.sem }()
+
+		if info, err := os.Stat(fullFilePath); err != nil || info.IsDir() {
+			return
+		}
+		if result := ParseFile(fullFilePath, wd.directory, db); !result.NotReady {
+			wd.resetRetries(fullFilePath)
+			return
+		}
+
+		wd.mu.Lock()
+		wd.retries[fullFilePath]++
+		retries := wd.retries[fullFilePath]
+		wd.mu.Unlock()
+		if retries > watchMaxRetries {
+			logger.Info("File is still not ready after %d checks, leaving it to the periodic rescan: %s", watchMaxRetries, fullFilePath)
+			wd.resetRetries(fullFilePath)
+			return
+		}
+		// Check again once the file had time to settle
+		wd.schedule(fullFilePath, delay, db)
+	})
+}
+
+// resetRetries forgets the checks of a file, a new event starts them over.
+func (wd *watchedDirectory) resetRetries(fullFilePath string) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	delete(wd.retries, fullFilePath)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+)
+
+// errNoWebexHeader is returned for files whose first row is not the header of
+// a Detailed Call History export.
+var errNoWebexHeader = errors.New("no Start time column in the header")
+
+func ParseWebexCDRFile(reader io.Reader, fileName string, add func(*models.WebexCDR) error, reject func(*models.RejectedRecord) error) (int, error) {
+
+	logger.Info("Parsing file: %s", fileName)
+
+	var columns columnMap[models.RawWebexCDR]
+	rejected := 0
+
+	csvReader := newRowReader(reader, fileName, "webex_cdr")
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			logger.Info("Finished parsing file: %s", fileName)
+			break
+		}
+		if err != nil {
+			logger.Error("Error parsing file: %s Error: %s", fileName, err)
+			rejected++
+			if err := reject(csvReader.Reject(csvRejectReason(err), err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+
+		if columns == nil {
+			// Exports saved by spreadsheet tools start with a byte order mark
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if !containsColumn(record, "Start time") {
+				return rejected, errNoWebexHeader
+			}
+			columns = newColumnMap(webexColumns, record, fileName)
+			continue
+		}
+
+		raw := &models.RawWebexCDR{
+			Filename: &fileName,
+		}
+		columns.fill(raw, record)
+
+		cdr, err := raw.Parse(fileName)
+		if err != nil {
+			rejected++
+			if err := reject(csvReader.Reject(models.RejectInvalidValue, err)); err != nil {
+				return rejected, err
+			}
+			continue
+		}
+		if err := add(cdr); err != nil {
+			return rejected, err
+		}
+	}
+
+	return rejected, nil
+}
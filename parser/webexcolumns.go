@@ -0,0 +1,89 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package parser
+
+import "github.com/eds-ch/Go-CDR-V/models"
+
+// webexColumns are the columns of the Webex Calling Detailed Call History by
+// their header name. Control Hub adds columns over time, so they are always
+// read by name.
+var webexColumns = []column[models.RawWebexCDR]{
+	{"Start time", func(raw *models.RawWebexCDR, value *string) { raw.Starttime = value }},
+	{"Answer time", func(raw *models.RawWebexCDR, value *string) { raw.Answertime = value }},
+	{"Duration", func(raw *models.RawWebexCDR, value *string) { raw.Duration = value }},
+	{"Report ID", func(raw *models.RawWebexCDR, value *string) { raw.Reportid = value }},
+	{"Report time", func(raw *models.RawWebexCDR, value *string) { raw.Reporttime = value }},
+	{"Call ID", func(raw *models.RawWebexCDR, value *string) { raw.Callid = value }},
+	{"Correlation ID", func(raw *models.RawWebexCDR, value *string) { raw.Correlationid = value }},
+	{"Local call ID", func(raw *models.RawWebexCDR, value *string) { raw.Localcallid = value }},
+	{"Remote call ID", func(raw *models.RawWebexCDR, value *string) { raw.Remotecallid = value }},
+	{"Network call ID", func(raw *models.RawWebexCDR, value *string) { raw.Networkcallid = value }},
+	{"Related call ID", func(raw *models.RawWebexCDR, value *string) { raw.Relatedcallid = value }},
+	{"Transfer related call ID", func(raw *models.RawWebexCDR, value *string) { raw.Transferrelatedcallid = value }},
+	{"User", func(raw *models.RawWebexCDR, value *string) { raw.User = value }},
+	{"User type", func(raw *models.RawWebexCDR, value *string) { raw.Usertype = value }},
+	{"User UUID", func(raw *models.RawWebexCDR, value *string) { raw.Useruuid = value }},
+	{"Calling line ID", func(raw *models.RawWebexCDR, value *string) { raw.Callinglineid = value }},
+	{"Called line ID", func(raw *models.RawWebexCDR, value *string) { raw.Calledlineid = value }},
+	{"Calling number", func(raw *models.RawWebexCDR, value *string) { raw.Callingnumber = value }},
+	{"Called number", func(raw *models.RawWebexCDR, value *string) { raw.Callednumber = value }},
+	{"Dialed digits", func(raw *models.RawWebexCDR, value *string) { raw.Dialeddigits = value }},
+	{"Caller ID number", func(raw *models.RawWebexCDR, value *string) { raw.Calleridnumber = value }},
+	{"Redirecting number", func(raw *models.RawWebexCDR, value *string) { raw.Redirectingnumber = value }},
+	{"Direction", func(raw *models.RawWebexCDR, value *string) { raw.Direction = value }},
+	{"Call type", func(raw *models.RawWebexCDR, value *string) { raw.Calltype = value }},
+	{"Client type", func(raw *models.RawWebexCDR, value *string) { raw.Clienttype = value }},
+	{"Client version", func(raw *models.RawWebexCDR, value *string) { raw.Clientversion = value }},
+	{"Sub client type", func(raw *models.RawWebexCDR, value *string) { raw.Subclienttype = value }},
+	{"Device MAC", func(raw *models.RawWebexCDR, value *string) { raw.Devicemac = value }},
+	{"Model", func(raw *models.RawWebexCDR, value *string) { raw.Model = value }},
+	{"OS type", func(raw *models.RawWebexCDR, value *string) { raw.Ostype = value }},
+	{"Location", func(raw *models.RawWebexCDR, value *string) { raw.Location = value }},
+	{"Location UUID", func(raw *models.RawWebexCDR, value *string) { raw.Locationuuid = value }},
+	{"Site UUID", func(raw *models.RawWebexCDR, value *string) { raw.Siteuuid = value }},
+	{"Org UUID", func(raw *models.RawWebexCDR, value *string) { raw.Orguuid = value }},
+	{"Department ID", func(raw *models.RawWebexCDR, value *string) { raw.Departmentid = value }},
+	{"Answered", func(raw *models.RawWebexCDR, value *string) { raw.Answered = value }},
+	{"Answer indicator", func(raw *models.RawWebexCDR, value *string) { raw.Answerindicator = value }},
+	{"Original reason", func(raw *models.RawWebexCDR, value *string) { raw.Originalreason = value }},
+	{"Redirect reason", func(raw *models.RawWebexCDR, value *string) { raw.Redirectreason = value }},
+	{"Related reason", func(raw *models.RawWebexCDR, value *string) { raw.Relatedreason = value }},
+	{"Inbound trunk", func(raw *models.RawWebexCDR, value *string) { raw.Inboundtrunk = value }},
+	{"Outbound trunk", func(raw *models.RawWebexCDR, value *string) { raw.Outboundtrunk = value }},
+	{"Route group", func(raw *models.RawWebexCDR, value *string) { raw.Routegroup = value }},
+	{"International country", func(raw *models.RawWebexCDR, value *string) { raw.Internationalcountry = value }},
+	{"Authorization code", func(raw *models.RawWebexCDR, value *string) { raw.Authorizationcode = value }},
+	{"Call transfer time", func(raw *models.RawWebexCDR, value *string) { raw.Calltransfertime = value }},
+	{"Ring duration", func(raw *models.RawWebexCDR, value *string) { raw.Ringduration = value }},
+	{"Hold duration", func(raw *models.RawWebexCDR, value *string) { raw.Holdduration = value }},
+	{"Wait time", func(raw *models.RawWebexCDR, value *string) { raw.Waittime = value }},
+	{"Queue type", func(raw *models.RawWebexCDR, value *string) { raw.Queuetype = value }},
+	{"Release time", func(raw *models.RawWebexCDR, value *string) { raw.Releasetime = value }},
+	{"Releasing party", func(raw *models.RawWebexCDR, value *string) { raw.Releasingparty = value }},
+	{"Release cause", func(raw *models.RawWebexCDR, value *string) { raw.Releasecause = value }},
+	{"Call outcome", func(raw *models.RawWebexCDR, value *string) { raw.Calloutcome = value }},
+	{"Call outcome reason", func(raw *models.RawWebexCDR, value *string) { raw.Calloutcomereason = value }},
+	{"Local SessionID", func(raw *models.RawWebexCDR, value *string) { raw.Localsessionid = value }},
+	{"Remote SessionID", func(raw *models.RawWebexCDR, value *string) { raw.Remotesessionid = value }},
+	{"Final local SessionID", func(raw *models.RawWebexCDR, value *string) { raw.Finallocalsessionid = value }},
+	{"Final remote SessionID", func(raw *models.RawWebexCDR, value *string) { raw.Finalremotesessionid = value }},
+	{"PSTN vendor name", func(raw *models.RawWebexCDR, value *string) { raw.Pstnvendorname = value }},
+	{"PSTN legal entity", func(raw *models.RawWebexCDR, value *string) { raw.Pstnlegalentity = value }},
+	{"PSTN vendor Org ID", func(raw *models.RawWebexCDR, value *string) { raw.Pstnvendororgid = value }},
+	{"PSTN provider ID", func(raw *models.RawWebexCDR, value *string) { raw.Pstnproviderid = value }},
+	{"Public calling IP address", func(raw *models.RawWebexCDR, value *string) { raw.Publiccallingipaddress = value }},
+	{"Public called IP address", func(raw *models.RawWebexCDR, value *string) { raw.Publiccalledipaddress = value }},
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package poller
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/eds-ch/Go-CDR-V/parser"
+)
+
+// expresswayPoller writes the calls of the call history that ended since the
+// previous poll. The history holds the last calls of the Expressway, so every
+// poll returns calls that were already written.
+type expresswayPoller struct {
+	config config.ExpresswayPollerConfig
+	db     *database.DataService
+	client *http.Client
+	// last is the latest end time written, and written the keys of the calls
+	// that ended at that time
+	last    int64
+	written map[string]bool
+}
+
+func startExpressway(expresswayConfig config.ExpresswayPollerConfig, db *database.DataService) error {
+	p := &expresswayPoller{
+		config:  expresswayConfig,
+		db:      db,
+		written: map[string]bool{},
+		client: &http.Client{
+			Timeout: time.Minute,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: expresswayConfig.InsecureSkipVerify},
+			},
+		},
+	}
+
+	latest, err := db.GetLatestExpresswayCalls(expresswayConfig.Name)
+	if err != nil {
+		return err
+	}
+	for i := range latest {
+		p.remember(&latest[i])
+	}
+
+	logger.Info("Polling Expressway %s every %d seconds: %s", expresswayConfig.Name, expresswayConfig.Interval, expresswayConfig.URL)
+	go func() {
+		ticker := time.NewTicker(time.Duration(expresswayConfig.Interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			if err := p.poll(); err != nil {
+				logger.Error("Error polling Expressway %s: %s", expresswayConfig.Name, err)
+			}
+			<-ticker.C
+		}
+	}()
+	return nil
+}
+
+// poll reads the call history once and writes the calls that are new.
+func (p *expresswayPoller) poll() error {
+	request, err := http.NewRequest(http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json, text/csv")
+	if p.config.Username != "" {
+		request.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	var calls []*models.ExpresswayCall
+	rejected, err := parser.ParseExpresswayCallFile(response.Body, p.config.Name, func(call *models.ExpresswayCall) error {
+		if p.isNew(call) {
+			call.Filename = nil
+			call.System = &p.config.Name
+			calls = append(calls, call)
+		}
+		return nil
+	}, func(record *models.RejectedRecord) error {
+		logger.Warn("Skipping call %d of Expressway %s: %s", record.Line, p.config.Name, *record.Error)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.db.CreateExpresswayCalls(calls); err != nil {
+		return err
+	}
+	for _, call := range calls {
+		p.remember(call)
+	}
+
+	logger.Info("Wrote %s new calls from Expressway %s, %d rejected", strconv.Itoa(len(calls)), p.config.Name, rejected)
+	return nil
+}
+
+// isNew reports whether a call has ended and was not written before. Calls
+// that are still active are written by a later poll.
+func (p *expresswayPoller) isNew(call *models.ExpresswayCall) bool {
+	if call.EndTime == nil {
+		return false
+	}
+	return *call.EndTime > p.last || *call.EndTime == p.last && !p.written[expresswayCallKey(call)]
+}
+
+func (p *expresswayPoller) remember(call *models.ExpresswayCall) {
+	if call.EndTime == nil || *call.EndTime < p.last {
+		return
+	}
+	if *call.EndTime > p.last {
+		p.last = *call.EndTime
+		p.written = map[string]bool{}
+	}
+	p.written[expresswayCallKey(call)] = true
+}
+
+// expresswayCallKey identifies a call in the history by its serial number,
+// or its tag and start time on releases without serial numbers.
+func expresswayCallKey(call *models.ExpresswayCall) string {
+	if call.SerialNumber != nil {
+		return *call.SerialNumber
+	}
+	key := ""
+	if call.Tag != nil {
+		key = *call.Tag
+	}
+	if call.StartTime != nil {
+		key += "/" + strconv.FormatInt(*call.StartTime, 10)
+	}
+	return key
+}
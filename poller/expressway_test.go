@@ -0,0 +1,120 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package poller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"go.uber.org/zap"
+)
+
+// expresswayHistoryJSON is the history of the first poll: a and b have
+// ended, c is still active.
+const expresswayHistoryJSON = `{"calls": [
+	{"SerialNumber": "a", "State": "Disconnected", "StartTime": "2025-03-01 10:00:00", "EndTime": "2025-03-01 10:01:00"},
+	{"SerialNumber": "b", "State": "Disconnected", "StartTime": "2025-03-01 10:00:30", "EndTime": "2025-03-01 10:02:00"},
+	{"SerialNumber": "c", "State": "Active", "StartTime": "2025-03-01 10:01:30"}
+]}`
+
+// expresswayHistoryCSV is the history of the second poll: a and b were
+// written before, c has ended since and d ended at the same time as b.
+const expresswayHistoryCSV = "Serial number,State,Start time,End time\r\n" +
+	"a,Disconnected,2025-03-01 10:00:00,2025-03-01 10:01:00\r\n" +
+	"b,Disconnected,2025-03-01 10:00:30,2025-03-01 10:02:00\r\n" +
+	"c,Disconnected,2025-03-01 10:01:30,2025-03-01 10:03:00\r\n" +
+	"d,Disconnected,2025-03-01 10:01:45,2025-03-01 10:02:00\r\n"
+
+func TestExpresswayPollWritesEndedCallsOnce(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	histories := []string{expresswayHistoryJSON, expresswayHistoryCSV, expresswayHistoryCSV}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, histories[requests])
+		requests++
+	}))
+	defer server.Close()
+
+	db := database.InitDB(config.DatabaseConfig{
+		Driver:      "sqlite",
+		Path:        filepath.Join(t.TempDir(), "go-cdr.sqlite"),
+		AutoMigrate: true,
+	})
+	pollerConfig := config.ExpresswayPollerConfig{
+		Name:     "expressway1",
+		URL:      server.URL,
+		Username: "admin",
+		Password: "secret",
+	}
+	p := &expresswayPoller{config: pollerConfig, db: db, client: server.Client(), written: map[string]bool{}}
+
+	if err := p.poll(); err != nil {
+		t.Fatalf("first poll: %s", err)
+	}
+	assertExpresswayCalls(t, db, "a", "b")
+
+	if err := p.poll(); err != nil {
+		t.Fatalf("second poll: %s", err)
+	}
+	assertExpresswayCalls(t, db, "a", "b", "c", "d")
+
+	// A restarted poller picks up the watermark from the database
+	latest, err := db.GetLatestExpresswayCalls(pollerConfig.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted := &expresswayPoller{config: pollerConfig, db: db, client: server.Client(), written: map[string]bool{}}
+	for i := range latest {
+		restarted.remember(&latest[i])
+	}
+	if err := restarted.poll(); err != nil {
+		t.Fatalf("poll after restart: %s", err)
+	}
+	assertExpresswayCalls(t, db, "a", "b", "c", "d")
+}
+
+func assertExpresswayCalls(t *testing.T, db *database.DataService, want ...string) {
+	t.Helper()
+
+	var calls []models.ExpresswayCall
+	if err := db.Session.Find(&calls).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, call := range calls {
+		if call.System == nil || *call.System != "expressway1" {
+			t.Errorf("call %s has system %v, want expressway1", *call.SerialNumber, call.System)
+		}
+		got = append(got, *call.SerialNumber)
+	}
+	sort.Strings(got)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("written calls are %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package poller pulls call history from the APIs of systems that cannot
+// push their records, and writes it to the database on an interval.
+package poller
+
+import (
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// Start starts the configured pollers in the background.
+func Start(pollerConfig *config.PollerConfig, db *database.DataService) {
+	names := map[string]bool{}
+	for _, expressway := range pollerConfig.Expressway {
+		if expressway.Name == "" || expressway.URL == "" {
+			logger.Error("Expressway poller needs a name and a url, ignoring: %s", expressway.Name)
+			continue
+		}
+		if names[expressway.Name] {
+			logger.Error("Expressway poller %s is configured more than once, ignoring", expressway.Name)
+			continue
+		}
+		names[expressway.Name] = true
+		if err := startExpressway(expressway, db); err != nil {
+			logger.Error("Error starting Expressway poller %s: %s", expressway.Name, err)
+		}
+	}
+}
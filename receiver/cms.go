@@ -0,0 +1,201 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/eds-ch/Go-CDR-V/parser"
+)
+
+// cmsMaxBodySize limits a single post. CMS sends batches of a few hundred
+// records at most.
+const cmsMaxBodySize = 16 * 1024 * 1024
+
+// cmsServer correlates the records CMS posts into calls and call legs. Calls
+// and legs that have not ended are kept in memory, and are read back from
+// the database after a restart.
+type cmsServer struct {
+	db *database.DataService
+	// mutex serialises posts, records of one call may arrive from several
+	// connections
+	mutex sync.Mutex
+	calls map[string]*models.CMSCall
+	legs  map[string]*models.CMSCallLeg
+}
+
+// startCMS starts the HTTP receiver CMS posts CDRs to.
+func startCMS(cmsConfig *config.CMSConfig, db *database.DataService) error {
+	listener, err := net.Listen("tcp", cmsConfig.Listen)
+	if err != nil {
+		return err
+	}
+
+	server := &cmsServer{
+		db:    db,
+		calls: map[string]*models.CMSCall{},
+		legs:  map[string]*models.CMSCallLeg{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(cmsConfig.Path, server.handle)
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if cmsConfig.CertFile != "" && cmsConfig.KeyFile != "" {
+		logger.Info("CMS CDR receiver listening on https://%s%s", cmsConfig.Listen, cmsConfig.Path)
+		go func() {
+			logger.Error("CMS CDR receiver stopped: %s", httpServer.ServeTLS(listener, cmsConfig.CertFile, cmsConfig.KeyFile))
+		}()
+		return nil
+	}
+
+	logger.Info("CMS CDR receiver listening on http://%s%s", cmsConfig.Listen, cmsConfig.Path)
+	go func() {
+		logger.Error("CMS CDR receiver stopped: %s", httpServer.Serve(listener))
+	}()
+	return nil
+}
+
+// handle answers a post once its records are written. CMS keeps the records
+// and posts them again when it gets an error.
+func (s *cmsServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records, err := parser.ParseCMSRecords(http.MaxBytesReader(w, r.Body, cmsMaxBodySize))
+	if err != nil {
+		logger.Error("Error parsing CMS records from %s: %s", r.RemoteAddr, err)
+		http.Error(w, "invalid records", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.apply(records); err != nil {
+		logger.Error("Error writing CMS records from %s: %s", r.RemoteAddr, err)
+		http.Error(w, "failed to write records", http.StatusInternalServerError)
+		return
+	}
+	logger.Debug("Received %d CMS records from %s", len(records.Records), r.RemoteAddr)
+	w.WriteHeader(http.StatusOK)
+}
+
+// apply adds the records of a post to their calls and legs and writes the
+// ones that changed.
+func (s *cmsServer) apply(records *parser.CMSRecords) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var calls []*models.CMSCall
+	var legs []*models.CMSCallLeg
+	changedCalls := map[string]bool{}
+	changedLegs := map[string]bool{}
+	var endedCalls, endedLegs []string
+
+	for i := range records.Records {
+		record := &records.Records[i]
+		switch {
+		case record.Call != nil && (record.Type == models.CMSCallStart || record.Type == models.CMSCallEnd):
+			call, err := s.call(record.Call.ID)
+			if err != nil {
+				return err
+			}
+			record.ApplyCall(call, records.Session)
+			if !changedCalls[call.ID] {
+				changedCalls[call.ID] = true
+				calls = append(calls, call)
+			}
+			if record.Type == models.CMSCallEnd {
+				endedCalls = append(endedCalls, call.ID)
+			}
+
+		case record.CallLeg != nil && (record.Type == models.CMSCallLegStart || record.Type == models.CMSCallLegUpdate || record.Type == models.CMSCallLegEnd):
+			leg, err := s.leg(record.CallLeg.ID)
+			if err != nil {
+				return err
+			}
+			record.ApplyCallLeg(leg, records.Session)
+			if !changedLegs[leg.ID] {
+				changedLegs[leg.ID] = true
+				legs = append(legs, leg)
+			}
+			if record.Type == models.CMSCallLegEnd {
+				endedLegs = append(endedLegs, leg.ID)
+			}
+
+		default:
+			// Recording, streaming and other records are not stored
+			logger.Debug("Ignoring CMS %s record", record.Type)
+		}
+	}
+
+	if err := s.db.SaveCMSCalls(calls); err != nil {
+		return err
+	}
+	if err := s.db.SaveCMSCallLegs(legs); err != nil {
+		return err
+	}
+
+	for _, id := range endedCalls {
+		delete(s.calls, id)
+	}
+	for _, id := range endedLegs {
+		delete(s.legs, id)
+	}
+	return nil
+}
+
+// call returns the call a record belongs to, a new one if it is not known.
+func (s *cmsServer) call(id string) (*models.CMSCall, error) {
+	if call, ok := s.calls[id]; ok {
+		return call, nil
+	}
+	call, err := s.db.GetCMSCall(id)
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		call = &models.CMSCall{ID: id}
+	}
+	s.calls[id] = call
+	return call, nil
+}
+
+// leg returns the call leg a record belongs to, a new one if it is not known.
+func (s *cmsServer) leg(id string) (*models.CMSCallLeg, error) {
+	if leg, ok := s.legs[id]; ok {
+		return leg, nil
+	}
+	leg, err := s.db.GetCMSCallLeg(id)
+	if err != nil {
+		return nil, err
+	}
+	if leg == nil {
+		leg = &models.CMSCallLeg{ID: id}
+	}
+	s.legs[id] = leg
+	return leg, nil
+}
@@ -0,0 +1,468 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// The FTP receiver implements the part of RFC 959 that upload clients such
+// as CUBE gw-accounting and the CUCM billing application server use: login,
+// passive and active data connections, STOR, listing and renames.
+
+const (
+	ftpIdleTimeout = 5 * time.Minute
+	ftpDataTimeout = 30 * time.Second
+	// ftpMaxLineLength is the longest command line read, longer lines are
+	// answered with 500 without being buffered
+	ftpMaxLineLength = 4096
+)
+
+type ftpServer struct {
+	config   *config.FTPConfig
+	accounts accounts
+	minPort  int
+	maxPort  int
+}
+
+func startFTP(ftpConfig *config.FTPConfig, accs accounts) error {
+	minPort, maxPort, err := parsePortRange(ftpConfig.PassivePorts)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", ftpConfig.Listen)
+	if err != nil {
+		return err
+	}
+	logger.Info("FTP receiver listening on %s", listener.Addr())
+
+	server := &ftpServer{config: ftpConfig, accounts: accs, minPort: minPort, maxPort: maxPort}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				logger.Error("FTP receiver stopped: %s", err)
+				return
+			}
+			session := &ftpSession{server: server, conn: conn, reader: bufio.NewReaderSize(conn, ftpMaxLineLength)}
+			go session.serve()
+		}
+	}()
+	return nil
+}
+
+// parsePortRange parses a passive port range such as "30000-30009". An empty
+// range lets the operating system pick a port.
+func parsePortRange(portRange string) (int, int, error) {
+	if portRange == "" {
+		return 0, 0, nil
+	}
+	first, last, found := strings.Cut(portRange, "-")
+	if !found {
+		last = first
+	}
+	minPort, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid passive port range: %s", portRange)
+	}
+	maxPort, err := strconv.Atoi(strings.TrimSpace(last))
+	if err != nil || maxPort < minPort || minPort < 1 || maxPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid passive port range: %s", portRange)
+	}
+	return minPort, maxPort, nil
+}
+
+type ftpSession struct {
+	server     *ftpServer
+	conn       net.Conn
+	reader     *bufio.Reader
+	username   string
+	input      string
+	passive    net.Listener
+	active     string
+	renameFrom string
+}
+
+func (s *ftpSession) reply(code int, message string) {
+	fmt.Fprintf(s.conn, "%d %s\r\n", code, message)
+}
+
+func (s *ftpSession) serve() {
+	defer s.conn.Close()
+	defer s.closePassive()
+
+	s.reply(220, "go-cdr FTP receiver ready")
+
+	for {
+		s.conn.SetReadDeadline(time.Now().Add(ftpIdleTimeout))
+		line, err := s.readLine()
+		if err == errFTPLineTooLong {
+			s.reply(500, "Command line too long.")
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		command = strings.ToUpper(command)
+
+		if s.input == "" {
+			switch command {
+			case "USER", "PASS", "QUIT", "SYST", "FEAT", "NOOP":
+			default:
+				s.reply(530, "Not logged in.")
+				continue
+			}
+		}
+
+		switch command {
+		case "USER":
+			s.username = argument
+			s.input = ""
+			s.reply(331, "Password required.")
+		case "PASS":
+			input, ok := s.server.accounts.authenticate(s.username, argument)
+			if !ok {
+				logger.Error("FTP login failed for user %s from %s", s.username, s.conn.RemoteAddr())
+				s.reply(530, "Login incorrect.")
+				continue
+			}
+			s.input = input
+			logger.Debug("FTP login for user %s from %s", s.username, s.conn.RemoteAddr())
+			s.reply(230, "Logged in.")
+		case "SYST":
+			s.reply(215, "UNIX Type: L8")
+		case "FEAT":
+			fmt.Fprint(s.conn, "211-Features:\r\n EPSV\r\n PASV\r\n SIZE\r\n UTF8\r\n211 End\r\n")
+		case "OPTS", "NOOP", "ALLO":
+			s.reply(200, "OK.")
+		case "PWD", "XPWD":
+			s.reply(257, "\"/\" is the current directory.")
+		case "CWD", "XCWD", "CDUP", "XCUP":
+			// The input directory is presented as a single flat directory
+			s.reply(250, "Directory changed.")
+		case "MKD", "XMKD":
+			s.reply(257, "\"/\" directory exists.")
+		case "TYPE":
+			s.reply(200, "Type set.")
+		case "MODE":
+			s.replyIf(strings.EqualFold(argument, "S"), 200, "Mode set.", 504, "Only stream mode is supported.")
+		case "STRU":
+			s.replyIf(strings.EqualFold(argument, "F"), 200, "Structure set.", 504, "Only file structure is supported.")
+		case "PASV":
+			s.handlePassive(false)
+		case "EPSV":
+			s.handlePassive(true)
+		case "PORT":
+			s.handleActive(parsePortArgument(argument))
+		case "EPRT":
+			s.handleActive(parseEprtArgument(argument))
+		case "STOR":
+			s.handleStore(argument)
+		case "LIST", "NLST":
+			s.handleList(command == "NLST")
+		case "SIZE":
+			s.handleSize(argument)
+		case "DELE":
+			s.handleDelete(argument)
+		case "RNFR":
+			s.handleRenameFrom(argument)
+		case "RNTO":
+			s.handleRenameTo(argument)
+		case "ABOR":
+			s.reply(226, "No transfer to abort.")
+		case "QUIT":
+			s.reply(221, "Goodbye.")
+			return
+		default:
+			s.reply(502, "Command not implemented.")
+		}
+	}
+}
+
+var errFTPLineTooLong = errors.New("command line too long")
+
+// readLine reads a command line of at most ftpMaxLineLength bytes. The rest
+// of a longer line is read and discarded, and errFTPLineTooLong returned.
+func (s *ftpSession) readLine() (string, error) {
+	line, err := s.reader.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return string(line), err
+	}
+	for err == bufio.ErrBufferFull {
+		_, err = s.reader.ReadSlice('\n')
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", errFTPLineTooLong
+}
+
+func (s *ftpSession) replyIf(ok bool, okCode int, okMessage string, failCode int, failMessage string) {
+	if ok {
+		s.reply(okCode, okMessage)
+		return
+	}
+	s.reply(failCode, failMessage)
+}
+
+func (s *ftpSession) closePassive() {
+	if s.passive != nil {
+		s.passive.Close()
+		s.passive = nil
+	}
+}
+
+func (s *ftpSession) handlePassive(extended bool) {
+	s.closePassive()
+	s.active = ""
+
+	host, _, _ := net.SplitHostPort(s.conn.LocalAddr().String())
+	listener, err := s.listenPassive(host)
+	if err != nil {
+		logger.Error("Error opening FTP passive port: %s", err)
+		s.reply(425, "Can't open passive connection.")
+		return
+	}
+	s.passive = listener
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if extended {
+		s.reply(229, fmt.Sprintf("Entering Extended Passive Mode (|||%d|)", port))
+		return
+	}
+
+	if s.server.config.PublicIP != "" {
+		host = s.server.config.PublicIP
+	}
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		s.closePassive()
+		s.reply(425, "PASV needs IPv4, use EPSV.")
+		return
+	}
+	s.reply(227, fmt.Sprintf("Entering Passive Mode (%d,%d,%d,%d,%d,%d)", ip[0], ip[1], ip[2], ip[3], port>>8, port&0xff))
+}
+
+func (s *ftpSession) listenPassive(host string) (net.Listener, error) {
+	if s.server.minPort == 0 {
+		return net.Listen("tcp", net.JoinHostPort(host, "0"))
+	}
+	var err error
+	for port := s.server.minPort; port <= s.server.maxPort; port++ {
+		var listener net.Listener
+		listener, err = net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err == nil {
+			return listener, nil
+		}
+	}
+	return nil, err
+}
+
+func (s *ftpSession) handleActive(address string, err error) {
+	if err != nil {
+		s.reply(501, "Invalid address.")
+		return
+	}
+	// Only connect back to the client itself, never to a third host
+	clientHost, _, _ := net.SplitHostPort(s.conn.RemoteAddr().String())
+	host, _, _ := net.SplitHostPort(address)
+	if !net.ParseIP(host).Equal(net.ParseIP(clientHost)) {
+		s.reply(504, "Data connection must go to the client address.")
+		return
+	}
+	s.closePassive()
+	s.active = address
+	s.reply(200, "Active mode set.")
+}
+
+// parsePortArgument parses the h1,h2,h3,h4,p1,p2 argument of PORT.
+func parsePortArgument(argument string) (string, error) {
+	parts := strings.Split(argument, ",")
+	if len(parts) != 6 {
+		return "", errors.New("invalid PORT argument")
+	}
+	var numbers [6]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || number < 0 || number > 255 {
+			return "", errors.New("invalid PORT argument")
+		}
+		numbers[i] = number
+	}
+	host := fmt.Sprintf("%d.%d.%d.%d", numbers[0], numbers[1], numbers[2], numbers[3])
+	return net.JoinHostPort(host, strconv.Itoa(numbers[4]<<8+numbers[5])), nil
+}
+
+// parseEprtArgument parses the |protocol|address|port| argument of EPRT.
+func parseEprtArgument(argument string) (string, error) {
+	if len(argument) < 2 {
+		return "", errors.New("invalid EPRT argument")
+	}
+	parts := strings.Split(argument[1:], argument[:1])
+	if len(parts) < 3 || net.ParseIP(parts[1]) == nil {
+		return "", errors.New("invalid EPRT argument")
+	}
+	if _, err := strconv.Atoi(parts[2]); err != nil {
+		return "", errors.New("invalid EPRT argument")
+	}
+	return net.JoinHostPort(parts[1], parts[2]), nil
+}
+
+func (s *ftpSession) openData() (net.Conn, error) {
+	if s.passive != nil {
+		defer s.closePassive()
+		s.passive.(*net.TCPListener).SetDeadline(time.Now().Add(ftpDataTimeout))
+		// Like active connections, the data connection must come from the
+		// client itself, so no other host can take over the transfer
+		clientHost, _, _ := net.SplitHostPort(s.conn.RemoteAddr().String())
+		for {
+			conn, err := s.passive.Accept()
+			if err != nil {
+				return nil, err
+			}
+			host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+			if net.ParseIP(host).Equal(net.ParseIP(clientHost)) {
+				return conn, nil
+			}
+			logger.Error("Rejecting FTP data connection from %s for client %s", conn.RemoteAddr(), s.conn.RemoteAddr())
+			conn.Close()
+		}
+	}
+	if s.active != "" {
+		address := s.active
+		s.active = ""
+		return net.DialTimeout("tcp", address, ftpDataTimeout)
+	}
+	return nil, errors.New("no data connection requested")
+}
+
+func (s *ftpSession) handleStore(path string) {
+	file, err := newUpload(s.input, path)
+	if err != nil {
+		logger.Error("Error receiving file: %s Error: %s", path, err)
+		s.reply(553, "File name not allowed.")
+		return
+	}
+
+	s.reply(150, "Ok to send data.")
+	data, err := s.openData()
+	if err != nil {
+		file.Abort()
+		file.Close()
+		s.reply(425, "Can't open data connection.")
+		return
+	}
+
+	_, err = io.Copy(file, data)
+	data.Close()
+	if err != nil {
+		file.Abort()
+	}
+	if err := file.Close(); err != nil {
+		s.reply(451, "Transfer failed.")
+		return
+	}
+	s.reply(226, "Transfer complete.")
+}
+
+func (s *ftpSession) handleList(namesOnly bool) {
+	files, err := listFiles(s.input)
+	if err != nil {
+		s.reply(550, "Can't list directory.")
+		return
+	}
+
+	s.reply(150, "Here comes the directory listing.")
+	data, err := s.openData()
+	if err != nil {
+		s.reply(425, "Can't open data connection.")
+		return
+	}
+	for _, file := range files {
+		if namesOnly {
+			fmt.Fprintf(data, "%s\r\n", file.Name())
+			continue
+		}
+		fmt.Fprintf(data, "-rw-r--r-- 1 go-cdr go-cdr %d %s %s\r\n", file.Size(), file.ModTime().Format("Jan _2 15:04"), file.Name())
+	}
+	data.Close()
+	s.reply(226, "Directory send OK.")
+}
+
+func (s *ftpSession) handleSize(path string) {
+	name, err := uploadName(path)
+	if err != nil {
+		s.reply(550, "No such file.")
+		return
+	}
+	info, err := os.Stat(filepath.Join(s.input, name))
+	if err != nil || info.IsDir() {
+		s.reply(550, "No such file.")
+		return
+	}
+	s.reply(213, strconv.FormatInt(info.Size(), 10))
+}
+
+func (s *ftpSession) handleDelete(path string) {
+	name, err := uploadName(path)
+	if err == nil {
+		err = os.Remove(filepath.Join(s.input, name))
+	}
+	s.replyIf(err == nil, 250, "File deleted.", 550, "Delete failed.")
+}
+
+func (s *ftpSession) handleRenameFrom(path string) {
+	name, err := uploadName(path)
+	if err != nil {
+		s.reply(550, "No such file.")
+		return
+	}
+	if _, err := os.Stat(filepath.Join(s.input, name)); err != nil {
+		s.reply(550, "No such file.")
+		return
+	}
+	s.renameFrom = name
+	s.reply(350, "Ready for RNTO.")
+}
+
+func (s *ftpSession) handleRenameTo(path string) {
+	from := s.renameFrom
+	s.renameFrom = ""
+	if from == "" {
+		s.reply(503, "RNFR required first.")
+		return
+	}
+	to, err := uploadName(path)
+	if err == nil {
+		err = os.Rename(filepath.Join(s.input, from), filepath.Join(s.input, to))
+	}
+	s.replyIf(err == nil, 250, "Rename successful.", 550, "Rename failed.")
+}
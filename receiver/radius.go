@@ -0,0 +1,476 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/eds-ch/Go-CDR-V/parser"
+)
+
+const (
+	radiusAccountingRequest  = 4
+	radiusAccountingResponse = 5
+
+	radiusHeaderLength = 20
+	radiusMaxLength    = 4096
+
+	// Standard attributes, RFC 2865 and RFC 2866
+	attributeUserName          = 1
+	attributeNasIPAddress      = 4
+	attributeVendorSpecific    = 26
+	attributeCalledStationId   = 30
+	attributeCallingStationId  = 31
+	attributeAcctStatusType    = 40
+	attributeAcctInputOctets   = 42
+	attributeAcctOutputOctets  = 43
+	attributeAcctSessionId     = 44
+	attributeAcctInputPackets  = 47
+	attributeAcctOutputPackets = 48
+	attributeEventTimestamp    = 55
+
+	vendorCisco = 9
+
+	acctStatusStart = 1
+	acctStatusStop  = 2
+
+	// radiusDuplicateWindow is how long a request is remembered, so a
+	// retransmission whose response got lost is answered again instead of
+	// being written twice.
+	radiusDuplicateWindow = 30 * time.Second
+)
+
+// radiusStandardAttributes are the standard attributes that carry CDR fields,
+// by the AVPair name of the field.
+var radiusStandardAttributes = map[byte]string{
+	attributeUserName:          "username",
+	attributeCalledStationId:   "dnis",
+	attributeCallingStationId:  "clid",
+	attributeAcctInputOctets:   "bytes-in",
+	attributeAcctOutputOctets:  "bytes-out",
+	attributeAcctInputPackets:  "paks-in",
+	attributeAcctOutputPackets: "paks-out",
+}
+
+var errRadiusAuthenticator = errors.New("request authenticator does not match the shared secret")
+
+type radiusClient struct {
+	network *net.IPNet
+	secret  []byte
+	name    string
+}
+
+// radiusRequest is an accounting request waiting for its CDR to be written.
+type radiusRequest struct {
+	addr     *net.UDPAddr
+	key      string
+	response []byte
+	cdr      *models.CubeCDR
+}
+
+type radiusServer struct {
+	conn    *net.UDPConn
+	clients []radiusClient
+	db      *database.DataService
+	pending chan *radiusRequest
+
+	mu sync.Mutex
+	// answered holds the responses sent recently, by request
+	answered map[string]answeredRequest
+	// writing holds the requests waiting to be written
+	writing map[string]bool
+}
+
+type answeredRequest struct {
+	response []byte
+	at       time.Time
+}
+
+// startRadius starts the RADIUS accounting server. Start and Stop records of
+// CUBE gateways are written as CUBE CDRs, and a record is only acknowledged
+// once it has been written, so the gateway sends it again after a failure.
+func startRadius(radiusConfig *config.RadiusConfig, db *database.DataService) error {
+	clients, err := newRadiusClients(radiusConfig.Clients)
+	if err != nil {
+		return err
+	}
+	if len(clients) == 0 {
+		return errors.New("no RADIUS clients configured")
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", radiusConfig.Listen)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &radiusServer{
+		conn:     conn,
+		clients:  clients,
+		db:       db,
+		pending:  make(chan *radiusRequest, db.BatchSize()),
+		answered: map[string]answeredRequest{},
+		writing:  map[string]bool{},
+	}
+
+	logger.Info("RADIUS accounting receiver listening on %s", conn.LocalAddr())
+	go server.write()
+	go server.serve()
+	return nil
+}
+
+func newRadiusClients(clientConfigs []config.RadiusClient) ([]radiusClient, error) {
+	var clients []radiusClient
+	for _, clientConfig := range clientConfigs {
+		address := clientConfig.Address
+		if !strings.Contains(address, "/") {
+			if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+				address += "/128"
+			} else {
+				address += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RADIUS client address %s: %w", clientConfig.Address, err)
+		}
+		if clientConfig.Secret == "" {
+			return nil, fmt.Errorf("RADIUS client %s has no secret", clientConfig.Address)
+		}
+		clients = append(clients, radiusClient{network: network, secret: []byte(clientConfig.Secret), name: clientConfig.Name})
+	}
+	return clients, nil
+}
+
+func (s *radiusServer) client(ip net.IP) *radiusClient {
+	for i := range s.clients {
+		if s.clients[i].network.Contains(ip) {
+			return &s.clients[i]
+		}
+	}
+	return nil
+}
+
+func (s *radiusServer) serve() {
+	buffer := make([]byte, radiusMaxLength)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buffer)
+		if err != nil {
+			logger.Error("Error reading RADIUS packet: %s", err)
+			continue
+		}
+		packet := make([]byte, n)
+		copy(packet, buffer[:n])
+		s.handle(packet, addr)
+	}
+}
+
+func (s *radiusServer) handle(packet []byte, addr *net.UDPAddr) {
+	// A malformed packet must not stop the receiver. The packet is not
+	// answered, so the gateway sends it again.
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Dropping RADIUS packet from %s after panic: %v", addr.IP, r)
+		}
+	}()
+
+	client := s.client(addr.IP)
+	if client == nil {
+		logger.Error("Dropping RADIUS packet from unknown client %s", addr.IP)
+		return
+	}
+
+	if len(packet) < radiusHeaderLength || packet[0] != radiusAccountingRequest {
+		logger.Debug("Dropping RADIUS packet that is not an accounting request from %s", addr.IP)
+		return
+	}
+	length := int(binary.BigEndian.Uint16(packet[2:4]))
+	if length < radiusHeaderLength || length > len(packet) {
+		logger.Error("Dropping RADIUS packet with invalid length from %s", addr.IP)
+		return
+	}
+	packet = packet[:length]
+
+	if err := verifyAccountingRequest(packet, client.secret); err != nil {
+		logger.Error("Dropping RADIUS packet from %s: %s", addr.IP, err)
+		return
+	}
+
+	key := fmt.Sprintf("%s/%d/%x", addr, packet[1], packet[4:20])
+	if response, ok := s.answer(key); ok {
+		logger.Debug("Answering retransmitted RADIUS request from %s", addr.IP)
+		s.send(response, addr)
+		return
+	}
+
+	response := accountingResponse(packet, client.secret)
+
+	attributes, status, nasIP, err := decodeAccountingRequest(packet)
+	if err != nil {
+		logger.Error("Dropping RADIUS packet from %s: %s", addr.IP, err)
+		return
+	}
+
+	// Interim updates and Accounting-On/Off are acknowledged but are not CDRs
+	if status != acctStatusStart && status != acctStatusStop {
+		s.remember(key, response)
+		s.send(response, addr)
+		return
+	}
+
+	hostname := client.name
+	if hostname == "" {
+		hostname = nasIP
+	}
+	if hostname == "" {
+		hostname = addr.IP.String()
+	}
+
+	cdr, err := parser.ParseCubeAttributes(attributes, hostname)
+	if err != nil {
+		// The record can never be parsed, so it is acknowledged to keep the
+		// gateway from sending it again
+		logger.Error("Error parsing RADIUS accounting record from %s: %s", hostname, err)
+		s.remember(key, response)
+		s.send(response, addr)
+		return
+	}
+
+	// A retransmission of a request that is still waiting to be written is
+	// ignored, it is answered once the first one is stored
+	if !s.claim(key) {
+		logger.Debug("Ignoring retransmitted RADIUS request from %s that is being written", addr.IP)
+		return
+	}
+	// A full queue must not block reading, the record is dropped unanswered
+	// and the gateway sends it again
+	select {
+	case s.pending <- &radiusRequest{addr: addr, key: key, response: response, cdr: cdr}:
+	default:
+		s.release(key)
+		logger.Error("Dropping RADIUS accounting record from %s, too many records are waiting to be written", hostname)
+	}
+}
+
+// write writes the pending records in batches. Under load a batch holds every
+// record that arrived while the previous one was being written.
+func (s *radiusServer) write() {
+	size := s.db.BatchSize()
+	for first := range s.pending {
+		requests := []*radiusRequest{first}
+	collect:
+		for len(requests) < size {
+			select {
+			case request := <-s.pending:
+				requests = append(requests, request)
+			default:
+				break collect
+			}
+		}
+
+		cdrs := make([]*models.CubeCDR, len(requests))
+		for i, request := range requests {
+			cdrs[i] = request.cdr
+		}
+		if err := s.db.CreateCubeCDRs(cdrs); err != nil {
+			// Not answering makes the gateways send the records again
+			logger.Error("Error writing %d RADIUS accounting records to database: %s", len(cdrs), err)
+			for _, request := range requests {
+				s.release(request.key)
+			}
+			continue
+		}
+		logger.Debug("Wrote %d RADIUS accounting records to database", len(cdrs))
+
+		// The records are stored, so the gateways are answered before the
+		// calls are paired
+		for _, request := range requests {
+			s.remember(request.key, request.response)
+			s.send(request.response, request.addr)
+		}
+
+		if err := parser.PairCubeCalls(s.db, parser.CubeCallKeys(cdrs)); err != nil {
+			logger.Error("Error pairing CUBE calls of RADIUS accounting records: %s", err)
+		}
+	}
+}
+
+func (s *radiusServer) answer(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	answered, ok := s.answered[key]
+	if !ok || time.Since(answered.at) > radiusDuplicateWindow {
+		return nil, false
+	}
+	return answered.response, true
+}
+
+func (s *radiusServer) remember(key string, response []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, answered := range s.answered {
+		if now.Sub(answered.at) > radiusDuplicateWindow {
+			delete(s.answered, k)
+		}
+	}
+	s.answered[key] = answeredRequest{response: response, at: now}
+	delete(s.writing, key)
+}
+
+// claim marks a request as waiting to be written. It returns false if the
+// request already is.
+func (s *radiusServer) claim(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writing[key] {
+		return false
+	}
+	s.writing[key] = true
+	return true
+}
+
+func (s *radiusServer) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.writing, key)
+}
+
+func (s *radiusServer) send(response []byte, addr *net.UDPAddr) {
+	if _, err := s.conn.WriteToUDP(response, addr); err != nil {
+		logger.Error("Error sending RADIUS accounting response to %s: %s", addr, err)
+	}
+}
+
+// verifyAccountingRequest checks the request authenticator, the MD5 of the
+// packet with a zero authenticator followed by the shared secret (RFC 2866).
+func verifyAccountingRequest(packet []byte, secret []byte) error {
+	hash := md5.New()
+	hash.Write(packet[:4])
+	hash.Write(make([]byte, 16))
+	hash.Write(packet[radiusHeaderLength:])
+	hash.Write(secret)
+	if !bytes.Equal(hash.Sum(nil), packet[4:20]) {
+		return errRadiusAuthenticator
+	}
+	return nil
+}
+
+// accountingResponse builds the Accounting-Response to a request.
+func accountingResponse(request []byte, secret []byte) []byte {
+	response := make([]byte, radiusHeaderLength)
+	response[0] = radiusAccountingResponse
+	response[1] = request[1]
+	binary.BigEndian.PutUint16(response[2:4], radiusHeaderLength)
+
+	hash := md5.New()
+	hash.Write(response[:4])
+	hash.Write(request[4:20])
+	hash.Write(secret)
+	copy(response[4:20], hash.Sum(nil))
+	return response
+}
+
+// decodeAccountingRequest returns the CDR fields of a request by AVPair name,
+// its Acct-Status-Type and its NAS-IP-Address.
+func decodeAccountingRequest(packet []byte) (map[string]string, uint32, string, error) {
+	attributes := map[string]string{}
+	var status uint32
+	var nasIP string
+
+	data := packet[radiusHeaderLength:]
+	for len(data) > 0 {
+		if len(data) < 2 || data[1] < 2 || int(data[1]) > len(data) {
+			return nil, 0, "", errors.New("malformed attribute")
+		}
+		attributeType, value := data[0], data[2:data[1]]
+		data = data[data[1]:]
+
+		switch attributeType {
+		case attributeVendorSpecific:
+			if len(value) < 4 || binary.BigEndian.Uint32(value[:4]) != vendorCisco {
+				continue
+			}
+			decodeCiscoAttributes(value[4:], attributes)
+		case attributeAcctStatusType:
+			if len(value) == 4 {
+				status = binary.BigEndian.Uint32(value)
+			}
+		case attributeNasIPAddress:
+			if len(value) == 4 {
+				nasIP = net.IP(value).String()
+			}
+		case attributeAcctSessionId:
+			// Cisco sends the call id as hexadecimal, gw-accounting files
+			// have it in decimal
+			sessionId := string(value)
+			if callId, err := strconv.ParseUint(sessionId, 16, 64); err == nil {
+				sessionId = strconv.FormatUint(callId, 10)
+			}
+			attributes[parser.AttributeCallId] = sessionId
+		case attributeEventTimestamp:
+			if len(value) == 4 {
+				attributes[parser.AttributeRecordTimestamp] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(value)), 10)
+			}
+		case attributeAcctInputOctets, attributeAcctOutputOctets, attributeAcctInputPackets, attributeAcctOutputPackets:
+			if len(value) == 4 {
+				attributes[radiusStandardAttributes[attributeType]] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(value)), 10)
+			}
+		default:
+			if name, ok := radiusStandardAttributes[attributeType]; ok {
+				attributes[name] = string(value)
+			}
+		}
+	}
+
+	if status != 0 {
+		attributes[parser.AttributeCdrType] = strconv.FormatUint(uint64(status), 10)
+	}
+	return attributes, status, nasIP, nil
+}
+
+// decodeCiscoAttributes reads the sub attributes of a Cisco Vendor-Specific
+// attribute. Both Cisco-AVPair and the dedicated h323 attributes carry a
+// "name=value" string.
+func decodeCiscoAttributes(data []byte, attributes map[string]string) {
+	for len(data) >= 2 {
+		length := int(data[1])
+		if length < 2 || length > len(data) {
+			return
+		}
+		name, value, found := strings.Cut(string(data[2:length]), "=")
+		if found {
+			attributes[strings.TrimSpace(name)] = value
+		}
+		data = data[length:]
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/parser"
+	"go.uber.org/zap"
+)
+
+var radiusTestSecret = []byte("secret")
+
+// radiusAttribute encodes a standard attribute.
+func radiusAttribute(attributeType byte, value []byte) []byte {
+	return append([]byte{attributeType, byte(len(value) + 2)}, value...)
+}
+
+func radiusUint32(value uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, value)
+}
+
+// radiusVendorAttribute encodes a Vendor-Specific attribute with a single
+// "name=value" sub attribute.
+func radiusVendorAttribute(vendor uint32, avPair string) []byte {
+	value := radiusUint32(vendor)
+	value = append(value, 1, byte(len(avPair)+2))
+	value = append(value, avPair...)
+	return radiusAttribute(attributeVendorSpecific, value)
+}
+
+// radiusTestRequest builds an accounting request signed with secret.
+func radiusTestRequest(identifier byte, secret []byte, attributes ...[]byte) []byte {
+	packet := make([]byte, radiusHeaderLength)
+	packet[0] = radiusAccountingRequest
+	packet[1] = identifier
+	for _, attribute := range attributes {
+		packet = append(packet, attribute...)
+	}
+	binary.BigEndian.PutUint16(packet[2:4], uint16(len(packet)))
+
+	hash := md5.New()
+	hash.Write(packet[:4])
+	hash.Write(make([]byte, 16))
+	hash.Write(packet[radiusHeaderLength:])
+	hash.Write(secret)
+	copy(packet[4:20], hash.Sum(nil))
+	return packet
+}
+
+func TestDecodeAccountingRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes [][]byte
+		want       map[string]string
+		status     uint32
+		nasIP      string
+		wantErr    bool
+	}{
+		{
+			name: "stop record",
+			attributes: [][]byte{
+				radiusAttribute(attributeAcctStatusType, radiusUint32(acctStatusStop)),
+				radiusAttribute(attributeNasIPAddress, []byte{192, 0, 2, 1}),
+				radiusAttribute(attributeCallingStationId, []byte("0441234567")),
+				radiusAttribute(attributeCalledStationId, []byte("0447654321")),
+				radiusAttribute(attributeAcctInputOctets, radiusUint32(1200)),
+				radiusAttribute(attributeEventTimestamp, radiusUint32(1700000000)),
+			},
+			want: map[string]string{
+				parser.AttributeCdrType:         "2",
+				parser.AttributeRecordTimestamp: "1700000000",
+				"clid":                          "0441234567",
+				"dnis":                          "0447654321",
+				"bytes-in":                      "1200",
+			},
+			status: acctStatusStop,
+			nasIP:  "192.0.2.1",
+		},
+		{
+			name: "hexadecimal session id",
+			attributes: [][]byte{
+				radiusAttribute(attributeAcctSessionId, []byte("1A")),
+			},
+			want: map[string]string{parser.AttributeCallId: "26"},
+		},
+		{
+			name: "session id that is not hexadecimal",
+			attributes: [][]byte{
+				radiusAttribute(attributeAcctSessionId, []byte("call-1")),
+			},
+			want: map[string]string{parser.AttributeCallId: "call-1"},
+		},
+		{
+			name: "Cisco AVPair",
+			attributes: [][]byte{
+				radiusVendorAttribute(vendorCisco, "h323-conf-id=4C2F1B0 A1B2C3D4"),
+				radiusVendorAttribute(vendorCisco, "no-separator"),
+			},
+			want: map[string]string{"h323-conf-id": "4C2F1B0 A1B2C3D4"},
+		},
+		{
+			name: "other vendor",
+			attributes: [][]byte{
+				radiusVendorAttribute(311, "h323-conf-id=1"),
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "status with wrong length",
+			attributes: [][]byte{
+				radiusAttribute(attributeAcctStatusType, []byte{1}),
+			},
+			want: map[string]string{},
+		},
+		{
+			name:       "attribute longer than the packet",
+			attributes: [][]byte{{attributeUserName, 10, 'a'}},
+			wantErr:    true,
+		},
+		{
+			name:       "attribute with zero length",
+			attributes: [][]byte{{attributeUserName, 0}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet := radiusTestRequest(1, radiusTestSecret, tt.attributes...)
+			attributes, status, nasIP, err := decodeAccountingRequest(packet)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", attributes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if status != tt.status || nasIP != tt.nasIP {
+				t.Errorf("got status %d and NAS-IP-Address %q, want %d and %q", status, nasIP, tt.status, tt.nasIP)
+			}
+			if len(attributes) != len(tt.want) {
+				t.Errorf("got attributes %v, want %v", attributes, tt.want)
+			}
+			for name, value := range tt.want {
+				if attributes[name] != value {
+					t.Errorf("attribute %s is %q, want %q", name, attributes[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyAccountingRequest(t *testing.T) {
+	attribute := radiusAttribute(attributeUserName, []byte("gateway"))
+	tampered := radiusTestRequest(1, radiusTestSecret, attribute)
+	tampered[len(tampered)-1] = 'X'
+
+	tests := []struct {
+		name    string
+		packet  []byte
+		wantErr bool
+	}{
+		{"signed with the secret", radiusTestRequest(1, radiusTestSecret, attribute), false},
+		{"signed with another secret", radiusTestRequest(1, []byte("other"), attribute), true},
+		{"changed after signing", tampered, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyAccountingRequest(tt.packet, radiusTestSecret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountingResponse(t *testing.T) {
+	request := radiusTestRequest(42, radiusTestSecret, radiusAttribute(attributeUserName, []byte("gateway")))
+	response := accountingResponse(request, radiusTestSecret)
+
+	if len(response) != radiusHeaderLength || response[0] != radiusAccountingResponse || response[1] != 42 {
+		t.Fatalf("unexpected response header % x", response[:4])
+	}
+	if length := binary.BigEndian.Uint16(response[2:4]); length != radiusHeaderLength {
+		t.Errorf("response length is %d, want %d", length, radiusHeaderLength)
+	}
+
+	// The response authenticator is the MD5 of the response header, the
+	// request authenticator and the secret (RFC 2866)
+	hash := md5.New()
+	hash.Write(response[:4])
+	hash.Write(request[4:20])
+	hash.Write(radiusTestSecret)
+	if want := hash.Sum(nil); string(response[4:20]) != string(want) {
+		t.Errorf("response authenticator is % x, want % x", response[4:20], want)
+	}
+}
+
+func TestRadiusHandleQueuesRequestsOnce(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	s := &radiusServer{
+		clients:  []radiusClient{{network: network, secret: radiusTestSecret}},
+		pending:  make(chan *radiusRequest, 1),
+		answered: map[string]answeredRequest{},
+		writing:  map[string]bool{},
+	}
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 1646}
+	start := func(identifier byte) []byte {
+		return radiusTestRequest(identifier, radiusTestSecret,
+			radiusAttribute(attributeAcctStatusType, radiusUint32(acctStatusStart)),
+			radiusAttribute(attributeAcctSessionId, []byte("1A")))
+	}
+
+	first := start(1)
+	s.handle(first, addr)
+	// The retransmission arrives before the first request is written
+	s.handle(first, addr)
+	if len(s.pending) != 1 {
+		t.Fatalf("%d requests are waiting to be written, want 1", len(s.pending))
+	}
+
+	// The queue is full, so another request is dropped without blocking and
+	// can be sent again
+	s.handle(start(2), addr)
+	if len(s.pending) != 1 {
+		t.Fatalf("%d requests are waiting to be written, want 1", len(s.pending))
+	}
+	request := <-s.pending
+	s.remember(request.key, request.response)
+	s.handle(start(2), addr)
+	if len(s.pending) != 1 {
+		t.Fatalf("dropped request was not queued when sent again")
+	}
+}
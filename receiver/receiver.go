@@ -0,0 +1,210 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+// Package receiver embeds the file transfer servers that CUCM billing
+// servers and CUBE gw-accounting push CDR files to, the RADIUS and syslog
+// servers CUBE gateways can send their records to instead, and the HTTP
+// receiver Cisco Meeting Server posts its CDRs to.
+package receiver
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+)
+
+// uploadDirectory is the directory inside an input directory where uploads
+// are written before they are renamed into place. The parser never looks
+// into sub directories, so it only ever sees complete files.
+const uploadDirectory = ".upload"
+
+var errInvalidName = errors.New("invalid file name")
+
+// account maps the credentials of a directory to its input directory.
+type account struct {
+	password string
+	input    string
+}
+
+type accounts map[string]account
+
+// newAccounts builds the login table from the directories that have a
+// username and password configured.
+func newAccounts(directories []config.DirectoryConfig) accounts {
+	accs := accounts{}
+	for _, directory := range directories {
+		if directory.Username == "" {
+			continue
+		}
+		if directory.Password == "" {
+			logger.Error("Username %s has no password configured, ignoring: %s", directory.Username, directory.Input)
+			continue
+		}
+		if _, exists := accs[directory.Username]; exists {
+			logger.Error("Username %s is configured for more than one directory, ignoring: %s", directory.Username, directory.Input)
+			continue
+		}
+		accs[directory.Username] = account{password: directory.Password, input: directory.Input}
+	}
+	return accs
+}
+
+// authenticate returns the input directory of the user if the password matches.
+func (a accounts) authenticate(username, password string) (string, bool) {
+	acc, ok := a[username]
+	if !ok {
+		return "", false
+	}
+	if subtle.ConstantTimeCompare([]byte(acc.password), []byte(password)) != 1 {
+		return "", false
+	}
+	return acc.input, true
+}
+
+// Start starts the enabled receivers in the background. Every directory with
+// a username becomes a login whose uploads land in that directory's input.
+func Start(receiverConfig *config.ReceiverConfig, directories []config.DirectoryConfig, db *database.DataService) {
+	if receiverConfig.Radius.Enabled {
+		if err := startRadius(receiverConfig.Radius, db); err != nil {
+			logger.Error("Error starting RADIUS receiver: %s", err)
+		}
+	}
+	if receiverConfig.Syslog.Enabled {
+		if err := startSyslog(receiverConfig.Syslog, db); err != nil {
+			logger.Error("Error starting syslog receiver: %s", err)
+		}
+	}
+	if receiverConfig.CMS.Enabled {
+		if err := startCMS(receiverConfig.CMS, db); err != nil {
+			logger.Error("Error starting CMS CDR receiver: %s", err)
+		}
+	}
+
+	if !receiverConfig.SFTP.Enabled && !receiverConfig.FTP.Enabled {
+		return
+	}
+
+	accs := newAccounts(directories)
+	if len(accs) == 0 {
+		logger.Error("A receiver is enabled but no directory has a username configured")
+		return
+	}
+
+	if receiverConfig.SFTP.Enabled {
+		if err := startSFTP(receiverConfig.SFTP, accs); err != nil {
+			logger.Error("Error starting SFTP receiver: %s", err)
+		}
+	}
+	if receiverConfig.FTP.Enabled {
+		if err := startFTP(receiverConfig.FTP, accs); err != nil {
+			logger.Error("Error starting FTP receiver: %s", err)
+		}
+	}
+}
+
+// uploadName returns the file name to store an upload under. Clients only
+// see a flat directory, so any path they send is reduced to its base name.
+func uploadName(path string) (string, error) {
+	name := filepath.Base(filepath.Clean("/" + strings.ReplaceAll(path, "\\", "/")))
+	if name == "/" || name == "." || name == ".." || strings.HasPrefix(name, ".") {
+		return "", errInvalidName
+	}
+	return name, nil
+}
+
+// upload is a file being received. It is written to the upload directory and
+// only renamed into the input directory once the transfer has completed.
+type upload struct {
+	*os.File
+	target string
+	failed bool
+}
+
+func newUpload(input, path string) (*upload, error) {
+	name, err := uploadName(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tempPath := filepath.Join(input, uploadDirectory)
+	if err := os.MkdirAll(tempPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(tempPath, name+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &upload{File: file, target: filepath.Join(input, name)}, nil
+}
+
+// Abort marks the transfer as incomplete, Close then discards the file.
+func (u *upload) Abort() {
+	u.failed = true
+}
+
+// TransferError is called by the SFTP server when the connection drops
+// while the file is still open.
+func (u *upload) TransferError(err error) {
+	u.Abort()
+}
+
+func (u *upload) Close() error {
+	tempFile := u.File.Name()
+	err := u.File.Close()
+	if err == nil && u.failed {
+		err = fmt.Errorf("transfer of %s did not complete", filepath.Base(u.target))
+	}
+	if err != nil {
+		os.Remove(tempFile)
+		logger.Error("Discarded upload: %s Error: %s", u.target, err)
+		return err
+	}
+	if err := os.Rename(tempFile, u.target); err != nil {
+		os.Remove(tempFile)
+		logger.Error("Error moving upload into place: %s Error: %s", u.target, err)
+		return err
+	}
+	logger.Info("Received file: %s", u.target)
+	return nil
+}
+
+// listFiles returns the files waiting in an input directory, which is what
+// clients see when they list the remote directory.
+func listFiles(input string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(input)
+	if err != nil {
+		return nil, err
+	}
+	var files []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+	}
+	return files, nil
+}
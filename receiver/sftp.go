@@ -0,0 +1,241 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+func startSFTP(sftpConfig *config.SFTPConfig, accs accounts) error {
+	signer, err := loadHostKey(sftpConfig.HostKey)
+	if err != nil {
+		return err
+	}
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			input, ok := accs.authenticate(conn.User(), string(password))
+			if !ok {
+				logger.Error("SFTP login failed for user %s from %s", conn.User(), conn.RemoteAddr())
+				return nil, fmt.Errorf("password rejected for %s", conn.User())
+			}
+			return &ssh.Permissions{Extensions: map[string]string{"input": input}}, nil
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", sftpConfig.Listen)
+	if err != nil {
+		return err
+	}
+	logger.Info("SFTP receiver listening on %s", listener.Addr())
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				logger.Error("SFTP receiver stopped: %s", err)
+				return
+			}
+			go serveSSH(conn, serverConfig)
+		}
+	}()
+	return nil
+}
+
+// loadHostKey reads the SSH host key, generating one on first start.
+func loadHostKey(path string) (ssh.Signer, error) {
+	pemBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		block, err := ssh.MarshalPrivateKey(key, "go-cdr")
+		if err != nil {
+			return nil, err
+		}
+		pemBytes = pem.EncodeToMemory(block)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, pemBytes, 0600); err != nil {
+			return nil, err
+		}
+		logger.Info("Generated SFTP host key: %s", path)
+	} else if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(pemBytes)
+}
+
+func serveSSH(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	defer conn.Close()
+
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		logger.Debug("SFTP handshake failed from %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	input := sshConn.Permissions.Extensions["input"]
+	logger.Debug("SFTP login for user %s from %s", sshConn.User(), sshConn.RemoteAddr())
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			logger.Error("Error accepting SFTP channel: %s", err)
+			continue
+		}
+
+		go func() {
+			// Only the sftp subsystem is offered, no shell or exec
+			for req := range channelRequests {
+				if req.Type == "subsystem" && len(req.Payload) > 4 && string(req.Payload[4:]) == "sftp" {
+					req.Reply(true, nil)
+					go serveSFTP(channel, input)
+					continue
+				}
+				req.Reply(false, nil)
+			}
+		}()
+	}
+}
+
+func serveSFTP(channel ssh.Channel, input string) {
+	defer channel.Close()
+
+	handler := &sftpHandler{input: input}
+	server := sftp.NewRequestServer(channel, sftp.Handlers{
+		FileGet:  handler,
+		FilePut:  handler,
+		FileCmd:  handler,
+		FileList: handler,
+	})
+	if err := server.Serve(); err != nil && err != io.EOF {
+		logger.Debug("SFTP session ended: %s", err)
+	}
+	server.Close()
+}
+
+// sftpHandler presents an input directory as the flat root of the session.
+type sftpHandler struct {
+	input string
+}
+
+func (h *sftpHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+func (h *sftpHandler) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	file, err := newUpload(h.input, r.Filepath)
+	if err != nil {
+		logger.Error("Error receiving file: %s Error: %s", r.Filepath, err)
+		return nil, sftp.ErrSSHFxPermissionDenied
+	}
+	return file, nil
+}
+
+func (h *sftpHandler) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat", "Mkdir":
+		// Clients set times and permissions after an upload, and some create
+		// their target directory first. Neither matters here.
+		return nil
+	case "Rename":
+		// Clients that upload under a temporary name rename the file when done
+		from, err := uploadName(r.Filepath)
+		if err != nil {
+			return sftp.ErrSSHFxPermissionDenied
+		}
+		to, err := uploadName(r.Target)
+		if err != nil {
+			return sftp.ErrSSHFxPermissionDenied
+		}
+		return os.Rename(filepath.Join(h.input, from), filepath.Join(h.input, to))
+	case "Remove":
+		name, err := uploadName(r.Filepath)
+		if err != nil {
+			return sftp.ErrSSHFxPermissionDenied
+		}
+		return os.Remove(filepath.Join(h.input, name))
+	default:
+		return sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+func (h *sftpHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	switch r.Method {
+	case "List":
+		files, err := listFiles(h.input)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt(files), nil
+	case "Stat", "Lstat":
+		if filepath.Clean("/"+r.Filepath) == "/" {
+			// The root of the session is the input directory itself
+			info, err := os.Stat(h.input)
+			if err != nil {
+				return nil, err
+			}
+			return listerAt{info}, nil
+		}
+		name, err := uploadName(r.Filepath)
+		if err != nil {
+			return nil, sftp.ErrSSHFxNoSuchFile
+		}
+		info, err := os.Stat(filepath.Join(h.input, name))
+		if err != nil {
+			return nil, sftp.ErrSSHFxNoSuchFile
+		}
+		return listerAt{info}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(list []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(list, l[offset:])
+	if n < len(list) {
+		return n, io.EOF
+	}
+	return n, nil
+}
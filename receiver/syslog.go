@@ -0,0 +1,197 @@
+// Copyright (c) 2025 eds-ch
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <https://www.gnu.org/licenses/>.
+
+package receiver
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eds-ch/Go-CDR-V/config"
+	"github.com/eds-ch/Go-CDR-V/database"
+	"github.com/eds-ch/Go-CDR-V/logger"
+	"github.com/eds-ch/Go-CDR-V/models"
+	"github.com/eds-ch/Go-CDR-V/parser"
+)
+
+const (
+	syslogMaxLength = 64 * 1024
+	// syslogFlushInterval is the longest a received record waits before it
+	// is written.
+	syslogFlushInterval = time.Second
+)
+
+type syslogServer struct {
+	db      *database.DataService
+	records chan *models.CubeCDR
+}
+
+// startSyslog starts the syslog server on UDP and TCP. Call history messages
+// are written as CUBE CDRs in batches, every other message is ignored.
+func startSyslog(syslogConfig *config.SyslogConfig, db *database.DataService) error {
+	udpAddr, err := net.ResolveUDPAddr("udp", syslogConfig.Listen)
+	if err != nil {
+		return err
+	}
+	udpConn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return err
+	}
+	tcpListener, err := net.Listen("tcp", syslogConfig.Listen)
+	if err != nil {
+		udpConn.Close()
+		return err
+	}
+
+	server := &syslogServer{
+		db:      db,
+		records: make(chan *models.CubeCDR, db.BatchSize()),
+	}
+
+	logger.Info("Syslog receiver listening on %s (udp and tcp)", syslogConfig.Listen)
+	go server.write()
+	go server.serveUDP(udpConn)
+	go server.serveTCP(tcpListener)
+	return nil
+}
+
+func (s *syslogServer) serveUDP(conn *net.UDPConn) {
+	buffer := make([]byte, syslogMaxLength)
+	for {
+		n, addr, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			logger.Error("Error reading syslog message: %s", err)
+			continue
+		}
+		s.handle(string(buffer[:n]), addr.IP.String())
+	}
+}
+
+func (s *syslogServer) serveTCP(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			logger.Error("Error accepting syslog connection: %s", err)
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn reads the messages of a TCP connection, framed either by octet
+// counting (RFC 6587) or by newlines.
+func (s *syslogServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	reader := bufio.NewReaderSize(conn, syslogMaxLength)
+	for {
+		message, err := readSyslogFrame(reader)
+		if message != "" {
+			s.handle(message, host)
+		}
+		if err != nil {
+			if err != io.EOF {
+				logger.Error("Error reading syslog connection from %s: %s", host, err)
+			}
+			return
+		}
+	}
+}
+
+func readSyslogFrame(reader *bufio.Reader) (string, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return "", err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		count, err := reader.ReadString(' ')
+		if err != nil {
+			return "", err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || length > syslogMaxLength {
+			return "", io.ErrUnexpectedEOF
+		}
+		message := make([]byte, length)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			return "", err
+		}
+		return string(message), nil
+	}
+
+	line, err := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n\x00"), err
+}
+
+func (s *syslogServer) handle(message string, host string) {
+	cdr, ok, err := parser.ParseCubeSyslogMessage(message, host)
+	if !ok {
+		return
+	}
+	if err != nil {
+		logger.Error("Error parsing call history from %s: %s", host, err)
+		return
+	}
+	s.records <- cdr
+}
+
+// write writes the records once a batch is full, or a second after the first
+// record of the batch arrived.
+func (s *syslogServer) write() {
+	size := s.db.BatchSize()
+	batch := make([]*models.CubeCDR, 0, size)
+	timer := time.NewTimer(syslogFlushInterval)
+	timer.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		written := batch
+		batch = make([]*models.CubeCDR, 0, size)
+		if err := s.db.CreateCubeCDRs(written); err != nil {
+			logger.Error("Error writing %d syslog call records to database: %s", len(written), err)
+			return
+		}
+		logger.Debug("Wrote %d syslog call records to database", len(written))
+
+		// Pairing comes last, the records are already stored
+		if err := parser.PairCubeCalls(s.db, parser.CubeCallKeys(written)); err != nil {
+			logger.Error("Error pairing CUBE calls of syslog call records: %s", err)
+		}
+	}
+
+	for {
+		select {
+		case record := <-s.records:
+			if len(batch) == 0 {
+				timer.Reset(syslogFlushInterval)
+			}
+			batch = append(batch, record)
+			if len(batch) >= size {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}